@@ -4,32 +4,69 @@ import (
 	"context"
 	"embed"
 	"fmt"
-	"io"
-	"log"
+	"log/slog"
 	"barakaERP/backend/db"
+	apperr "barakaERP/backend/domain/errors"
+	"barakaERP/backend/i18n"
+	"barakaERP/backend/logging"
 	"barakaERP/backend/pdf"
 	"barakaERP/backend/services"
 	"os"
+	"os/user"
 	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 //go:embed backend/db/schema.sql
 var embeddedDBAssets embed.FS
 
+// app.log is rotated past maxLogFileSize bytes, keeping logBackups older files
+const (
+	maxLogFileSize = 5 << 20
+	logBackups     = 3
+)
+
+var (
+	appLog    = logging.For(logging.App)
+	dbLog     = logging.For(logging.DB)
+	clientLog = logging.For(logging.Clients)
+	pdfLog    = logging.For(logging.PDF)
+)
+
 // App struct
 type App struct {
-	ctx            context.Context
-	db             *db.DB
-	repo           *db.Repository
-	clientService  *services.ClientService
-	productService *services.ProductService
-	orderService   *services.OrderService
-	licenseService *services.LicenseService
-	orderPDF       *pdf.OrderPDFGenerator
-	amiriFont      embed.FS
+	ctx             context.Context
+	db              *db.DB
+	repo            *db.Repository
+	clientService   *services.ClientService
+	productService  *services.ProductService
+	orderService    *services.OrderService
+	licenseService  *services.LicenseService
+	auditService    *services.AuditService
+	searchService   *services.SearchService
+	companyService  *services.CompanyService
+	settingsService *services.SettingsService
+	pricingService  *services.PricingService
+	categoryService *services.CategoryService
+	discountService *services.DiscountService
+	authService     *services.AuthService
+	seatService     *services.SeatService
+	orderPDF        *pdf.OrderPDFGenerator
+	docSigner       *pdf.DocumentSigner
+	amiriFont       embed.FS
+	// actor is recorded as the user in the audit log for mutating calls;
+	// bound methods run concurrently, so it is only used under actorMu
+	actor           db.Actor
+	actorMu         sync.RWMutex
+	// locale of messages for this session; the settings' locale when empty.
+	// Also only used under actorMu
+	locale          string
 	// initialization state
-	initialized    bool
-	initErr        error
+	initialized     bool
+	initErr         error
 }
 
 // NewApp creates a new App application struct
@@ -46,66 +83,105 @@ func (a *App) startup(ctx context.Context) {
 	configDir, err := os.UserConfigDir()
 	if err != nil {
 		a.initErr = fmt.Errorf("failed to get user config dir: %w", err)
-		log.Printf(a.initErr.Error())
+		appLog.Error("startup failed", "err", a.initErr)
 		return
 	}
 	appDir := filepath.Join(configDir, "barakaERP")
 
-	// Setup file logging early so we capture any init errors in packaged builds
+	// Setup file logging early so we capture any init errors in packaged builds.
+	// Logs go to both file and console (console may not be visible in packaged
+	// build, but useful in dev).
 	_ = os.MkdirAll(appDir, 0755)
-	logFilePath := filepath.Join(appDir, "app.log")
-	if f, ferr := os.OpenFile(logFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644); ferr == nil {
-		// Log to both file and console (console may not be visible in packaged build, but useful in dev)
-		log.SetOutput(io.MultiWriter(f, os.Stdout))
-		log.Printf("=== App startup at %s ===", filepath.Base(os.Args[0]))
+	if lerr := logging.Setup(filepath.Join(appDir, "app.log"), maxLogFileSize, logBackups); lerr != nil {
+		appLog.Error("file logging unavailable", "err", lerr)
 	}
+	appLog.Info("app startup", "executable", filepath.Base(os.Args[0]))
 
 	// Database file path
 	dbPath := filepath.Join(appDir, "data.db")
-	log.Printf("Connecting to database at: %s", dbPath)
+	appLog.Info("connecting to database", "path", dbPath)
 	database, err := db.Connect(dbPath)
 	if err != nil {
 		a.initErr = fmt.Errorf("failed to connect to database: %w", err)
-		log.Printf(a.initErr.Error())
+		appLog.Error("startup failed", "err", a.initErr)
 		return
 	}
 	a.db = database
-	log.Printf("✓ Database connected successfully!")
 
 	// Run migrations
-	log.Printf("Applying consolidated schema from embedded assets...")
 	if err := a.db.ApplyEmbeddedSchema(embeddedDBAssets, "backend/db/schema.sql"); err != nil {
-		log.Printf("Embedded schema apply failed, trying file system copy: %v", err)
+		appLog.Warn("embedded schema apply failed, trying file system copy", "err", err)
 		if err := a.db.ApplySchemaFile("./backend/db/schema.sql"); err != nil {
 			a.initErr = fmt.Errorf("failed to initialize database schema: %w", err)
-			log.Printf(a.initErr.Error())
+			appLog.Error("startup failed", "err", a.initErr)
 			return
 		}
 	}
-	log.Printf("✓ Database schema ensured successfully!")
-	// Log schema snapshot for diagnostics
-	if tmpRepo := db.NewRepository(a.db); tmpRepo != nil {
-		if schema, derr := tmpRepo.DebugSchema(a.ctx); derr == nil {
+	// Initialize repository and services
+	a.repo = db.NewRepository(a.db)
+	a.settingsService = services.NewSettingsService(a.repo)
+	if _, serr := a.settingsService.Load(a.ctx); serr != nil {
+		// Non-fatal: the defaults apply until settings are saved again
+		appLog.Error("settings load failed", "err", serr)
+	}
+	a.clientService = services.NewClientService(a.repo, a.settingsService)
+	a.productService = services.NewProductService(a.repo, a.settingsService)
+	a.pricingService = services.NewPricingService(a.repo, a.settingsService)
+	a.categoryService = services.NewCategoryService(a.repo)
+	a.discountService = services.NewDiscountService(a.repo, a.settingsService)
+	a.orderService = services.NewOrderService(a.repo, a.settingsService, a.pricingService, a.discountService)
+	a.licenseService = services.NewLicenseService(appDir)
+	a.auditService = services.NewAuditService(a.repo, a.settingsService)
+	a.searchService = services.NewSearchService(a.repo, a.settingsService)
+	a.companyService = services.NewCompanyService(a.repo)
+	a.authService = services.NewAuthService(a.repo)
+	a.seatService = services.NewSeatService(a.repo, a.licenseService)
+	a.applySettings(a.settingsService.Get())
+	// Schema snapshot for diagnostics when the db subsystem logs at debug level
+	a.db.LogDiagnostics()
+	if dbLog.Enabled(a.ctx, slog.LevelDebug) {
+		if schema, derr := a.repo.DebugSchema(a.ctx); derr == nil {
 			for t, cols := range schema {
-				log.Printf("[DB] table=%s cols=%v", t, cols)
+				dbLog.Debug("table schema", "table", t, "columns", cols)
 			}
 		}
 	}
-
-	// Initialize repository and services
-	a.repo = db.NewRepository(a.db)
-	a.clientService = services.NewClientService(a.repo)
-	a.productService = services.NewProductService(a.repo)
-	a.orderService = services.NewOrderService(a.repo)
-	a.licenseService = services.NewLicenseService()
-	log.Printf("✓ Services initialized successfully!")
+	a.settingsService.OnChange(func(settings db.AppSettings) {
+		a.applySettings(settings)
+		runtime.EventsEmit(a.ctx, EventSettingsChanged, settings)
+	})
+	if err := a.repo.EnsureSearchIndex(a.ctx); err != nil {
+		// Non-fatal: lists still work, only global search is degraded
+		appLog.Error("search index build failed", "err", err)
+	}
+	// Sessions start as the local OS user with cashier rights; admin rights
+	// are granted by SetCurrentUser after authentication
+	actor := db.Actor{User: "desktop", Role: db.RoleCashier}
+	if u, uerr := user.Current(); uerr == nil && u.Username != "" {
+		actor.User = u.Username
+	}
+	a.actorMu.Lock()
+	a.actor = actor
+	a.actorMu.Unlock()
 
 	// Initialize PDF generators
-	a.orderPDF = pdf.NewOrderPDFGenerator()
-	log.Printf("✓ PDF generators initialized successfully!")
+	// Layouts in <appDir>/templates/<name>.json override the built-in templates
+	if key, kerr := a.repo.GetOrCreateSecret(a.ctx, db.SecretDocumentKey, 32); kerr == nil {
+		a.docSigner = pdf.NewDocumentSigner(key)
+	} else {
+		// Non-fatal: documents are printed without a QR code
+		pdfLog.Warn("document signing key unavailable", "err", kerr)
+	}
+	// Fonts in <appDir>/fonts (<Family>-<Style>.ttf) are available to templates
+	fonts := pdf.NewFontRegistry()
+	if ferr := fonts.LoadDir(filepath.Join(appDir, "fonts")); ferr != nil {
+		// Non-fatal: templates fall back to the built-in fonts
+		pdfLog.Warn("font loading failed", "err", ferr)
+	}
+	a.orderPDF = pdf.NewOrderPDFGenerator(filepath.Join(appDir, "templates"), a.docSigner, fonts)
 
 	a.initialized = true
-	log.Printf("🎉 Application startup completed successfully!")
+	appLog.Info("startup completed")
 }
 
 // Greet returns a greeting for the given name
@@ -127,27 +203,18 @@ func (a *App) CreateClient(name, phone, address string) (*db.Client, error) {
 	if address != "" {
 		addressPtr = &address
 	}
-	if v, ok := os.LookupEnv("DEBUG_CLIENTS"); ok && v != "" && v != "0" {
-		log.Printf("[clients] CreateClient req name=%q phone=%q address=%q", name, phone, address)
-	}
 	client := db.Client{
 		Name:      name,
 		Phone:     phonePtr,
 		Address:   addressPtr,
 		DebtCents: 0,
 	}
-	log.Printf("[clients] Create request name=%q phone=%q address=%q", name, phone, address)
-	res, err := a.clientService.Create(a.ctx, client)
+	clientLog.Debug("create client requested", "name", name, "phone", phone, "address", address)
+	res, err := a.clientService.Create(a.opCtx(), client)
 	if err != nil {
-		log.Printf("[clients] Create failed: %v", err)
-		if v, ok := os.LookupEnv("DEBUG_CLIENTS"); ok && v != "" && v != "0" {
-			log.Printf("[clients] CreateClient error: %v", err)
-		}
+		clientLog.Warn("create client rejected", "name", name, "err", err)
 		return nil, err
 	}
-	if v, ok := os.LookupEnv("DEBUG_CLIENTS"); ok && v != "" && v != "0" {
-		log.Printf("[clients] CreateClient success id=%d", res.ID)
-	}
 	return res, nil
 }
 
@@ -166,6 +233,15 @@ func (a *App) GetClients(query string, limit, offset int) (*db.PaginatedResult[d
 	}, nil
 }
 
+// GetClientsAfter retrieves a page of clients after cursor. Pass the previous
+// page's next_cursor to continue, or "" for the first page.
+func (a *App) GetClientsAfter(query, cursor string, limit int) (*db.PaginatedResult[db.Client], error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	return a.clientService.ListAfter(a.ctx, query, cursor, limit)
+}
+
 // GetClient retrieves a client by ID
 func (a *App) GetClient(id int) (*db.Client, error) {
 	if err := a.ensureReady(); err != nil {
@@ -194,7 +270,7 @@ func (a *App) UpdateClient(id int, name, phone, address string, debtCents int64)
 		Address:   addressPtr,
 		DebtCents: debtCents,
 	}
-	return a.clientService.Update(a.ctx, client)
+	return a.clientService.Update(a.opCtx(), client)
 }
 
 // AdjustClientDebt adjusts a client's debt by delta cents and creates a debt payment record
@@ -208,7 +284,7 @@ func (a *App) AdjustClientDebt(id int, deltaCents int64, notes string) (*db.Clie
 		notesPtr = &notes
 	}
 	
-	client, _, err := a.clientService.AdjustDebt(a.ctx, int64(id), deltaCents, notesPtr)
+	client, _, err := a.clientService.AdjustDebt(a.opCtx(), int64(id), deltaCents, notesPtr)
 	return client, err
 }
 
@@ -220,6 +296,14 @@ func (a *App) GetDebtPayments(limit, offset int) (*db.PaginatedResult[db.DebtPay
 	return a.clientService.GetDebtPayments(a.ctx, limit, offset)
 }
 
+// GetDebtPaymentsAfter retrieves a page of debt payment records after cursor
+func (a *App) GetDebtPaymentsAfter(cursor string, limit int) (*db.PaginatedResult[db.DebtPaymentDetail], error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	return a.clientService.GetDebtPaymentsAfter(a.ctx, cursor, limit)
+}
+
 // GetClientDebtPayments retrieves debt payment records for a specific client
 func (a *App) GetClientDebtPayments(clientID, limit, offset int) (*db.PaginatedResult[db.DebtPayment], error) {
 	if err := a.ensureReady(); err != nil {
@@ -228,10 +312,39 @@ func (a *App) GetClientDebtPayments(clientID, limit, offset int) (*db.PaginatedR
 	return a.clientService.GetClientDebtPayments(a.ctx, int64(clientID), limit, offset)
 }
 
-// DeleteClient deletes a client
+// DeleteClient moves a client to the recycle bin
 func (a *App) DeleteClient(id int) error {
 	if err := a.ensureReady(); err != nil { return err }
-	return a.clientService.Delete(a.ctx, int64(id))
+	return a.clientService.Delete(a.opCtx(), int64(id))
+}
+
+// GetDeletedClients lists clients in the recycle bin
+func (a *App) GetDeletedClients(query string, limit, offset int) (*db.PaginatedResult[db.Client], error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	clients, total, err := a.clientService.ListDeleted(a.ctx, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return &db.PaginatedResult[db.Client]{
+		Data:  clients,
+		Total: total,
+	}, nil
+}
+
+// RestoreClient restores a client from the recycle bin
+func (a *App) RestoreClient(id int) (*db.Client, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	return a.clientService.Restore(a.opCtx(), int64(id))
+}
+
+// PurgeClient permanently deletes a client from the recycle bin (admins only)
+func (a *App) PurgeClient(id int) error {
+	if err := a.ensureReady(); err != nil { return err }
+	return a.clientService.Purge(a.opCtx(), int64(id))
 }
 
 // Product operations
@@ -254,18 +367,19 @@ func (a *App) CreateProduct(name, description string, price float64, sku string)
 		Description:    descPtr,
 		SKU:            skuPtr,
 		UnitPriceCents: int64(price), // Convert dollars to cents
-		Currency:       "USD",
+		Currency:       a.settingsService.DefaultCurrency(),
 		Active:         true,
 	}
-	return a.productService.Create(a.ctx, product)
+	return a.productService.Create(a.opCtx(), product)
 }
 
-// GetProducts retrieves products with pagination and search
-func (a *App) GetProducts(query string, limit, offset int) (*db.PaginatedResult[db.Product], error) {
+// GetProducts retrieves products with pagination and search; a non-zero
+// categoryID limits them to that category and its subcategories
+func (a *App) GetProducts(query string, categoryID int, limit, offset int) (*db.PaginatedResult[db.Product], error) {
 	if err := a.ensureReady(); err != nil {
 		return nil, err
 	}
-	products, total, err := a.productService.List(a.ctx, query, nil, limit, offset)
+	products, total, err := a.productService.List(a.ctx, query, nil, categoryFilter(categoryID), limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -275,6 +389,25 @@ func (a *App) GetProducts(query string, limit, offset int) (*db.PaginatedResult[
 	}, nil
 }
 
+// GetProductsAfter retrieves a page of products after cursor, filtered as in
+// GetProducts
+func (a *App) GetProductsAfter(query string, categoryID int, cursor string, limit int) (*db.PaginatedResult[db.Product], error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	return a.productService.ListAfter(a.ctx, query, nil, categoryFilter(categoryID), cursor, limit)
+}
+
+// categoryFilter turns the category ID of a product list request into its
+// filter, nil for zero (all categories)
+func categoryFilter(categoryID int) *int64 {
+	if categoryID <= 0 {
+		return nil
+	}
+	id := int64(categoryID)
+	return &id
+}
+
 // GetProduct retrieves a product by ID
 func (a *App) GetProduct(id int) (*db.Product, error) {
 	if err := a.ensureReady(); err != nil {
@@ -283,7 +416,8 @@ func (a *App) GetProduct(id int) (*db.Product, error) {
 	return a.productService.Get(a.ctx, int64(id))
 }
 
-// UpdateProduct updates an existing product
+// UpdateProduct updates the name, description, price and SKU of a product;
+// its other fields (category, unit, variant attributes, stock) are kept
 func (a *App) UpdateProduct(id int, name, description string, price float64, sku string) (*db.Product, error) {
 	if err := a.ensureReady(); err != nil {
 		return nil, err
@@ -296,22 +430,263 @@ func (a *App) UpdateProduct(id int, name, description string, price float64, sku
 		skuPtr = &sku
 	}
 
-	product := db.Product{
-		ID:             int64(id),
-		Name:           name,
-		Description:    descPtr,
-		SKU:            skuPtr,
-		UnitPriceCents: int64(price), // Convert dollars to cents
-		Currency:       "USD",
-		Active:         true,
+	product, err := a.productService.Get(a.ctx, int64(id))
+	if err != nil {
+		return nil, err
+	}
+	product.Name = name
+	product.Description = descPtr
+	product.SKU = skuPtr
+	product.UnitPriceCents = int64(price) // Convert dollars to cents
+	product.Currency = a.settingsService.DefaultCurrency()
+	product.Active = true
+	return a.productService.Update(a.opCtx(), *product)
+}
+
+// SetProductCategory moves a product and its variants to a category; zero
+// removes it from any category
+func (a *App) SetProductCategory(id, categoryID int) (*db.Product, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	return a.productService.SetCategory(a.opCtx(), int64(id), categoryFilter(categoryID))
+}
+
+// GetProductVariants lists the variants of a product
+func (a *App) GetProductVariants(parentID int) ([]db.Product, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	return a.productService.ListVariants(a.ctx, int64(parentID))
+}
+
+// SaveProductVariant creates a variant of variant.parent_id (e.g. a size or
+// color of it), or updates the variant when variant.id is set. New variants
+// without a price, unit or name take them from their parent.
+func (a *App) SaveProductVariant(variant db.Product) (*db.Product, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
 	}
-	return a.productService.Update(a.ctx, product)
+	if variant.ID > 0 {
+		return a.productService.Update(a.opCtx(), variant)
+	}
+	if variant.ParentID == nil {
+		return nil, apperr.Validation("parent_id", "invalid_variant_parent")
+	}
+	variant.Active = true
+	return a.productService.Create(a.opCtx(), variant)
 }
 
-// DeleteProduct deletes a product
+// SetProductStock sets the quantity on hand of a product or variant, as a
+// decimal in its unit (e.g. "12" or "2.5")
+func (a *App) SetProductStock(id int, stock string) (*db.Product, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	qty, err := db.ParseQuantity(stock)
+	if err != nil {
+		return nil, apperr.Validation("stock", "invalid_stock").Wrap(err)
+	}
+	product, err := a.productService.Get(a.ctx, int64(id))
+	if err != nil {
+		return nil, err
+	}
+	product.Stock = qty
+	return a.productService.Update(a.opCtx(), *product)
+}
+
+// DeleteProduct moves a product to the recycle bin
 func (a *App) DeleteProduct(id int) error {
 	if err := a.ensureReady(); err != nil { return err }
-	return a.productService.Delete(a.ctx, int64(id))
+	return a.productService.Delete(a.opCtx(), int64(id))
+}
+
+// GetDeletedProducts lists products in the recycle bin
+func (a *App) GetDeletedProducts(query string, limit, offset int) (*db.PaginatedResult[db.Product], error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	products, total, err := a.productService.ListDeleted(a.ctx, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return &db.PaginatedResult[db.Product]{
+		Data:  products,
+		Total: total,
+	}, nil
+}
+
+// RestoreProduct restores a product from the recycle bin
+func (a *App) RestoreProduct(id int) (*db.Product, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	return a.productService.Restore(a.opCtx(), int64(id))
+}
+
+// PurgeProduct permanently deletes a product from the recycle bin (admins only)
+func (a *App) PurgeProduct(id int) error {
+	if err := a.ensureReady(); err != nil { return err }
+	return a.productService.Purge(a.opCtx(), int64(id))
+}
+
+// GetProductUnits lists the packaging units a product is also sold in
+func (a *App) GetProductUnits(productID int) ([]db.ProductUnit, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	return a.productService.ListUnits(a.ctx, int64(productID))
+}
+
+// SaveProductUnit adds a packaging unit to a product (e.g. a carton of 12
+// pieces, factor 12), or updates it when unit.id is set
+func (a *App) SaveProductUnit(unit db.ProductUnit) (*db.ProductUnit, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	return a.productService.SaveUnit(a.opCtx(), unit)
+}
+
+// DeleteProductUnit removes a packaging unit from a product
+func (a *App) DeleteProductUnit(id int) error {
+	if err := a.ensureReady(); err != nil { return err }
+	return a.productService.DeleteUnit(a.opCtx(), int64(id))
+}
+
+// Pricing operations
+
+// GetPriceLists lists the price lists (e.g. retail, wholesale, distributor)
+func (a *App) GetPriceLists() ([]db.PriceList, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	return a.pricingService.ListPriceLists(a.ctx)
+}
+
+// GetPriceList retrieves a price list with its product prices
+func (a *App) GetPriceList(id int) (*db.PriceListDetail, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	return a.pricingService.GetPriceList(a.ctx, int64(id))
+}
+
+// CreatePriceList creates a price list (admins only)
+func (a *App) CreatePriceList(list db.PriceList) (*db.PriceList, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	return a.pricingService.CreatePriceList(a.opCtx(), list)
+}
+
+// UpdatePriceList renames a price list or changes its currency (admins only)
+func (a *App) UpdatePriceList(list db.PriceList) (*db.PriceList, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	return a.pricingService.UpdatePriceList(a.opCtx(), list)
+}
+
+// DeletePriceList removes a price list and its prices (admins only)
+func (a *App) DeletePriceList(id int) error {
+	if err := a.ensureReady(); err != nil { return err }
+	return a.pricingService.DeletePriceList(a.opCtx(), int64(id))
+}
+
+// SetProductPrice adds or updates (when price.id is set) the price of a
+// product in a price list, with optional validity dates (admins only)
+func (a *App) SetProductPrice(price db.ProductPrice) (*db.ProductPrice, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	return a.pricingService.SetProductPrice(a.opCtx(), price)
+}
+
+// DeleteProductPrice removes a product price from its list (admins only)
+func (a *App) DeleteProductPrice(id int) error {
+	if err := a.ensureReady(); err != nil { return err }
+	return a.pricingService.DeleteProductPrice(a.opCtx(), int64(id))
+}
+
+// SetClientPriceList sets the default price list of a client; 0 goes back to
+// the products' own prices (admins only)
+func (a *App) SetClientPriceList(clientID, priceListID int) (*db.Client, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	var listID *int64
+	if priceListID != 0 {
+		id := int64(priceListID)
+		listID = &id
+	}
+	return a.pricingService.SetClientPriceList(a.opCtx(), int64(clientID), listID)
+}
+
+// QuotePrice returns the price a client pays today for qty of a product in
+// unit (its base unit when empty), to fill order lines
+func (a *App) QuotePrice(clientID, productID int, qty float64, unit string) (*db.PriceQuote, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	return a.pricingService.Quote(a.ctx, int64(clientID), int64(productID), db.QuantityFromFloat(qty), unit)
+}
+
+// Category operations
+
+// GetCategories lists the product categories
+func (a *App) GetCategories() ([]db.Category, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	return a.categoryService.List(a.ctx)
+}
+
+// CreateCategory creates a product category (admins only)
+func (a *App) CreateCategory(category db.Category) (*db.Category, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	return a.categoryService.Create(a.opCtx(), category)
+}
+
+// UpdateCategory renames a product category (admins only)
+func (a *App) UpdateCategory(category db.Category) (*db.Category, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	return a.categoryService.Update(a.opCtx(), category)
+}
+
+// DeleteCategory removes a category and its discount rules; its products
+// are left without a category (admins only)
+func (a *App) DeleteCategory(id int) error {
+	if err := a.ensureReady(); err != nil { return err }
+	return a.categoryService.Delete(a.opCtx(), int64(id))
+}
+
+// Discount operations
+
+// GetDiscountRules lists the volume and promotional discount rules
+func (a *App) GetDiscountRules() ([]db.DiscountRule, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	return a.discountService.ListRules(a.ctx)
+}
+
+// SaveDiscountRule creates a discount rule, or updates it when rule.id is
+// set (admins only)
+func (a *App) SaveDiscountRule(rule db.DiscountRule) (*db.DiscountRule, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	return a.discountService.SaveRule(a.opCtx(), rule)
+}
+
+// DeleteDiscountRule removes a discount rule (admins only)
+func (a *App) DeleteDiscountRule(id int) error {
+	if err := a.ensureReady(); err != nil { return err }
+	return a.discountService.DeleteRule(a.opCtx(), int64(id))
 }
 
 // Dashboard operations
@@ -351,19 +726,21 @@ func (a *App) CreateOrder(clientID int, notes string, discountPercent int, items
 		}
 
 		nameSnapshot, _ := item["name_snapshot"].(string)
-		qty, _ := item["qty"].(float64)
+		qty := itemQuantity(item["qty"])
+		unit, _ := item["unit"].(string)
 		unitPriceCents, _ := item["unit_price_cents"].(float64)
 		discountPercent, _ := item["discount_percent"].(float64)
 		currency, _ := item["currency"].(string)
 		if currency == "" {
-			currency = "USD"
+			currency = a.settingsService.DefaultCurrency()
 		}
 
 		orderItems[i] = db.OrderItemDraft{
 			ProductID:       productID,
 			NameSnapshot:    nameSnapshot,
 			SKUSnapshot:     skuSnapshot,
-			Qty:             int(qty),
+			Qty:             qty,
+			Unit:            unit,
 			UnitPriceCents:  int64(unitPriceCents),
 			DiscountPercent: int(discountPercent),
 			Currency:        currency,
@@ -377,28 +754,27 @@ func (a *App) CreateOrder(clientID int, notes string, discountPercent int, items
 		Items:           orderItems,
 	}
 
-	return a.orderService.Create(a.ctx, draft)
+	return a.orderService.Create(a.opCtx(), draft)
 }
 
-// GetOrders retrieves orders with pagination and search
-func (a *App) GetOrders(query string, clientID int, status string, limit, offset int, sort string) (*db.PaginatedResult[db.OrderDetail], error) {
+// itemQuantity reads the quantity of an order line sent by the frontend, as
+// a number or a decimal string (e.g. "1.25" kg)
+func itemQuantity(v interface{}) db.Quantity {
+	switch qty := v.(type) {
+	case float64:
+		return db.QuantityFromFloat(qty)
+	case string:
+		q, _ := db.ParseQuantity(qty) // zero is reported by the item validation
+		return q
+	}
+	return 0
+}
+
+// GetOrders retrieves orders with pagination, filters and whitelisted sorting
+func (a *App) GetOrders(filters db.OrderFilters, limit, offset int) (*db.PaginatedResult[db.OrderDetail], error) {
 	if err := a.ensureReady(); err != nil {
 		return nil, err
 	}
-	filters := db.OrderFilters{}
-	if query != "" {
-		filters.Query = &query
-	}
-	if clientID > 0 {
-		clientIDInt64 := int64(clientID)
-		filters.ClientID = &clientIDInt64
-	}
-	if status != "" {
-		filters.Status = &status
-	}
-	if sort != "" {
-		filters.Sort = &sort
-	}
 
 	orders, total, err := a.orderService.List(a.ctx, filters, limit, offset)
 	if err != nil {
@@ -410,6 +786,14 @@ func (a *App) GetOrders(query string, clientID int, status string, limit, offset
 	}, nil
 }
 
+// GetOrdersAfter retrieves a page of orders after cursor, newest first
+func (a *App) GetOrdersAfter(filters db.OrderFilters, cursor string, limit int) (*db.PaginatedResult[db.OrderDetail], error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	return a.orderService.ListAfter(a.ctx, filters, cursor, limit)
+}
+
 // GetOrder retrieves an order by ID
 func (a *App) GetOrder(id int) (*db.OrderDetail, error) {
 	if err := a.ensureReady(); err != nil {
@@ -453,19 +837,21 @@ func (a *App) UpdateOrder(id int, status, notes string, discountPercent *int, it
 			}
 
 			nameSnapshot, _ := item["name_snapshot"].(string)
-			qty, _ := item["qty"].(float64)
+			qty := itemQuantity(item["qty"])
+			unit, _ := item["unit"].(string)
 			unitPriceCents, _ := item["unit_price_cents"].(float64)
 			discountPercent, _ := item["discount_percent"].(float64)
 			currency, _ := item["currency"].(string)
 			if currency == "" {
-				currency = "USD"
+				currency = a.settingsService.DefaultCurrency()
 			}
 
 			orderItems[i] = db.OrderItemDraft{
 				ProductID:       productID,
 				NameSnapshot:    nameSnapshot,
 				SKUSnapshot:     skuSnapshot,
-				Qty:             int(qty),
+				Qty:             qty,
+				Unit:            unit,
 				UnitPriceCents:  int64(unitPriceCents),
 				DiscountPercent: int(discountPercent),
 				Currency:        currency,
@@ -474,7 +860,7 @@ func (a *App) UpdateOrder(id int, status, notes string, discountPercent *int, it
 		update.Items = orderItems
 	}
 
-	return a.orderService.Update(a.ctx, update)
+	return a.orderService.Update(a.opCtx(), update)
 }
 
 // DeleteOrder deletes an order (cancels it)
@@ -482,7 +868,7 @@ func (a *App) DeleteOrder(id int) error {
 	if err := a.ensureReady(); err != nil {
 		return err
 	}
-	return a.orderService.Delete(a.ctx, int64(id))
+	return a.orderService.Delete(a.opCtx(), int64(id))
 }
 
 // GetOrderStatuses returns available order statuses
@@ -499,24 +885,40 @@ func (a *App) DebugSchema() (map[string][]string, error) {
 	return a.repo.DebugSchema(a.ctx)
 }
 
-// ExportOrderPDF generates and exports an order as PDF
+// ExportOrderPDF generates and exports an order as PDF in the document locale
+// of the settings
 func (a *App) ExportOrderPDF(orderID int) ([]byte, error) {
+	return a.ExportOrderPDFIn(orderID, "")
+}
+
+// ExportOrderPDFIn generates and exports an order as PDF in locale (ar, en or
+// fr), e.g. in French for a distributor; empty uses the settings
+func (a *App) ExportOrderPDFIn(orderID int, locale string) ([]byte, error) {
 	if err := a.ensureReady(); err != nil {
 		return nil, err
 	}
+	locale, err := a.documentLocale(locale)
+	if err != nil {
+		return nil, err
+	}
 	orderDetail, err := a.orderService.Get(a.ctx, int64(orderID))
 	if err != nil {
 		return nil, err
 	}
 
+	company, err := a.companyService.Get(a.ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	// Generate PDF bytes
-	pdfBytes, err := a.orderPDF.GenerateOrderPDF(*orderDetail)
+	pdfBytes, err := a.orderPDF.GenerateOrderPDF(*orderDetail, *company, locale)
 	if err != nil {
 		return nil, err
 	}
 
 	// Log info for debugging: ensure we have items and bytes length
-	log.Printf("ExportOrderPDF: orderID=%d items=%d bytes=%d\n", orderID, len(orderDetail.Items), len(pdfBytes))
+	pdfLog.Debug("order exported", "order_id", orderID, "locale", locale, "items", len(orderDetail.Items), "bytes", len(pdfBytes))
 
 	if len(pdfBytes) == 0 {
 		return nil, fmt.Errorf("generated PDF is empty for order %d", orderID)
@@ -525,6 +927,343 @@ func (a *App) ExportOrderPDF(orderID int) ([]byte, error) {
 	return pdfBytes, nil
 }
 
+// maxExportOrders caps how many orders one batch export may render
+const maxExportOrders = 1000
+
+// EventOrdersExportProgress is emitted with a pdf.BatchProgress after each
+// order rendered by ExportOrdersPDF
+const EventOrdersExportProgress = "orders-export:progress"
+
+// ExportOrdersPDF renders every order matching filters, e.g. today's orders
+// for delivery. format is "pdf" for one merged PDF or "zip" for an archive of
+// individual PDFs. Progress is reported through EventOrdersExportProgress.
+func (a *App) ExportOrdersPDF(filters db.OrderFilters, format string) ([]byte, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	if err := a.requireFeature(services.FeatureBatchExport); err != nil {
+		return nil, err
+	}
+	if err := pdf.CheckBatchFormat(format); err != nil {
+		return nil, err
+	}
+
+	var orders []db.OrderDetail
+	filters.SkipItems = false
+	for {
+		page, total, err := a.orderService.List(a.ctx, filters, 100, len(orders))
+		if err != nil {
+			return nil, err
+		}
+		if total > maxExportOrders {
+			return nil, apperr.Validation("", "too_many_orders_to_export").With("count", total).With("max", maxExportOrders) // Too many orders for one export
+		}
+		orders = append(orders, page...)
+		if len(page) == 0 || len(orders) >= total {
+			break
+		}
+	}
+	if len(orders) == 0 {
+		return nil, apperr.NotFound("no_orders_to_export") // No matching orders to export
+	}
+
+	company, err := a.companyService.Get(a.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	locale, _ := a.documentLocale("")
+	out, err := a.orderPDF.GenerateOrdersPDF(a.ctx, orders, *company, format, locale, func(p pdf.BatchProgress) {
+		runtime.EventsEmit(a.ctx, EventOrdersExportProgress, p)
+	})
+	if err != nil {
+		return nil, err
+	}
+	pdfLog.Info("orders exported", "orders", len(orders), "format", format, "bytes", len(out), "duration", time.Since(start))
+	return out, nil
+}
+
+// ExportOrderReceipt renders the cash-register receipt of an order for thermal
+// printers. format is one of pdf-80, pdf-58 (narrow PDFs), escpos-80, escpos-58
+// (raw ESC/POS with Arabic drawn as raster images) or escpos-80-cp1256,
+// escpos-58-cp1256 (ESC/POS text for printers with Arabic firmware).
+func (a *App) ExportOrderReceipt(orderID int, format string) ([]byte, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	if err := a.requireFeature(services.FeatureReceipts); err != nil {
+		return nil, err
+	}
+	orderDetail, err := a.orderService.Get(a.ctx, int64(orderID))
+	if err != nil {
+		return nil, err
+	}
+
+	company, err := a.companyService.Get(a.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	locale, _ := a.documentLocale("")
+	receipt, err := a.orderPDF.GenerateReceipt(*orderDetail, *company, format, locale)
+	if err != nil {
+		return nil, err
+	}
+	pdfLog.Debug("receipt exported", "order_id", orderID, "format", format, "bytes", len(receipt))
+	return receipt, nil
+}
+
+// LookupScannedDocument resolves the QR code scanned from a printed order or
+// invoice to its order. The code's signature must match this installation;
+// Current in the result is false when the paper copy predates an edit.
+func (a *App) LookupScannedDocument(payload string) (*db.ScannedDocument, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	if a.docSigner == nil {
+		return nil, &apperr.Error{Code: apperr.CodeInternal, Key: "document_verification_unavailable"} // Document verification unavailable
+	}
+	ref, err := a.docSigner.Decode(payload)
+	if err != nil {
+		pdfLog.Warn("rejected scanned document", "err", err)
+		return nil, apperr.Validation("payload", "invalid_document_code").Wrap(err) // Invalid document code
+	}
+	if ref.Kind == pdf.DocumentInvoice {
+		if err := a.requireFeature(services.FeatureInvoices); err != nil {
+			return nil, err
+		}
+	}
+	return a.orderService.ResolveDocument(a.ctx, ref.Kind, ref.Number, ref.Date, ref.TotalCents)
+}
+
+// Settings operations
+
+// EventSettingsChanged is emitted with the new db.AppSettings after they are saved
+const EventSettingsChanged = "settings:changed"
+
+// GetSettings retrieves the application settings
+func (a *App) GetSettings() (*db.AppSettings, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	settings := a.settingsService.Get()
+	return &settings, nil
+}
+
+// UpdateSettings validates and saves the application settings; they take
+// effect immediately
+func (a *App) UpdateSettings(settings db.AppSettings) (*db.AppSettings, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	return a.settingsService.Update(a.opCtx(), settings)
+}
+
+// GetRecentLogs returns up to limit (200 by default, at most 1000) of the
+// latest log entries, newest first, at level (debug, info, warn or error) or
+// above; subsystem, if not empty, keeps only that subsystem's entries. It
+// works before the backend is ready, so startup failures can be inspected.
+func (a *App) GetRecentLogs(level, subsystem string, limit int) ([]logging.Entry, error) {
+	minLevel := slog.LevelInfo
+	if level != "" {
+		parsed, err := logging.ParseLevel(level)
+		if err != nil {
+			return nil, apperr.Validation("level", "invalid_log_level").With("level", level) // Invalid log level
+		}
+		minLevel = parsed
+	}
+	if limit <= 0 {
+		limit = 200
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+	return logging.Recent(minLevel, subsystem, limit), nil
+}
+
+// applySettings pushes settings to the parts of the app that do not read them on demand
+func (a *App) applySettings(settings db.AppSettings) {
+	logging.SetLevels(a.settingsService.LogLevels())
+	a.licenseService.SetServerURL(settings.LicenseServerURL)
+	runtime.WindowSetSize(a.ctx, settings.WindowWidth, settings.WindowHeight)
+}
+
+// Company operations
+
+// GetCompanySettings retrieves the company profile printed on documents
+func (a *App) GetCompanySettings() (*db.CompanySettings, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	return a.companyService.Get(a.ctx)
+}
+
+// UpdateCompanySettings saves the company profile (names, address, phones,
+// registration numbers, logo and footer text)
+func (a *App) UpdateCompanySettings(settings db.CompanySettings) (*db.CompanySettings, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	return a.companyService.Update(a.opCtx(), settings)
+}
+
+// Search operations
+
+// GlobalSearch searches clients (name, phone, address), products (name, SKU, description)
+// and orders (number, client, contained products) with Arabic-insensitive matching
+func (a *App) GlobalSearch(q string) ([]db.SearchResult, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	return a.searchService.GlobalSearch(a.ctx, q, 0)
+}
+
+// RebuildSearchIndex repopulates the global search index from the database
+func (a *App) RebuildSearchIndex() error {
+	if err := a.ensureReady(); err != nil { return err }
+	return a.searchService.Rebuild(a.ctx)
+}
+
+// Audit operations
+
+// GetAuditLog browses the audit trail by entity (e.g. "client", "order"), entity id and date range.
+// Dates use YYYY-MM-DD; empty values and a zero entityID disable the corresponding filter.
+func (a *App) GetAuditLog(entity string, entityID int, from, to string, limit, offset int) (*db.PaginatedResult[db.AuditEntry], error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	if err := a.requireFeature(services.FeatureAuditLog); err != nil {
+		return nil, err
+	}
+	filters := db.AuditFilters{}
+	if entity != "" {
+		filters.Entity = &entity
+	}
+	if entityID > 0 {
+		id := int64(entityID)
+		filters.EntityID = &id
+	}
+	if from != "" {
+		fromDate, err := time.ParseInLocation("2006-01-02", from, time.Local)
+		if err != nil {
+			return nil, apperr.Validation("from", "invalid_date").With("value", from) // Invalid date format
+		}
+		filters.From = &fromDate
+	}
+	if to != "" {
+		toDate, err := time.ParseInLocation("2006-01-02", to, time.Local)
+		if err != nil {
+			return nil, apperr.Validation("to", "invalid_date").With("value", to) // Invalid date format
+		}
+		// Make the end date inclusive
+		toDate = toDate.AddDate(0, 0, 1)
+		filters.To = &toDate
+	}
+	return a.auditService.List(a.ctx, filters, limit, offset)
+}
+
+// SetCurrentUser sets the user and role used for subsequent changes (audit
+// log, admin-only operations). Switching to the admin role, or to another
+// user as admin, requires the admin password, and a user not seen before
+// takes one of the licensed seats.
+func (a *App) SetCurrentUser(name, role, password string) error {
+	if err := a.ensureReady(); err != nil { return err }
+	if role != db.RoleAdmin && role != db.RoleCashier {
+		return apperr.Validation("role", "unknown_role").With("role", role)
+	}
+	current := a.currentActor()
+	if name == "" {
+		name = current.User
+	}
+	if role == db.RoleAdmin && (!current.IsAdmin() || name != current.User) {
+		if err := a.authService.CheckAdminPassword(a.ctx, password); err != nil {
+			return err
+		}
+	}
+	if name != current.User {
+		if err := a.seatService.Claim(a.ctx, name); err != nil {
+			return err
+		}
+	}
+	a.actorMu.Lock()
+	a.actor = db.Actor{User: name, Role: role}
+	a.actorMu.Unlock()
+	return nil
+}
+
+// GetCurrentUser returns the active user and role
+func (a *App) GetCurrentUser() db.Actor {
+	return a.currentActor()
+}
+
+// HasAdminPassword reports whether an admin password has been set; until
+// then nobody can switch to the admin role
+func (a *App) HasAdminPassword() (bool, error) {
+	if err := a.ensureReady(); err != nil {
+		return false, err
+	}
+	return a.authService.HasAdminPassword(a.ctx)
+}
+
+// SetAdminPassword sets the admin password; changing it requires the current
+// one, while the first one is set on first run without it
+func (a *App) SetAdminPassword(current, password string) error {
+	if err := a.ensureReady(); err != nil { return err }
+	return a.authService.SetAdminPassword(a.ctx, current, password)
+}
+
+// currentActor returns a copy of the session's actor
+func (a *App) currentActor() db.Actor {
+	a.actorMu.RLock()
+	defer a.actorMu.RUnlock()
+	return a.actor
+}
+
+// SetLocale sets the locale (ar, en or fr) of the messages this session
+// receives; empty goes back to the settings' locale
+func (a *App) SetLocale(locale string) error {
+	if locale != "" && i18n.Normalize(locale) == "" {
+		return apperr.Validation("locale", "invalid_locale").With("locale", locale)
+	}
+	a.actorMu.Lock()
+	a.locale = i18n.Normalize(locale)
+	a.actorMu.Unlock()
+	return nil
+}
+
+// GetLocale returns the locale of messages for this session
+func (a *App) GetLocale() string {
+	a.actorMu.RLock()
+	locale := a.locale
+	a.actorMu.RUnlock()
+	if locale != "" {
+		return locale
+	}
+	if a.settingsService != nil {
+		return a.settingsService.Locale()
+	}
+	return i18n.Default
+}
+
+// documentLocale validates the locale requested for a document, or returns
+// the document locale of the settings when none is
+func (a *App) documentLocale(requested string) (string, error) {
+	if requested == "" {
+		return a.settingsService.DocumentLocale(), nil
+	}
+	locale := i18n.Normalize(requested)
+	if locale == "" {
+		return "", apperr.Validation("locale", "invalid_locale").With("locale", requested)
+	}
+	return locale, nil
+}
+
+// opCtx returns the request context tagged with the current actor for audit logging
+func (a *App) opCtx() context.Context {
+	return db.WithActor(a.ctx, a.currentActor())
+}
+
 // ensureReady verifies backend initialization before handling a request
 func (a *App) ensureReady() error {
 	if a.initialized && a.repo != nil && a.clientService != nil && a.productService != nil && a.orderService != nil && a.licenseService != nil {
@@ -538,12 +1277,56 @@ func (a *App) ensureReady() error {
 
 // License validation methods
 
+// formatError turns errors returned by bound methods into the payload the
+// frontend receives: a domain error's code, message key, field and parameters,
+// with the message in the session locale. Other errors are reported as internal.
+func (a *App) formatError(err error) any {
+	return apperr.From(err).Localize(a.GetLocale())
+}
+
+// requireFeature rejects calls to a module the license does not include
+func (a *App) requireFeature(feature string) error {
+	if !a.licenseService.HasFeature(feature) {
+		return apperr.Forbidden("feature_not_licensed").With("feature", feature) // Feature not included in your license
+	}
+	return nil
+}
+
+// ListLicensedUsers lists the users holding one of the licensed seats
+func (a *App) ListLicensedUsers() ([]db.LicensedUser, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	return a.seatService.List(a.ctx)
+}
+
+// ReleaseLicensedUser frees the seat of a user who has left (admins only);
+// they take a seat again if they sign in later
+func (a *App) ReleaseLicensedUser(name string) error {
+	if err := a.ensureReady(); err != nil {
+		return err
+	}
+	return a.seatService.Release(a.opCtx(), name)
+}
+
+// HasFeature reports whether the license includes a module (see services.Feature*)
+func (a *App) HasFeature(name string) bool {
+	if err := a.ensureReady(); err != nil {
+		return false
+	}
+	return a.licenseService.HasFeature(name)
+}
+
 // ValidateLicense checks the license status
 func (a *App) ValidateLicense() (*services.LicenseStatus, error) {
 	if err := a.ensureReady(); err != nil {
 		return nil, err
 	}
-	return a.licenseService.ValidateLicense()
+	status, err := a.licenseService.ValidateLicense()
+	if err != nil {
+		return nil, err
+	}
+	return status.Localize(a.GetLocale()), nil
 }
 
 // CheckLicense performs a quick license check
@@ -553,3 +1336,27 @@ func (a *App) CheckLicense() bool {
 	}
 	return a.licenseService.CheckLicenseQuiet()
 }
+
+// InstallLicense installs a license file's content (issued for this computer's MachineID)
+func (a *App) InstallLicense(content string) (*services.LicenseStatus, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	status, err := a.licenseService.InstallLicense([]byte(content))
+	if err != nil {
+		return nil, err
+	}
+	return status.Localize(a.GetLocale()), nil
+}
+
+// RefreshLicense downloads the current license of this computer from the license server
+func (a *App) RefreshLicense() (*services.LicenseStatus, error) {
+	if err := a.ensureReady(); err != nil {
+		return nil, err
+	}
+	status, err := a.licenseService.RefreshLicense(a.ctx)
+	if err != nil {
+		return nil, err
+	}
+	return status.Localize(a.GetLocale()), nil
+}
@@ -0,0 +1,154 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+)
+
+// querier is satisfied by both *DB and *sql.Tx so read helpers can run
+// inside or outside a transaction.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Actor identifies who performed a mutating operation
+type Actor struct {
+	User string `json:"user"`
+	Role string `json:"role"`
+}
+
+// User roles
+const (
+	RoleAdmin   = "ADMIN"
+	RoleCashier = "CASHIER"
+)
+
+// IsAdmin reports whether the actor may perform admin-only operations
+func (a Actor) IsAdmin() bool { return a.Role == RoleAdmin }
+
+type actorKey struct{}
+
+// WithActor returns a context carrying the actor recorded in the audit log
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor stored in ctx, or a "system" actor if none was set
+func ActorFromContext(ctx context.Context) Actor {
+	if actor, ok := ctx.Value(actorKey{}).(Actor); ok && actor.User != "" {
+		return actor
+	}
+	return Actor{User: "system"}
+}
+
+// writeAudit records a mutation in audit_log within the caller's transaction.
+// before/after are marshaled to JSON; nil values are stored as NULL.
+func (r *Repository) writeAudit(ctx context.Context, tx *sql.Tx, entity string, entityID int64, action string, before, after interface{}) error {
+	beforeJSON, err := auditJSON(before)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit before state: %w", err)
+	}
+	afterJSON, err := auditJSON(after)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit after state: %w", err)
+	}
+
+	query := `
+		INSERT INTO audit_log (entity, entity_id, action, before_json, after_json, user, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+	`
+	if _, err := tx.ExecContext(ctx, query, entity, entityID, action, beforeJSON, afterJSON, ActorFromContext(ctx).User); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+func auditJSON(v interface{}) (*string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	s := string(data)
+	return &s, nil
+}
+
+// ListAuditLog retrieves audit entries, newest first, filtered by entity and date range
+func (r *Repository) ListAuditLog(ctx context.Context, filters AuditFilters, limit, offset int) ([]AuditEntry, int, error) {
+	whereClause := "WHERE 1=1"
+	args := []interface{}{}
+
+	if filters.Entity != nil && *filters.Entity != "" {
+		whereClause += " AND entity = ?"
+		args = append(args, *filters.Entity)
+	}
+	if filters.EntityID != nil {
+		whereClause += " AND entity_id = ?"
+		args = append(args, *filters.EntityID)
+	}
+	if filters.From != nil {
+		whereClause += " AND created_at >= ?"
+		args = append(args, filters.From.UTC().Format("2006-01-02 15:04:05"))
+	}
+	if filters.To != nil {
+		whereClause += " AND created_at < ?"
+		args = append(args, filters.To.UTC().Format("2006-01-02 15:04:05"))
+	}
+
+	var total int
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM audit_log %s`, whereClause)
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit log: %w", err)
+	}
+
+	listQuery := fmt.Sprintf(`
+		SELECT id, entity, entity_id, action, before_json, after_json, user, created_at
+		FROM audit_log
+		%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`, whereClause)
+	rows, err := r.db.QueryContext(ctx, listQuery, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []AuditEntry
+	for rows.Next() {
+		var e AuditEntry
+		if err := rows.Scan(&e.ID, &e.Entity, &e.EntityID, &e.Action, &e.Before, &e.After, &e.User, &e.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate audit log: %w", err)
+	}
+
+	return entries, total, nil
+}
+
+// orderSnapshot is the audited state of an order: header plus items
+type orderSnapshot struct {
+	Order Order       `json:"order"`
+	Items []OrderItem `json:"items"`
+}
+
+func (r *Repository) getOrderSnapshot(ctx context.Context, q querier, id int64) (*orderSnapshot, error) {
+	order, err := r.getOrderWith(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+	items, err := r.getOrderItemsWith(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+	return &orderSnapshot{Order: *order, Items: items}, nil
+}
@@ -0,0 +1,144 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	apperr "barakaERP/backend/domain/errors"
+)
+
+// Category operations
+
+// ListCategories lists all product categories by name; callers build the
+// tree from their parent IDs
+func (r *Repository) ListCategories(ctx context.Context) ([]Category, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, name, parent_id, created_at, updated_at FROM category ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	defer rows.Close()
+
+	categories := []Category{}
+	for rows.Next() {
+		var category Category
+		if err := rows.Scan(&category.ID, &category.Name, &category.ParentID, &category.CreatedAt, &category.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+		categories = append(categories, category)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate categories: %w", err)
+	}
+	return categories, nil
+}
+
+// GetCategory retrieves a category by ID
+func (r *Repository) GetCategory(ctx context.Context, id int64) (*Category, error) {
+	return r.getCategoryWith(ctx, r.db, id)
+}
+
+func (r *Repository) getCategoryWith(ctx context.Context, q querier, id int64) (*Category, error) {
+	var category Category
+	err := q.QueryRowContext(ctx, `SELECT id, name, parent_id, created_at, updated_at FROM category WHERE id = ?`, id).
+		Scan(&category.ID, &category.Name, &category.ParentID, &category.CreatedAt, &category.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperr.NotFound("category_not_found")
+		}
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+	return &category, nil
+}
+
+// CreateCategory creates a product category
+func (r *Repository) CreateCategory(ctx context.Context, category Category) (*Category, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO category (name, parent_id, created_at, updated_at)
+		VALUES (?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+	`, category.Name, category.ParentID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, apperr.Conflict("category_name_taken").Wrap(err)
+		}
+		return nil, fmt.Errorf("failed to create category: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category ID: %w", err)
+	}
+
+	created, err := r.getCategoryWith(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.writeAudit(ctx, tx, AuditEntityCategory, id, AuditActionCreate, nil, created); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return created, nil
+}
+
+// UpdateCategory renames a category or moves it under another parent
+func (r *Repository) UpdateCategory(ctx context.Context, category Category) (*Category, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := r.getCategoryWith(ctx, tx, category.ID)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE category SET name = ?, parent_id = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`, category.Name, category.ParentID, category.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, apperr.Conflict("category_name_taken").Wrap(err)
+		}
+		return nil, fmt.Errorf("failed to update category: %w", err)
+	}
+
+	after, err := r.getCategoryWith(ctx, tx, category.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.writeAudit(ctx, tx, AuditEntityCategory, category.ID, AuditActionUpdate, before, after); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return after, nil
+}
+
+// DeleteCategory removes a category and its discount rules; its
+// subcategories and products move up to its parent (none for a top-level
+// category)
+func (r *Repository) DeleteCategory(ctx context.Context, id int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil { return fmt.Errorf("failed to begin transaction: %w", err) }
+	defer tx.Rollback()
+
+	before, err := r.getCategoryWith(ctx, tx, id)
+	if err != nil { return err }
+	if _, err := tx.ExecContext(ctx, `UPDATE category SET parent_id = ?, updated_at = CURRENT_TIMESTAMP WHERE parent_id = ?`, before.ParentID, id); err != nil {
+		return fmt.Errorf("failed to move subcategories: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE product SET category_id = ?, updated_at = CURRENT_TIMESTAMP WHERE category_id = ?`, before.ParentID, id); err != nil {
+		return fmt.Errorf("failed to move category products: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM category WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+	if err := r.writeAudit(ctx, tx, AuditEntityCategory, id, AuditActionDelete, before, nil); err != nil { return err }
+	if err := tx.Commit(); err != nil { return fmt.Errorf("failed to commit transaction: %w", err) }
+	return nil
+}
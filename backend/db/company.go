@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	apperr "barakaERP/backend/domain/errors"
+)
+
+// GetCompanySettings retrieves the company profile
+func (r *Repository) GetCompanySettings(ctx context.Context) (*CompanySettings, error) {
+	return r.getCompanySettingsWith(ctx, r.db)
+}
+
+func (r *Repository) getCompanySettingsWith(ctx context.Context, q querier) (*CompanySettings, error) {
+	var settings CompanySettings
+	var phones string
+	query := `
+		SELECT name_ar, name_fr, address, phones, rc, nif, nis, ai, logo, logo_type, footer_text, updated_at
+		FROM company_settings
+		WHERE id = 1
+	`
+	err := q.QueryRowContext(ctx, query).Scan(
+		&settings.NameAR, &settings.NameFR, &settings.Address, &phones,
+		&settings.RC, &settings.NIF, &settings.NIS, &settings.AI,
+		&settings.Logo, &settings.LogoType, &settings.FooterText, &settings.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperr.NotFound("company_not_found")
+		}
+		return nil, fmt.Errorf("failed to get company settings: %w", err)
+	}
+	if err := json.Unmarshal([]byte(phones), &settings.Phones); err != nil {
+		return nil, fmt.Errorf("failed to decode company phones: %w", err)
+	}
+	return &settings, nil
+}
+
+// UpdateCompanySettings replaces the company profile
+func (r *Repository) UpdateCompanySettings(ctx context.Context, settings CompanySettings) (*CompanySettings, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := r.getCompanySettingsWith(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if settings.Phones == nil {
+		settings.Phones = []string{}
+	}
+	phones, err := json.Marshal(settings.Phones)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode company phones: %w", err)
+	}
+
+	query := `
+		UPDATE company_settings
+		SET name_ar = ?, name_fr = ?, address = ?, phones = ?, rc = ?, nif = ?, nis = ?, ai = ?,
+			logo = ?, logo_type = ?, footer_text = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = 1
+	`
+	_, err = tx.ExecContext(ctx, query,
+		settings.NameAR, settings.NameFR, settings.Address, string(phones),
+		settings.RC, settings.NIF, settings.NIS, settings.AI,
+		settings.Logo, settings.LogoType, settings.FooterText,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update company settings: %w", err)
+	}
+
+	after, err := r.getCompanySettingsWith(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.writeAudit(ctx, tx, AuditEntityCompany, 1, AuditActionUpdate, before.withoutLogo(), after.withoutLogo()); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return after, nil
+}
+
+// withoutLogo keeps image bytes out of the audit log; only the logo type is recorded
+func (s *CompanySettings) withoutLogo() CompanySettings {
+	out := *s
+	out.Logo = nil
+	return out
+}
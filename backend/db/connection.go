@@ -8,17 +8,20 @@ import (
 	"io"
 	"io/fs"
 	"io/ioutil"
-	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"path"
 	"sort"
 	"strings"
 	"time"
+	"barakaERP/backend/logging"
 
 	_ "modernc.org/sqlite"
 )
 
+var dbLog = logging.For(logging.DB)
+
 // DB wraps sql.DB with additional methods
 type DB struct {
 	*sql.DB
@@ -72,10 +75,10 @@ func Connect(dataSourceName string) (*DB, error) {
 	// Improve concurrency and reduce locking issues
 	if _, err := db.Exec("PRAGMA journal_mode=WAL"); err != nil {
 		// Non-fatal: log via fmt, but continue
-		log.Printf("[db] warning: failed to set journal_mode=WAL: %v", err)
+		dbLog.Warn("failed to set journal_mode=WAL", "err", err)
 	}
 	if _, err := db.Exec("PRAGMA busy_timeout=5000"); err != nil {
-		log.Printf("[db] warning: failed to set busy_timeout: %v", err)
+		dbLog.Warn("failed to set busy_timeout", "err", err)
 	}
 
 	// Set connection pool settings
@@ -83,48 +86,49 @@ func Connect(dataSourceName string) (*DB, error) {
 	db.SetMaxIdleConns(5)    // Keep some idle connections
 	db.SetConnMaxLifetime(0) // No connection lifetime limit
 
-	wrapped := &DB{DB: db}
+	return &DB{DB: db}, nil
+}
 
-	// Optional deep diagnostics when DEBUG_DB=1
-	if v, ok := os.LookupEnv("DEBUG_DB"); ok && v != "" && v != "0" && strings.ToLower(v) != "false" {
-		log.Printf("[db] DEBUG_DB enabled. dsn=%s", dsn)
-		// SQLite version
-		var sqliteVersion string
-		if err := wrapped.QueryRow("select sqlite_version()").Scan(&sqliteVersion); err == nil {
-			log.Printf("[db] sqlite_version=%s", sqliteVersion)
-		}
-		// List attached databases
-		rows, err := wrapped.Query("PRAGMA database_list")
-		if err == nil {
-			defer rows.Close()
-			for rows.Next() {
-				var seq int
-				var name, file string
-				if scanErr := rows.Scan(&seq, &name, &file); scanErr == nil {
-					log.Printf("[db] database_list seq=%d name=%s file=%s", seq, name, file)
-				}
+// LogDiagnostics logs the SQLite version, attached databases and tables at
+// debug level
+func (db *DB) LogDiagnostics() {
+	if !dbLog.Enabled(context.Background(), slog.LevelDebug) {
+		return
+	}
+	// SQLite version
+	var sqliteVersion string
+	if err := db.QueryRow("select sqlite_version()").Scan(&sqliteVersion); err == nil {
+		dbLog.Debug("sqlite version", "version", sqliteVersion)
+	}
+	// List attached databases
+	rows, err := db.Query("PRAGMA database_list")
+	if err == nil {
+		defer rows.Close()
+		for rows.Next() {
+			var seq int
+			var name, file string
+			if scanErr := rows.Scan(&seq, &name, &file); scanErr == nil {
+				dbLog.Debug("attached database", "seq", seq, "name", name, "file", file)
 			}
 		}
-		// List tables
-		tableRows, err := wrapped.Query("SELECT name FROM sqlite_master WHERE type='table' ORDER BY name")
-		if err == nil {
-			defer tableRows.Close()
-			var tables []string
-			for tableRows.Next() {
-				var t string
-				if scanErr := tableRows.Scan(&t); scanErr == nil {
-					tables = append(tables, t)
-				}
-			}
-			if len(tables) > 0 {
-				log.Printf("[db] existing tables: %s", strings.Join(tables, ", "))
-			} else {
-				log.Printf("[db] no tables present yet (migrations pending)")
+	}
+	// List tables
+	tableRows, err := db.Query("SELECT name FROM sqlite_master WHERE type='table' ORDER BY name")
+	if err == nil {
+		defer tableRows.Close()
+		var tables []string
+		for tableRows.Next() {
+			var t string
+			if scanErr := tableRows.Scan(&t); scanErr == nil {
+				tables = append(tables, t)
 			}
 		}
+		if len(tables) > 0 {
+			dbLog.Debug("existing tables", "tables", strings.Join(tables, ", "))
+		} else {
+			dbLog.Debug("no tables present yet")
+		}
 	}
-
-	return wrapped, nil
 }
 
 // RunMigrations executes all migration files in the migrations directory
@@ -191,7 +195,7 @@ func (db *DB) RunMigrations(migrationsDir string) error {
 			return fmt.Errorf("failed to record migration %s: %w", version, err)
 		}
 
-		fmt.Printf("Applied migration: %s\n", version)
+		dbLog.Info("applied migration", "version", version)
 	}
 
 	return nil
@@ -269,7 +273,7 @@ func (db *DB) RunEmbeddedMigrations(embeddedFS embed.FS, migrationsDir string) e
 			return fmt.Errorf("failed to record migration %s: %w", version, err)
 		}
 
-		fmt.Printf("Applied migration: %s\n", version)
+		dbLog.Info("applied migration", "version", version)
 	}
 
 	return nil
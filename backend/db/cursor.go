@@ -0,0 +1,248 @@
+package db
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	apperr "barakaERP/backend/domain/errors"
+)
+
+// errInvalidCursor is returned for a cursor that was not produced by encodeCursor
+var errInvalidCursor = apperr.Validation("cursor", "invalid_cursor")
+
+// pageCursor is the sort key of the last row of a keyset page. It is handed to
+// the frontend as an opaque string and only ever decoded here.
+type pageCursor struct {
+	Name *string `json:"n,omitempty"`
+	ID   int64   `json:"i"`
+}
+
+func encodeCursor(c pageCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses a cursor; an empty string means the first page
+func decodeCursor(s string) (*pageCursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	var c pageCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID <= 0 {
+		return nil, errInvalidCursor
+	}
+	return &c, nil
+}
+
+// ListClientsAfter lists live clients by name using keyset pagination.
+// Pass the previous page's NextCursor to continue; "" starts from the beginning.
+func (r *Repository) ListClientsAfter(ctx context.Context, query, cursor string, limit int) (*PaginatedResult[Client], error) {
+	after, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	whereClause := "WHERE deleted_at IS NULL AND name LIKE ?"
+	args := []interface{}{"%" + query + "%"}
+	if after != nil {
+		if after.Name == nil {
+			return nil, errInvalidCursor
+		}
+		whereClause += " AND (name, id) > (?, ?)"
+		args = append(args, *after.Name, after.ID)
+	}
+
+	// Fetch one extra row to know whether another page follows
+	listQuery := fmt.Sprintf(`
+		SELECT id, name, phone, address, debt_cents, created_at, updated_at, deleted_at, price_list_id
+		FROM client
+		%s
+		ORDER BY name, id
+		LIMIT ?
+	`, whereClause)
+	rows, err := r.db.QueryContext(ctx, listQuery, append(args, limit+1)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clients: %w", err)
+	}
+	defer rows.Close()
+
+	clients := []Client{}
+	for rows.Next() {
+		var client Client
+		err := rows.Scan(&client.ID, &client.Name, &client.Phone, &client.Address, &client.DebtCents, &client.CreatedAt, &client.UpdatedAt, &client.DeletedAt, &client.PriceListID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan client: %w", err)
+		}
+		clients = append(clients, client)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate clients: %w", err)
+	}
+
+	result := &PaginatedResult[Client]{Data: clients}
+	if len(clients) > limit {
+		result.Data = clients[:limit]
+		last := result.Data[limit-1]
+		result.NextCursor = encodeCursor(pageCursor{Name: &last.Name, ID: last.ID})
+	}
+	return result, nil
+}
+
+// ListProductsAfter lists live products by name using keyset pagination,
+// filtered as in ListProducts
+func (r *Repository) ListProductsAfter(ctx context.Context, query string, active *bool, categoryID *int64, cursor string, limit int) (*PaginatedResult[Product], error) {
+	after, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	whereClause := "WHERE deleted_at IS NULL AND (name LIKE ? OR sku LIKE ?)"
+	args := []interface{}{"%" + query + "%", "%" + query + "%"}
+	if active != nil {
+		whereClause += " AND active = ?"
+		args = append(args, *active)
+	}
+	if categoryID != nil {
+		whereClause += " AND " + inCategoryTree
+		args = append(args, *categoryID)
+	}
+	if after != nil {
+		if after.Name == nil {
+			return nil, errInvalidCursor
+		}
+		whereClause += " AND (name, id) > (?, ?)"
+		args = append(args, *after.Name, after.ID)
+	}
+
+	listQuery := fmt.Sprintf(`
+		SELECT `+productColumns+`
+		FROM product
+		%s
+		ORDER BY name, id
+		LIMIT ?
+	`, whereClause)
+	rows, err := r.db.QueryContext(ctx, listQuery, append(args, limit+1)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list products: %w", err)
+	}
+	defer rows.Close()
+
+	products := []Product{}
+	for rows.Next() {
+		var product Product
+		if err := scanProduct(rows, &product); err != nil {
+			return nil, fmt.Errorf("failed to scan product: %w", err)
+		}
+		products = append(products, product)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate products: %w", err)
+	}
+
+	result := &PaginatedResult[Product]{Data: products}
+	if len(products) > limit {
+		result.Data = products[:limit]
+		last := result.Data[limit-1]
+		result.NextCursor = encodeCursor(pageCursor{Name: &last.Name, ID: last.ID})
+	}
+	return result, nil
+}
+
+// ListOrdersAfter lists orders newest first using keyset pagination on the
+// order id. Filters apply as in ListOrders except Sort, which is not supported.
+func (r *Repository) ListOrdersAfter(ctx context.Context, filters OrderFilters, cursor string, limit int) (*PaginatedResult[OrderDetail], error) {
+	if filters.Sort != nil && *filters.Sort != "" {
+		return nil, apperr.Validation("sort", "sort_with_cursor")
+	}
+	after, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	whereClause, args := orderWhereClause(filters)
+	if after != nil {
+		whereClause += " AND o.id < ?"
+		args = append(args, after.ID)
+	}
+
+	orders, err := r.queryOrderDetails(ctx, whereClause+" ORDER BY o.id DESC LIMIT ?", append(args, limit+1), filters.SkipItems)
+	if err != nil {
+		return nil, err
+	}
+	if orders == nil {
+		orders = []OrderDetail{}
+	}
+
+	result := &PaginatedResult[OrderDetail]{Data: orders}
+	if len(orders) > limit {
+		result.Data = orders[:limit]
+		result.NextCursor = encodeCursor(pageCursor{ID: result.Data[limit-1].Order.ID})
+	}
+	return result, nil
+}
+
+// GetDebtPaymentsAfter lists debt payments newest first using keyset pagination on the id
+func (r *Repository) GetDebtPaymentsAfter(ctx context.Context, cursor string, limit int) (*PaginatedResult[DebtPaymentDetail], error) {
+	after, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	whereClause := ""
+	args := []interface{}{}
+	if after != nil {
+		whereClause = "WHERE dp.id < ?"
+		args = append(args, after.ID)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			dp.id, dp.client_id, dp.previous_debt_cents, dp.new_debt_cents,
+			dp.adjustment_cents, dp.type, dp.notes, dp.created_at,
+			c.name, c.phone, c.address, c.debt_cents, c.created_at, c.updated_at
+		FROM debt_payment dp
+		JOIN client c ON dp.client_id = c.id
+		%s
+		ORDER BY dp.id DESC
+		LIMIT ?
+	`, whereClause)
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit+1)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get debt payments: %w", err)
+	}
+	defer rows.Close()
+
+	details := []DebtPaymentDetail{}
+	for rows.Next() {
+		var detail DebtPaymentDetail
+		err := rows.Scan(
+			&detail.DebtPayment.ID, &detail.DebtPayment.ClientID,
+			&detail.DebtPayment.PreviousDebtCents, &detail.DebtPayment.NewDebtCents,
+			&detail.DebtPayment.AdjustmentCents, &detail.DebtPayment.Type,
+			&detail.DebtPayment.Notes, &detail.DebtPayment.CreatedAt,
+			&detail.Client.Name, &detail.Client.Phone, &detail.Client.Address,
+			&detail.Client.DebtCents, &detail.Client.CreatedAt, &detail.Client.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan debt payment: %w", err)
+		}
+		detail.Client.ID = detail.DebtPayment.ClientID
+		details = append(details, detail)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate debt payments: %w", err)
+	}
+
+	result := &PaginatedResult[DebtPaymentDetail]{Data: details}
+	if len(details) > limit {
+		result.Data = details[:limit]
+		result.NextCursor = encodeCursor(pageCursor{ID: result.Data[limit-1].DebtPayment.ID})
+	}
+	return result, nil
+}
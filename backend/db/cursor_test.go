@@ -0,0 +1,221 @@
+package db
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+	apperr "barakaERP/backend/domain/errors"
+)
+
+// newCursorRepository returns a repository over an empty database and runs
+// the given statements on it
+func newCursorRepository(t *testing.T, statements ...string) *Repository {
+	t.Helper()
+	conn, err := Connect(filepath.Join(t.TempDir(), "cursor.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	if err := conn.ApplySchemaFile("schema.sql"); err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range statements {
+		if _, err := conn.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewRepository(conn)
+}
+
+// collectPages follows NextCursor from the first page to the last, returning
+// the ids of every page
+func collectPages(t *testing.T, page func(cursor string) ([]int64, string, error)) [][]int64 {
+	t.Helper()
+	var pages [][]int64
+	cursor := ""
+	for {
+		ids, next, err := page(cursor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages = append(pages, ids)
+		if next == "" {
+			return pages
+		}
+		if len(pages) > 10 {
+			t.Fatal("pages never end")
+		}
+		cursor = next
+	}
+}
+
+func checkPages(t *testing.T, got, want [][]int64) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got pages %v, want %v", got, want)
+	}
+}
+
+// checkInvalidCursor expects the error returned for a cursor not produced by
+// encodeCursor
+func checkInvalidCursor(t *testing.T, cursor string, err error) {
+	t.Helper()
+	var appErr *apperr.Error
+	if !errors.As(err, &appErr) || appErr.Key != "invalid_cursor" {
+		t.Errorf("cursor %q: got %v, want invalid_cursor", cursor, err)
+	}
+}
+
+// tamperedCursors are cursors the frontend could send that encodeCursor never produces
+var tamperedCursors = []string{
+	"not a cursor!",
+	base64.RawURLEncoding.EncodeToString([]byte("{")),
+	base64.RawURLEncoding.EncodeToString([]byte(`{"n":"x","i":0}`)),
+	base64.RawURLEncoding.EncodeToString([]byte(`{"n":"x","i":-3}`)),
+	base64.RawURLEncoding.EncodeToString([]byte(`{"n":"x","i":"1"}`)),
+}
+
+func TestListClientsAfter(t *testing.T) {
+	// two clients share a name and are ordered by id; a deleted one is skipped
+	repo := newCursorRepository(t, `
+		INSERT INTO client (id, name) VALUES (1, 'Karim'), (2, 'Amine'), (3, 'Karim'), (4, 'Zohra'), (5, 'Bilal'), (6, 'Amel')`,
+		`UPDATE client SET deleted_at = CURRENT_TIMESTAMP WHERE id = 6`,
+	)
+	ctx := context.Background()
+	pageOf := func(query string, limit int) func(string) ([]int64, string, error) {
+		return func(cursor string) ([]int64, string, error) {
+			result, err := repo.ListClientsAfter(ctx, query, cursor, limit)
+			if err != nil {
+				return nil, "", err
+			}
+			ids := []int64{}
+			for _, c := range result.Data {
+				ids = append(ids, c.ID)
+			}
+			return ids, result.NextCursor, nil
+		}
+	}
+
+	// Amine, Bilal, Karim (1), Karim (3), Zohra
+	checkPages(t, collectPages(t, pageOf("", 2)), [][]int64{{2, 5}, {1, 3}, {4}})
+	// the tie spans two pages
+	checkPages(t, collectPages(t, pageOf("", 3)), [][]int64{{2, 5, 1}, {3, 4}})
+	// a page that ends exactly on the last row has no next cursor
+	checkPages(t, collectPages(t, pageOf("", 5)), [][]int64{{2, 5, 1, 3, 4}})
+	checkPages(t, collectPages(t, pageOf("Karim", 1)), [][]int64{{1}, {3}})
+
+	// order cursors carry no name
+	for _, cursor := range append(tamperedCursors, encodeCursor(pageCursor{ID: 1})) {
+		_, err := repo.ListClientsAfter(ctx, "", cursor, 2)
+		checkInvalidCursor(t, cursor, err)
+	}
+}
+
+func TestListProductsAfter(t *testing.T) {
+	repo := newCursorRepository(t, `
+		INSERT INTO product (id, sku, name, active) VALUES
+			(1, 'S-1', 'Sucre', 1), (2, 'F-1', 'Farine', 1), (3, 'S-2', 'Sucre', 0),
+			(4, 'H-1', 'Huile', 1), (5, 'C-1', 'Café', 1), (6, 'T-1', 'Thé', 1)`,
+		`UPDATE product SET deleted_at = CURRENT_TIMESTAMP WHERE id = 6`,
+	)
+	ctx := context.Background()
+	pageOf := func(active *bool, limit int) func(string) ([]int64, string, error) {
+		return func(cursor string) ([]int64, string, error) {
+			result, err := repo.ListProductsAfter(ctx, "", active, nil, cursor, limit)
+			if err != nil {
+				return nil, "", err
+			}
+			ids := []int64{}
+			for _, p := range result.Data {
+				ids = append(ids, p.ID)
+			}
+			return ids, result.NextCursor, nil
+		}
+	}
+
+	// Café, Farine, Huile, Sucre (1), Sucre (3)
+	checkPages(t, collectPages(t, pageOf(nil, 2)), [][]int64{{5, 2}, {4, 1}, {3}})
+	checkPages(t, collectPages(t, pageOf(nil, 4)), [][]int64{{5, 2, 4, 1}, {3}})
+	active := true
+	checkPages(t, collectPages(t, pageOf(&active, 2)), [][]int64{{5, 2}, {4, 1}})
+
+	for _, cursor := range tamperedCursors {
+		_, err := repo.ListProductsAfter(ctx, "", nil, nil, cursor, 2)
+		checkInvalidCursor(t, cursor, err)
+	}
+}
+
+func TestListOrdersAfter(t *testing.T) {
+	stmts := []string{`INSERT INTO client (id, name) VALUES (1, 'Karim'), (2, 'Amine')`}
+	for id := 1; id <= 7; id++ {
+		stmts = append(stmts, fmt.Sprintf(`INSERT INTO "order" (id, order_number, client_id) VALUES (%d, 'ORD-%06d', %d)`, id, id, id%2+1))
+	}
+	repo := newCursorRepository(t, stmts...)
+	ctx := context.Background()
+	pageOf := func(filters OrderFilters, limit int) func(string) ([]int64, string, error) {
+		return func(cursor string) ([]int64, string, error) {
+			result, err := repo.ListOrdersAfter(ctx, filters, cursor, limit)
+			if err != nil {
+				return nil, "", err
+			}
+			ids := []int64{}
+			for _, o := range result.Data {
+				ids = append(ids, o.Order.ID)
+			}
+			return ids, result.NextCursor, nil
+		}
+	}
+
+	checkPages(t, collectPages(t, pageOf(OrderFilters{SkipItems: true}, 3)), [][]int64{{7, 6, 5}, {4, 3, 2}, {1}})
+	checkPages(t, collectPages(t, pageOf(OrderFilters{}, 7)), [][]int64{{7, 6, 5, 4, 3, 2, 1}})
+	client := int64(1)
+	checkPages(t, collectPages(t, pageOf(OrderFilters{ClientID: &client}, 2)), [][]int64{{6, 4}, {2}})
+
+	sort := "total"
+	if _, err := repo.ListOrdersAfter(ctx, OrderFilters{Sort: &sort}, "", 2); err == nil {
+		t.Error("want an error when sorting with a cursor")
+	}
+	for _, cursor := range tamperedCursors {
+		_, err := repo.ListOrdersAfter(ctx, OrderFilters{}, cursor, 2)
+		checkInvalidCursor(t, cursor, err)
+	}
+}
+
+func TestGetDebtPaymentsAfter(t *testing.T) {
+	stmts := []string{`INSERT INTO client (id, name) VALUES (1, 'Karim')`}
+	for id := 1; id <= 5; id++ {
+		stmts = append(stmts, fmt.Sprintf(`
+			INSERT INTO debt_payment (id, client_id, previous_debt_cents, new_debt_cents, adjustment_cents, type)
+			VALUES (%d, 1, %d, %d, 100, 'INCREASE')`, id, (id-1)*100, id*100))
+	}
+	repo := newCursorRepository(t, stmts...)
+	ctx := context.Background()
+	pageOf := func(limit int) func(string) ([]int64, string, error) {
+		return func(cursor string) ([]int64, string, error) {
+			result, err := repo.GetDebtPaymentsAfter(ctx, cursor, limit)
+			if err != nil {
+				return nil, "", err
+			}
+			ids := []int64{}
+			for _, p := range result.Data {
+				if p.Client.Name != "Karim" {
+					return nil, "", fmt.Errorf("payment %d: got client %q", p.DebtPayment.ID, p.Client.Name)
+				}
+				ids = append(ids, p.DebtPayment.ID)
+			}
+			return ids, result.NextCursor, nil
+		}
+	}
+
+	checkPages(t, collectPages(t, pageOf(2)), [][]int64{{5, 4}, {3, 2}, {1}})
+	checkPages(t, collectPages(t, pageOf(5)), [][]int64{{5, 4, 3, 2, 1}})
+
+	for _, cursor := range tamperedCursors {
+		_, err := repo.GetDebtPaymentsAfter(ctx, cursor, 2)
+		checkInvalidCursor(t, cursor, err)
+	}
+}
@@ -0,0 +1,152 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	apperr "barakaERP/backend/domain/errors"
+)
+
+// Discount rule operations
+
+const discountRuleColumns = `id, name, product_id, category_id, min_qty, percent, valid_from, valid_to, active, created_at, updated_at`
+
+func scanDiscountRule(row interface{ Scan(...interface{}) error }, rule *DiscountRule) error {
+	return row.Scan(&rule.ID, &rule.Name, &rule.ProductID, &rule.CategoryID, &rule.MinQty, &rule.Percent,
+		&rule.ValidFrom, &rule.ValidTo, &rule.Active, &rule.CreatedAt, &rule.UpdatedAt)
+}
+
+// ListDiscountRules lists all discount rules, active ones first
+func (r *Repository) ListDiscountRules(ctx context.Context) ([]DiscountRule, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+discountRuleColumns+` FROM discount_rule ORDER BY active DESC, name, min_qty`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list discount rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := []DiscountRule{}
+	for rows.Next() {
+		var rule DiscountRule
+		if err := scanDiscountRule(rows, &rule); err != nil {
+			return nil, fmt.Errorf("failed to scan discount rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate discount rules: %w", err)
+	}
+	return rules, nil
+}
+
+// GetDiscountRule retrieves a discount rule by ID
+func (r *Repository) GetDiscountRule(ctx context.Context, id int64) (*DiscountRule, error) {
+	return r.getDiscountRuleWith(ctx, r.db, id)
+}
+
+func (r *Repository) getDiscountRuleWith(ctx context.Context, q querier, id int64) (*DiscountRule, error) {
+	var rule DiscountRule
+	if err := scanDiscountRule(q.QueryRowContext(ctx, `SELECT `+discountRuleColumns+` FROM discount_rule WHERE id = ?`, id), &rule); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperr.NotFound("discount_rule_not_found")
+		}
+		return nil, fmt.Errorf("failed to get discount rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// SaveDiscountRule creates a discount rule, or updates it when rule.ID is set
+func (r *Repository) SaveDiscountRule(ctx context.Context, rule DiscountRule) (*DiscountRule, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var before interface{} // nil on create
+	action := AuditActionCreate
+	if rule.ID > 0 {
+		existing, err := r.getDiscountRuleWith(ctx, tx, rule.ID)
+		if err != nil {
+			return nil, err
+		}
+		before, action = existing, AuditActionUpdate
+		_, err = tx.ExecContext(ctx, `
+			UPDATE discount_rule
+			SET name = ?, product_id = ?, category_id = ?, min_qty = ?, percent = ?, valid_from = ?, valid_to = ?, active = ?, updated_at = CURRENT_TIMESTAMP
+			WHERE id = ?
+		`, rule.Name, rule.ProductID, rule.CategoryID, rule.MinQty, rule.Percent, rule.ValidFrom, rule.ValidTo, rule.Active, rule.ID)
+	} else {
+		var result sql.Result
+		result, err = tx.ExecContext(ctx, `
+			INSERT INTO discount_rule (name, product_id, category_id, min_qty, percent, valid_from, valid_to, active, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		`, rule.Name, rule.ProductID, rule.CategoryID, rule.MinQty, rule.Percent, rule.ValidFrom, rule.ValidTo, rule.Active)
+		if err == nil {
+			rule.ID, err = result.LastInsertId()
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save discount rule: %w", err)
+	}
+
+	after, err := r.getDiscountRuleWith(ctx, tx, rule.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.writeAudit(ctx, tx, AuditEntityDiscountRule, rule.ID, action, before, after); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return after, nil
+}
+
+// DeleteDiscountRule removes a discount rule; order lines it discounted keep
+// their percentage
+func (r *Repository) DeleteDiscountRule(ctx context.Context, id int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil { return fmt.Errorf("failed to begin transaction: %w", err) }
+	defer tx.Rollback()
+
+	before, err := r.getDiscountRuleWith(ctx, tx, id)
+	if err != nil { return err }
+	if _, err := tx.ExecContext(ctx, `DELETE FROM discount_rule WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete discount rule: %w", err)
+	}
+	if err := r.writeAudit(ctx, tx, AuditEntityDiscountRule, id, AuditActionDelete, before, nil); err != nil { return err }
+	if err := tx.Commit(); err != nil { return fmt.Errorf("failed to commit transaction: %w", err) }
+	return nil
+}
+
+// FindDiscountRule returns the active rule giving the largest discount on qty
+// (in the product's base unit) of product on date (YYYY-MM-DD), or nil when
+// none applies. Rules for a variant's parent product and for the categories
+// above the product's category apply too. Among equal discounts the most
+// specific rule wins: product, then category, then any product.
+func (r *Repository) FindDiscountRule(ctx context.Context, product *Product, qty Quantity, date string) (*DiscountRule, error) {
+	query := `
+		SELECT ` + discountRuleColumns + `
+		FROM discount_rule
+		WHERE active = 1 AND min_qty * 1000 <= ?
+			AND (product_id IN (?, ?) OR (product_id IS NULL AND (category_id IS NULL OR category_id IN (
+				WITH RECURSIVE up(id) AS (
+					SELECT ?
+					UNION SELECT c.parent_id FROM category c JOIN up ON c.id = up.id WHERE c.parent_id IS NOT NULL
+				)
+				SELECT id FROM up
+			))))
+			AND (valid_from IS NULL OR valid_from <= ?)
+			AND (valid_to IS NULL OR valid_to >= ?)
+		ORDER BY percent DESC, product_id IS NULL, category_id IS NULL, min_qty DESC, id DESC
+		LIMIT 1
+	`
+	var rule DiscountRule
+	if err := scanDiscountRule(r.db.QueryRowContext(ctx, query, qty, product.ID, product.ParentID, product.CategoryID, date, date), &rule); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find discount rule: %w", err)
+	}
+	return &rule, nil
+}
@@ -6,8 +6,9 @@ import (
 
 // Common pagination result
 type PaginatedResult[T any] struct {
-	Data  []T `json:"data"`
-	Total int `json:"total"`
+	Data       []T    `json:"data"`
+	Total      int    `json:"total"`                 // not computed for cursor pages
+	NextCursor string `json:"next_cursor,omitempty"` // set on cursor pages when more rows follow
 }
 
 // Client represents a customer
@@ -19,6 +20,9 @@ type Client struct {
 	Address   *string    `json:"address" db:"address"`
 	CreatedAt time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt *time.Time `json:"updated_at" db:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at" db:"deleted_at"` // set while the client is in the recycle bin
+	// PriceListID is the client's default price list; nil for the products' own prices
+	PriceListID *int64 `json:"price_list_id" db:"price_list_id"`
 }
 
 // Product represents a sellable item
@@ -30,6 +34,78 @@ type Product struct {
 	UnitPriceCents int64      `json:"unit_price_cents" db:"unit_price_cents"`
 	Currency       string     `json:"currency" db:"currency"`
 	Active         bool       `json:"active" db:"active"`
+	CategoryID     *int64     `json:"category_id" db:"category_id"`
+	Unit           string     `json:"unit" db:"unit"`             // base unit of UnitPriceCents, e.g. piece, kg or m
+	ParentID       *int64     `json:"parent_id" db:"parent_id"`   // set on variants
+	Attributes     Attributes `json:"attributes" db:"attributes"` // what sets a variant apart, e.g. size and color
+	Stock          Quantity   `json:"stock" db:"stock_milli"`     // on hand, in Unit
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      *time.Time `json:"updated_at" db:"updated_at"`
+	DeletedAt      *time.Time `json:"deleted_at" db:"deleted_at"` // set while the product is in the recycle bin
+}
+
+// Attributes are the named values of a product variant, e.g. size: 30 cm
+type Attributes map[string]string
+
+// ProductUnit is a packaging unit a product is also sold in, holding Factor
+// of its base unit (e.g. a carton of 12 pieces); its price is Factor times
+// the base unit price
+type ProductUnit struct {
+	ID        int64      `json:"id" db:"id"`
+	ProductID int64      `json:"product_id" db:"product_id"`
+	Unit      string     `json:"unit" db:"unit"`
+	Factor    Quantity   `json:"factor" db:"factor_milli"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Category groups products, e.g. for discount rules; categories form a tree
+// through ParentID
+type Category struct {
+	ID        int64      `json:"id" db:"id"`
+	Name      string     `json:"name" db:"name"`
+	ParentID  *int64     `json:"parent_id" db:"parent_id"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// DiscountRule is an automatic line discount: Percent off lines of at least
+// MinQty base units of ProductID, of the products in CategoryID, or of any product
+// when both are nil, while the order date is within ValidFrom-ValidTo
+// (YYYY-MM-DD, nil for no bound). Volume tiers are rules with a MinQty,
+// promotions rules with validity dates.
+type DiscountRule struct {
+	ID         int64      `json:"id" db:"id"`
+	Name       string     `json:"name" db:"name"`
+	ProductID  *int64     `json:"product_id" db:"product_id"`
+	CategoryID *int64     `json:"category_id" db:"category_id"`
+	MinQty     int        `json:"min_qty" db:"min_qty"`
+	Percent    int        `json:"percent" db:"percent"`
+	ValidFrom  *string    `json:"valid_from" db:"valid_from"`
+	ValidTo    *string    `json:"valid_to" db:"valid_to"`
+	Active     bool       `json:"active" db:"active"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt  *time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// PriceList is a named set of product prices, e.g. retail, wholesale or distributor
+type PriceList struct {
+	ID        int64      `json:"id" db:"id"`
+	Name      string     `json:"name" db:"name"`
+	Currency  string     `json:"currency" db:"currency"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ProductPrice is the price of a product in a price list. Validity dates use
+// YYYY-MM-DD and are inclusive; nil leaves that side open.
+type ProductPrice struct {
+	ID             int64      `json:"id" db:"id"`
+	PriceListID    int64      `json:"price_list_id" db:"price_list_id"`
+	ProductID      int64      `json:"product_id" db:"product_id"`
+	UnitPriceCents int64      `json:"unit_price_cents" db:"unit_price_cents"`
+	ValidFrom      *string    `json:"valid_from" db:"valid_from"`
+	ValidTo        *string    `json:"valid_to" db:"valid_to"`
 	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt      *time.Time `json:"updated_at" db:"updated_at"`
 }
@@ -55,16 +131,18 @@ type Order struct {
 
 // OrderItem represents a line item in an order
 type OrderItem struct {
-	ID              int64   `json:"id" db:"id"`
-	OrderID         int64   `json:"order_id" db:"order_id"`
-	ProductID       *int64  `json:"product_id" db:"product_id"`
-	NameSnapshot    string  `json:"name_snapshot" db:"name_snapshot"`
-	SKUSnapshot     *string `json:"sku_snapshot" db:"sku_snapshot"`
-	Qty             int     `json:"qty" db:"qty"`
-	UnitPriceCents  int64   `json:"unit_price_cents" db:"unit_price_cents"`
-	DiscountPercent int     `json:"discount_percent" db:"discount_percent"`
-	Currency        string  `json:"currency" db:"currency"`
-	TotalCents      int64   `json:"total_cents" db:"total_cents"`
+	ID              int64    `json:"id" db:"id"`
+	OrderID         int64    `json:"order_id" db:"order_id"`
+	ProductID       *int64   `json:"product_id" db:"product_id"`
+	NameSnapshot    string   `json:"name_snapshot" db:"name_snapshot"`
+	SKUSnapshot     *string  `json:"sku_snapshot" db:"sku_snapshot"`
+	Qty             Quantity `json:"qty" db:"qty_milli"`
+	Unit            string   `json:"unit" db:"unit"` // unit of Qty and UnitPriceCents, e.g. piece, kg or carton
+	UnitPriceCents  int64    `json:"unit_price_cents" db:"unit_price_cents"`
+	DiscountPercent int      `json:"discount_percent" db:"discount_percent"`
+	DiscountRuleID  *int64   `json:"discount_rule_id" db:"discount_rule_id"` // rule that set DiscountPercent, nil when entered by hand
+	Currency        string   `json:"currency" db:"currency"`
+	TotalCents      int64    `json:"total_cents" db:"total_cents"`
 }
 
 // Invoice represents a bill sent to customer
@@ -88,15 +166,16 @@ type Invoice struct {
 
 // InvoiceItem represents a line item in an invoice
 type InvoiceItem struct {
-	ID             int64   `json:"id" db:"id"`
-	InvoiceID      int64   `json:"invoice_id" db:"invoice_id"`
-	ProductID      *int64  `json:"product_id" db:"product_id"`
-	NameSnapshot   string  `json:"name_snapshot" db:"name_snapshot"`
-	SKUSnapshot    *string `json:"sku_snapshot" db:"sku_snapshot"`
-	Qty            int     `json:"qty" db:"qty"`
-	UnitPriceCents int64   `json:"unit_price_cents" db:"unit_price_cents"`
-	Currency       string  `json:"currency" db:"currency"`
-	TotalCents     int64   `json:"total_cents" db:"total_cents"`
+	ID             int64    `json:"id" db:"id"`
+	InvoiceID      int64    `json:"invoice_id" db:"invoice_id"`
+	ProductID      *int64   `json:"product_id" db:"product_id"`
+	NameSnapshot   string   `json:"name_snapshot" db:"name_snapshot"`
+	SKUSnapshot    *string  `json:"sku_snapshot" db:"sku_snapshot"`
+	Qty            Quantity `json:"qty" db:"qty_milli"`
+	Unit           string   `json:"unit" db:"unit"`
+	UnitPriceCents int64    `json:"unit_price_cents" db:"unit_price_cents"`
+	Currency       string   `json:"currency" db:"currency"`
+	TotalCents     int64    `json:"total_cents" db:"total_cents"`
 }
 
 // Payment represents a payment made against an invoice
@@ -129,6 +208,67 @@ type DebtPaymentDetail struct {
 	Client      Client      `json:"client"`
 }
 
+// AuditEntry records a single create, update or delete on an entity
+type AuditEntry struct {
+	ID        int64     `json:"id" db:"id"`
+	Entity    string    `json:"entity" db:"entity"`
+	EntityID  int64     `json:"entity_id" db:"entity_id"`
+	Action    string    `json:"action" db:"action"`
+	Before    *string   `json:"before" db:"before_json"` // JSON state before the change (nil on create)
+	After     *string   `json:"after" db:"after_json"`   // JSON state after the change (nil on delete)
+	User      string    `json:"user" db:"user"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// LicensedUser is a named user holding a licensed seat
+type LicensedUser struct {
+	Name      string    `json:"name" db:"name"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// CompanySettings is the company profile printed in document headers and footers
+type CompanySettings struct {
+	NameAR     string     `json:"name_ar" db:"name_ar"`
+	NameFR     *string    `json:"name_fr" db:"name_fr"`
+	Address    *string    `json:"address" db:"address"`
+	Phones     []string   `json:"phones" db:"phones"`
+	RC         *string    `json:"rc" db:"rc"`   // Registre de commerce
+	NIF        *string    `json:"nif" db:"nif"` // Numéro d'identification fiscale
+	NIS        *string    `json:"nis" db:"nis"` // Numéro d'identification statistique
+	AI         *string    `json:"ai" db:"ai"`   // Article d'imposition
+	Logo       []byte     `json:"logo" db:"logo"`
+	LogoType   *string    `json:"logo_type" db:"logo_type"` // PNG or JPG
+	FooterText *string    `json:"footer_text" db:"footer_text"`
+	UpdatedAt  *time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// AppSettings configures an installation. Each field is stored as its own
+// row of the settings table, so fields added later read their default until saved.
+type AppSettings struct {
+	DefaultCurrency  string `json:"default_currency"`  // for products and order items given none
+	DefaultPageSize  int    `json:"default_page_size"` // list size when the caller asks for none
+	MaxPageSize      int    `json:"max_page_size"`
+	LicenseServerURL string `json:"license_server_url"` // where renewed license files are fetched; empty disables it
+	WindowWidth      int    `json:"window_width"`
+	WindowHeight     int    `json:"window_height"`
+	// LogLevel (debug, info, warn or error) applies to subsystems without
+	// their own entry in LogLevels, e.g. {"orders": "debug"}
+	LogLevel  string            `json:"log_level"`
+	LogLevels map[string]string `json:"log_levels"`
+	// Locale (ar, en or fr) is used for messages and documents unless a
+	// request chooses another; DocumentLocale overrides it for documents
+	Locale         string `json:"locale"`
+	DocumentLocale string `json:"document_locale,omitempty"`
+	// EnforceListPrices rejects order lines priced differently from the
+	// client's quote unless an admin enters them; otherwise quotes only fill
+	// lines sent without a price
+	EnforceListPrices bool `json:"enforce_list_prices"`
+	// DiscountCaps is the largest discount percentage each role (e.g.
+	// {"CASHIER": 10}) may give by hand, on a line or on a whole order;
+	// roles without an entry are not capped. Discounts from rules always apply.
+	DiscountCaps map[string]int `json:"discount_caps"`
+}
+
 // DTOs for complex operations
 
 // OrderDetail includes order with client and items
@@ -142,6 +282,37 @@ type OrderDetail struct {
 	TotalCents    int64       `json:"total_cents"`
 }
 
+// PriceListDetail includes a price list with its product prices
+type PriceListDetail struct {
+	PriceList PriceList      `json:"price_list"`
+	Prices    []ProductPrice `json:"prices"`
+}
+
+// PriceQuote is the price a client pays for a quantity of a product
+type PriceQuote struct {
+	ClientID       int64    `json:"client_id"`
+	ProductID      int64    `json:"product_id"`
+	Qty            Quantity `json:"qty"`
+	Unit           string   `json:"unit"`
+	UnitPriceCents int64    `json:"unit_price_cents"`
+	Currency       string   `json:"currency"`
+	TotalCents     int64    `json:"total_cents"`
+	// PriceListID and PriceListName name the list the price comes from; nil
+	// when the product's own price applies
+	PriceListID   *int64  `json:"price_list_id"`
+	PriceListName *string `json:"price_list_name"`
+}
+
+// ScannedDocument is the order behind the QR code of a printed order or invoice
+type ScannedDocument struct {
+	Kind   string       `json:"kind"` // order or invoice
+	Number string       `json:"number"`
+	Order  *OrderDetail `json:"order"`
+	// Current is false when the printed date or total no longer match the
+	// stored document, i.e. the paper copy predates an edit
+	Current bool `json:"current"`
+}
+
 // InvoiceDetail includes invoice with client, items and payments
 type InvoiceDetail struct {
 	Invoice      Invoice       `json:"invoice"`
@@ -164,13 +335,15 @@ type OrderDraft struct {
 
 // OrderItemDraft for creating order items
 type OrderItemDraft struct {
-	ProductID       *int64  `json:"product_id"`
-	NameSnapshot    string  `json:"name_snapshot"`
-	SKUSnapshot     *string `json:"sku_snapshot"`
-	Qty             int     `json:"qty"`
-	UnitPriceCents  int64   `json:"unit_price_cents"`
-	DiscountPercent int     `json:"discount_percent"`
-	Currency        string  `json:"currency"`
+	ProductID       *int64   `json:"product_id"`
+	NameSnapshot    string   `json:"name_snapshot"`
+	SKUSnapshot     *string  `json:"sku_snapshot"`
+	Qty             Quantity `json:"qty"`
+	Unit            string   `json:"unit"` // the product's unit when empty
+	UnitPriceCents  int64    `json:"unit_price_cents"`
+	DiscountPercent int      `json:"discount_percent"`
+	DiscountRuleID  *int64   `json:"discount_rule_id"` // set by the discount rules, not by callers
+	Currency        string   `json:"currency"`
 }
 
 // OrderUpdate for updating orders
@@ -218,20 +391,52 @@ type InvoiceUpdate struct {
 
 // InvoiceItemDraft for creating/updating invoice items
 type InvoiceItemDraft struct {
-	ProductID      *int64  `json:"product_id"`
-	NameSnapshot   string  `json:"name_snapshot"`
-	SKUSnapshot    *string `json:"sku_snapshot"`
-	Qty            int     `json:"qty"`
-	UnitPriceCents int64   `json:"unit_price_cents"`
-	Currency       string  `json:"currency"`
+	ProductID      *int64   `json:"product_id"`
+	NameSnapshot   string   `json:"name_snapshot"`
+	SKUSnapshot    *string  `json:"sku_snapshot"`
+	Qty            Quantity `json:"qty"`
+	Unit           string   `json:"unit"`
+	UnitPriceCents int64    `json:"unit_price_cents"`
+	Currency       string   `json:"currency"`
 }
 
-// OrderFilters for filtering orders list
+// OrderFilters for filtering orders list. All filters combine with AND;
+// date bounds are inclusive calendar days in YYYY-MM-DD format.
+//
+// Sort is "field" or "field:asc|desc" (ascending by default) with field one of
+// id, order_number, status, issue_date, due_date, created_at, client_name or
+// total; orders are listed newest first when it is empty.
 type OrderFilters struct {
-	ClientID *int64  `json:"client_id"`
-	Status   *string `json:"status"`
-	Query    *string `json:"query"`
-	Sort     *string `json:"sort"`
+	ClientID         *int64  `json:"client_id"`
+	Status           *string `json:"status"`
+	Query            *string `json:"query"`
+	Sort             *string `json:"sort"` // "field" or "field:asc|desc", fields listed above
+	IssueDateFrom    *string `json:"issue_date_from"`
+	IssueDateTo      *string `json:"issue_date_to"`
+	DueDateFrom      *string `json:"due_date_from"`
+	DueDateTo        *string `json:"due_date_to"`
+	MinTotalCents    *int64  `json:"min_total_cents"`
+	MaxTotalCents    *int64  `json:"max_total_cents"`
+	ProductID        *int64  `json:"product_id"`         // orders containing this product
+	HasUnpaidInvoice *bool   `json:"has_unpaid_invoice"` // orders with (true) or without (false) an outstanding invoice
+	SkipItems        bool    `json:"skip_items"`         // return SQL-computed totals only, without line items
+}
+
+// SearchResult is a single hit from the global search
+type SearchResult struct {
+	Type     string  `json:"type"` // client, product or order
+	ID       int64   `json:"id"`
+	Title    string  `json:"title"`
+	Subtitle string  `json:"subtitle"`
+	Rank     float64 `json:"rank"` // bm25 score, lower is better
+}
+
+// AuditFilters for browsing the audit log
+type AuditFilters struct {
+	Entity   *string    `json:"entity"`
+	EntityID *int64     `json:"entity_id"`
+	From     *time.Time `json:"from"`
+	To       *time.Time `json:"to"`
 }
 
 // DashboardData for dashboard metrics
@@ -277,4 +482,21 @@ const (
 
 	DebtPaymentTypeIncrease = "INCREASE"
 	DebtPaymentTypeDecrease = "DECREASE"
+
+	AuditActionCreate = "CREATE"
+	AuditActionUpdate = "UPDATE"
+	AuditActionDelete = "DELETE"
+
+	AuditEntityClient       = "client"
+	AuditEntityProduct      = "product"
+	AuditEntityOrder        = "order"
+	AuditEntityInvoice      = "invoice"
+	AuditEntityDebtPayment  = "debt_payment"
+	AuditEntityCompany      = "company_settings"
+	AuditEntitySettings     = "settings"
+	AuditEntityPriceList    = "price_list"
+	AuditEntityPrice        = "product_price"
+	AuditEntityCategory     = "category"
+	AuditEntityDiscountRule = "discount_rule"
+	AuditEntityProductUnit  = "product_unit"
 )
@@ -0,0 +1,314 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	apperr "barakaERP/backend/domain/errors"
+)
+
+// Price list operations
+
+// ListPriceLists lists all price lists by name
+func (r *Repository) ListPriceLists(ctx context.Context) ([]PriceList, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, name, currency, created_at, updated_at FROM price_list ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list price lists: %w", err)
+	}
+	defer rows.Close()
+
+	lists := []PriceList{}
+	for rows.Next() {
+		var list PriceList
+		if err := rows.Scan(&list.ID, &list.Name, &list.Currency, &list.CreatedAt, &list.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan price list: %w", err)
+		}
+		lists = append(lists, list)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate price lists: %w", err)
+	}
+	return lists, nil
+}
+
+// GetPriceList retrieves a price list without its prices
+func (r *Repository) GetPriceList(ctx context.Context, id int64) (*PriceList, error) {
+	return r.getPriceListWith(ctx, r.db, id)
+}
+
+// GetPriceListDetail retrieves a price list with its product prices
+func (r *Repository) GetPriceListDetail(ctx context.Context, id int64) (*PriceListDetail, error) {
+	list, err := r.getPriceListWith(ctx, r.db, id)
+	if err != nil {
+		return nil, err
+	}
+	query := `
+		SELECT id, price_list_id, product_id, unit_price_cents, valid_from, valid_to, created_at, updated_at
+		FROM product_price
+		WHERE price_list_id = ?
+		ORDER BY product_id, valid_from IS NOT NULL, valid_from
+	`
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list product prices: %w", err)
+	}
+	defer rows.Close()
+
+	detail := &PriceListDetail{PriceList: *list, Prices: []ProductPrice{}}
+	for rows.Next() {
+		var price ProductPrice
+		if err := scanProductPrice(rows, &price); err != nil {
+			return nil, fmt.Errorf("failed to scan product price: %w", err)
+		}
+		detail.Prices = append(detail.Prices, price)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate product prices: %w", err)
+	}
+	return detail, nil
+}
+
+func (r *Repository) getPriceListWith(ctx context.Context, q querier, id int64) (*PriceList, error) {
+	var list PriceList
+	err := q.QueryRowContext(ctx, `SELECT id, name, currency, created_at, updated_at FROM price_list WHERE id = ?`, id).
+		Scan(&list.ID, &list.Name, &list.Currency, &list.CreatedAt, &list.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperr.NotFound("price_list_not_found")
+		}
+		return nil, fmt.Errorf("failed to get price list: %w", err)
+	}
+	return &list, nil
+}
+
+// CreatePriceList creates an empty price list
+func (r *Repository) CreatePriceList(ctx context.Context, list PriceList) (*PriceList, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO price_list (name, currency, created_at, updated_at)
+		VALUES (?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+	`, list.Name, list.Currency)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, apperr.Conflict("price_list_name_taken").Wrap(err)
+		}
+		return nil, fmt.Errorf("failed to create price list: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price list ID: %w", err)
+	}
+
+	created, err := r.getPriceListWith(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.writeAudit(ctx, tx, AuditEntityPriceList, id, AuditActionCreate, nil, created); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return created, nil
+}
+
+// UpdatePriceList renames a price list or changes its currency
+func (r *Repository) UpdatePriceList(ctx context.Context, list PriceList) (*PriceList, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := r.getPriceListWith(ctx, tx, list.ID)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE price_list SET name = ?, currency = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`,
+		list.Name, list.Currency, list.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, apperr.Conflict("price_list_name_taken").Wrap(err)
+		}
+		return nil, fmt.Errorf("failed to update price list: %w", err)
+	}
+
+	after, err := r.getPriceListWith(ctx, tx, list.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.writeAudit(ctx, tx, AuditEntityPriceList, list.ID, AuditActionUpdate, before, after); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return after, nil
+}
+
+// DeletePriceList removes a price list and its prices. Clients using it fall
+// back to the products' own prices.
+func (r *Repository) DeletePriceList(ctx context.Context, id int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil { return fmt.Errorf("failed to begin transaction: %w", err) }
+	defer tx.Rollback()
+
+	before, err := r.getPriceListWith(ctx, tx, id)
+	if err != nil { return err }
+	if _, err := tx.ExecContext(ctx, `DELETE FROM price_list WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete price list: %w", err)
+	}
+	if err := r.writeAudit(ctx, tx, AuditEntityPriceList, id, AuditActionDelete, before, nil); err != nil { return err }
+	if err := tx.Commit(); err != nil { return fmt.Errorf("failed to commit transaction: %w", err) }
+	return nil
+}
+
+// Product price operations
+
+func scanProductPrice(row interface{ Scan(...interface{}) error }, price *ProductPrice) error {
+	return row.Scan(&price.ID, &price.PriceListID, &price.ProductID, &price.UnitPriceCents,
+		&price.ValidFrom, &price.ValidTo, &price.CreatedAt, &price.UpdatedAt)
+}
+
+func (r *Repository) getProductPriceWith(ctx context.Context, q querier, id int64) (*ProductPrice, error) {
+	query := `
+		SELECT id, price_list_id, product_id, unit_price_cents, valid_from, valid_to, created_at, updated_at
+		FROM product_price
+		WHERE id = ?
+	`
+	var price ProductPrice
+	if err := scanProductPrice(q.QueryRowContext(ctx, query, id), &price); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperr.NotFound("product_price_not_found")
+		}
+		return nil, fmt.Errorf("failed to get product price: %w", err)
+	}
+	return &price, nil
+}
+
+// SaveProductPrice creates a product price, or updates it when price.ID is set
+func (r *Repository) SaveProductPrice(ctx context.Context, price ProductPrice) (*ProductPrice, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var before interface{} // nil on create
+	action := AuditActionCreate
+	if price.ID > 0 {
+		existing, err := r.getProductPriceWith(ctx, tx, price.ID)
+		if err != nil {
+			return nil, err
+		}
+		before, action = existing, AuditActionUpdate
+		_, err = tx.ExecContext(ctx, `
+			UPDATE product_price
+			SET price_list_id = ?, product_id = ?, unit_price_cents = ?, valid_from = ?, valid_to = ?, updated_at = CURRENT_TIMESTAMP
+			WHERE id = ?
+		`, price.PriceListID, price.ProductID, price.UnitPriceCents, price.ValidFrom, price.ValidTo, price.ID)
+	} else {
+		var result sql.Result
+		result, err = tx.ExecContext(ctx, `
+			INSERT INTO product_price (price_list_id, product_id, unit_price_cents, valid_from, valid_to, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		`, price.PriceListID, price.ProductID, price.UnitPriceCents, price.ValidFrom, price.ValidTo)
+		if err == nil {
+			price.ID, err = result.LastInsertId()
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save product price: %w", err)
+	}
+
+	after, err := r.getProductPriceWith(ctx, tx, price.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.writeAudit(ctx, tx, AuditEntityPrice, price.ID, action, before, after); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return after, nil
+}
+
+// DeleteProductPrice removes a product price from its list
+func (r *Repository) DeleteProductPrice(ctx context.Context, id int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil { return fmt.Errorf("failed to begin transaction: %w", err) }
+	defer tx.Rollback()
+
+	before, err := r.getProductPriceWith(ctx, tx, id)
+	if err != nil { return err }
+	if _, err := tx.ExecContext(ctx, `DELETE FROM product_price WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete product price: %w", err)
+	}
+	if err := r.writeAudit(ctx, tx, AuditEntityPrice, id, AuditActionDelete, before, nil); err != nil { return err }
+	if err := tx.Commit(); err != nil { return fmt.Errorf("failed to commit transaction: %w", err) }
+	return nil
+}
+
+// FindProductPrice returns the price of a product in a price list on date
+// (YYYY-MM-DD), or nil when the list has none. When validity periods
+// overlap, the one starting last wins.
+func (r *Repository) FindProductPrice(ctx context.Context, priceListID, productID int64, date string) (*ProductPrice, error) {
+	query := `
+		SELECT id, price_list_id, product_id, unit_price_cents, valid_from, valid_to, created_at, updated_at
+		FROM product_price
+		WHERE price_list_id = ? AND product_id = ?
+			AND (valid_from IS NULL OR valid_from <= ?)
+			AND (valid_to IS NULL OR valid_to >= ?)
+		ORDER BY valid_from IS NULL, valid_from DESC, id DESC
+		LIMIT 1
+	`
+	var price ProductPrice
+	if err := scanProductPrice(r.db.QueryRowContext(ctx, query, priceListID, productID, date, date), &price); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find product price: %w", err)
+	}
+	return &price, nil
+}
+
+// SetClientPriceList sets (or clears, when priceListID is nil) the default
+// price list of a client
+func (r *Repository) SetClientPriceList(ctx context.Context, clientID int64, priceListID *int64) (*Client, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := r.getClientWith(ctx, tx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if priceListID != nil {
+		if _, err := r.getPriceListWith(ctx, tx, *priceListID); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE client SET price_list_id = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`, priceListID, clientID); err != nil {
+		return nil, fmt.Errorf("failed to set client price list: %w", err)
+	}
+
+	after, err := r.getClientWith(ctx, tx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.writeAudit(ctx, tx, AuditEntityClient, clientID, AuditActionUpdate, before, after); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return after, nil
+}
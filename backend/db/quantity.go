@@ -0,0 +1,131 @@
+package db
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// QuantityScale is the number of Quantity steps in one unit
+const QuantityScale = 1000
+
+// DefaultUnit is the unit of products and order lines given none
+const DefaultUnit = "piece"
+
+// Quantity is an exact decimal quantity in thousandths of a unit (1.5 kg is
+// 1500), stored as such in qty_milli columns. It encodes to JSON as a plain
+// decimal number and decodes from a number or a numeric string.
+type Quantity int64
+
+// Units returns n whole units
+func Units(n int64) Quantity { return Quantity(n * QuantityScale) }
+
+// QuantityFromFloat rounds f to the nearest thousandth, for values that
+// arrive as float64 (e.g. untyped JSON from the frontend)
+func QuantityFromFloat(f float64) Quantity {
+	return Quantity(math.Round(f * QuantityScale))
+}
+
+// ParseQuantity parses a decimal such as "12", "1.5" or "0.125" exactly; a
+// comma is accepted as the decimal separator. Values with more than three
+// decimals or beyond the range of Quantity are rejected.
+func ParseQuantity(s string) (Quantity, error) {
+	s = strings.TrimSpace(strings.Replace(s, ",", ".", 1))
+	neg := strings.HasPrefix(s, "-")
+	whole, frac, _ := strings.Cut(strings.TrimPrefix(s, "-"), ".")
+	if whole == "" && frac == "" || len(frac) > 3 || strings.ContainsAny(whole+frac, "+-") {
+		return 0, fmt.Errorf("invalid quantity %q", s)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid quantity %q", s)
+	}
+	var milli int64
+	if frac != "" {
+		if milli, err = strconv.ParseInt((frac + "00")[:3], 10, 64); err != nil {
+			return 0, fmt.Errorf("invalid quantity %q", s)
+		}
+	}
+	if units > (math.MaxInt64-milli)/QuantityScale {
+		return 0, fmt.Errorf("quantity %q out of range", s)
+	}
+	q := Quantity(units*QuantityScale + milli)
+	if neg {
+		q = -q
+	}
+	return q, nil
+}
+
+// String formats the quantity without trailing zeros, e.g. 2, 1.5 or 0.125
+func (q Quantity) String() string {
+	sign := ""
+	if q < 0 {
+		sign, q = "-", -q
+	}
+	s := sign + strconv.FormatInt(int64(q)/QuantityScale, 10)
+	if frac := int64(q) % QuantityScale; frac != 0 {
+		s += "." + strings.TrimRight(strconv.FormatInt(frac+QuantityScale, 10)[1:], "0")
+	}
+	return s
+}
+
+// MarshalJSON encodes the quantity as a decimal number
+func (q Quantity) MarshalJSON() ([]byte, error) {
+	return []byte(q.String()), nil
+}
+
+// UnmarshalJSON decodes a decimal number or numeric string without going
+// through float64
+func (q *Quantity) UnmarshalJSON(data []byte) error {
+	data = bytes.Trim(data, `"`)
+	if string(data) == "null" {
+		return nil
+	}
+	if bytes.ContainsAny(data, "eE") { // exponent: only reachable from float encoders
+		f, err := strconv.ParseFloat(string(data), 64)
+		if err != nil {
+			return fmt.Errorf("invalid quantity %s", data)
+		}
+		if math.Abs(f*QuantityScale) >= math.MaxInt64 {
+			return fmt.Errorf("quantity %s out of range", data)
+		}
+		*q = QuantityFromFloat(f)
+		return nil
+	}
+	parsed, err := ParseQuantity(string(data))
+	if err != nil {
+		return err
+	}
+	*q = parsed
+	return nil
+}
+
+// Amount prices the quantity at unitCents per unit, rounding half-cents up
+func (q Quantity) Amount(unitCents int64) int64 {
+	return roundScaled(int64(q) * unitCents)
+}
+
+// Times converts the quantity with factor, e.g. 2 cartons of 12 pieces
+// (factor 12) are 24 pieces
+func (q Quantity) Times(factor Quantity) Quantity {
+	return Quantity(roundScaled(int64(q) * int64(factor)))
+}
+
+// CeilUnits is the quantity rounded up to whole units, kept in the legacy
+// integer qty columns
+func (q Quantity) CeilUnits() int64 {
+	return (int64(q) + QuantityScale - 1) / QuantityScale
+}
+
+// roundScaled divides v by QuantityScale, rounding halves away from zero
+func roundScaled(v int64) int64 {
+	if v < 0 {
+		return -((-v + QuantityScale/2) / QuantityScale)
+	}
+	return (v + QuantityScale/2) / QuantityScale
+}
@@ -0,0 +1,140 @@
+package db
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Quantity
+		wantErr bool
+	}{
+		{"12", 12000, false},
+		{"1.5", 1500, false},
+		{"0.001", 1, false},
+		{"0,125", 125, false},
+		{".5", 500, false},
+		{" 2.25 ", 2250, false},
+		{"-1.5", -1500, false},
+		{"-0.001", -1, false},
+		{"9223372036854775.807", math.MaxInt64, false},
+		{"", 0, true},
+		{".", 0, true},
+		{"-", 0, true},
+		{"abc", 0, true},
+		{"1.0001", 0, true},
+		{"1.2.3", 0, true},
+		{"--1", 0, true},
+		{"+1", 0, true},
+		{"1.-5", 0, true},
+		{"9223372036854775.808", 0, true},
+		{"9223372036854776", 0, true},
+		{"-9223372036854776", 0, true},
+		{"99999999999999999999", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseQuantity(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseQuantity(%q) = %d, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseQuantity(%q) = %d, %v, want %d", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestQuantityString(t *testing.T) {
+	tests := []struct {
+		q    Quantity
+		want string
+	}{
+		{0, "0"},
+		{2000, "2"},
+		{1500, "1.5"},
+		{125, "0.125"},
+		{1, "0.001"},
+		{-1500, "-1.5"},
+		{-1, "-0.001"},
+	}
+	for _, tt := range tests {
+		if got := tt.q.String(); got != tt.want {
+			t.Errorf("Quantity(%d).String() = %q, want %q", int64(tt.q), got, tt.want)
+		}
+	}
+}
+
+func TestQuantityJSON(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Quantity
+		wantErr bool
+	}{
+		{`1.5`, 1500, false},
+		{`"0.001"`, 1, false},
+		{`2`, 2000, false},
+		{`-0.25`, -250, false},
+		{`1.5e3`, 1500000, false},
+		{`1e-4`, 0, false},
+		{`1.0005`, 0, true},
+		{`"x"`, 0, true},
+		{`1e20`, 0, true},
+		{`9223372036854775.808`, 0, true},
+	}
+	for _, tt := range tests {
+		var got Quantity
+		err := json.Unmarshal([]byte(tt.in), &got)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Unmarshal(%s) = %d, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Unmarshal(%s) = %d, %v, want %d", tt.in, got, err, tt.want)
+		}
+	}
+
+	// null leaves the quantity unchanged
+	q := Quantity(700)
+	if err := json.Unmarshal([]byte("null"), &q); err != nil || q != 700 {
+		t.Errorf("Unmarshal(null) = %d, %v, want 700 unchanged", q, err)
+	}
+
+	data, err := json.Marshal(struct {
+		Qty Quantity `json:"qty"`
+	}{1250})
+	if err != nil || string(data) != `{"qty":1.25}` {
+		t.Errorf("Marshal = %s, %v", data, err)
+	}
+}
+
+func TestQuantityRounding(t *testing.T) {
+	tests := []struct {
+		name string
+		got  int64
+		want int64
+	}{
+		// 1.5 kg at 120.00 DZD
+		{"amount", Quantity(1500).Amount(12000), 18000},
+		// 0.333 at 0.05: 1.665 cents, rounded half up
+		{"amount half", Quantity(333).Amount(5), 2},
+		{"amount negative half", Quantity(-500).Amount(1), -1},
+		{"times", int64(Units(2).Times(Units(12))), 24000},
+		{"times fraction", int64(Quantity(1500).Times(Quantity(333))), 500},
+		{"float", int64(QuantityFromFloat(0.1 + 0.2)), 300},
+		{"float half", int64(QuantityFromFloat(1.0005)), 1001},
+		{"ceil", Quantity(1001).CeilUnits(), 2},
+		{"ceil whole", Quantity(2000).CeilUnits(), 2},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.name, tt.got, tt.want)
+		}
+	}
+}
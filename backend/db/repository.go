@@ -3,10 +3,17 @@ package db
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
-	"log"
-	"os"
+	"strings"
 	"time"
+	apperr "barakaERP/backend/domain/errors"
+	"barakaERP/backend/logging"
+)
+
+var (
+	clientLog = logging.For(logging.Clients)
+	orderLog  = logging.For(logging.Orders)
 )
 
 // Repository handles all database operations
@@ -20,21 +27,20 @@ func NewRepository(db *DB) *Repository { return &Repository{db: db} }
 // Client operations
 
 func (r *Repository) CreateClient(ctx context.Context, client Client) (*Client, error) {
-	debug := false
-	if v, ok := os.LookupEnv("DEBUG_CLIENTS"); ok && v != "" && v != "0" {
-		debug = true
-	}
-	if debug {
-		log.Printf("[clients] repo CreateClient inserting name=%q phone=%v address=%v debt=%d", client.Name, client.Phone, client.Address, client.DebtCents)
+	clientLog.Debug("create client", "name", client.Name, "phone", client.Phone, "address", client.Address, "debt_cents", client.DebtCents)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
+
 	query := `
 		INSERT INTO client (name, phone, address, debt_cents, created_at, updated_at)
 		VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
 	`
-	result, err := r.db.ExecContext(ctx, query, client.Name, client.Phone, client.Address, client.DebtCents)
+	result, err := tx.ExecContext(ctx, query, client.Name, client.Phone, client.Address, client.DebtCents)
 	if err != nil {
-		if debug { log.Printf("[clients] repo CreateClient SQL error: %v", err) }
-		log.Printf("[clients] repo CreateClient FAILED name=%q err=%v", client.Name, err)
+		clientLog.Error("create client failed", "name", client.Name, "err", err)
 		return nil, fmt.Errorf("failed to create client: %w", err)
 	}
 
@@ -43,23 +49,37 @@ func (r *Repository) CreateClient(ctx context.Context, client Client) (*Client,
 		return nil, fmt.Errorf("failed to get client ID: %w", err)
 	}
 
-	client.ID = id
-	client.CreatedAt = time.Now()
-	if debug {
-		log.Printf("[clients] repo CreateClient success id=%d", client.ID)
+	created, err := r.getClientWith(ctx, tx, id)
+	if err != nil {
+		return nil, err
 	}
-	return &client, nil
+	if err := r.writeAudit(ctx, tx, AuditEntityClient, id, AuditActionCreate, nil, created); err != nil {
+		return nil, err
+	}
+	if err := r.indexClient(ctx, tx, created); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	clientLog.Debug("client created", "client_id", created.ID)
+	return created, nil
 }
 
 func (r *Repository) GetClient(ctx context.Context, id int64) (*Client, error) {
-	query := `SELECT id, name, phone, address, debt_cents, created_at, updated_at FROM client WHERE id = ?`
+	return r.getClientWith(ctx, r.db, id)
+}
+
+func (r *Repository) getClientWith(ctx context.Context, q querier, id int64) (*Client, error) {
+	query := `SELECT id, name, phone, address, debt_cents, created_at, updated_at, deleted_at, price_list_id FROM client WHERE id = ?`
 
 	var client Client
-	row := r.db.QueryRowContext(ctx, query, id)
-	err := row.Scan(&client.ID, &client.Name, &client.Phone, &client.Address, &client.DebtCents, &client.CreatedAt, &client.UpdatedAt)
+	row := q.QueryRowContext(ctx, query, id)
+	err := row.Scan(&client.ID, &client.Name, &client.Phone, &client.Address, &client.DebtCents, &client.CreatedAt, &client.UpdatedAt, &client.DeletedAt, &client.PriceListID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("client not found")
+			return nil, apperr.NotFound("client_not_found")
 		}
 		return nil, fmt.Errorf("failed to get client: %w", err)
 	}
@@ -67,12 +87,29 @@ func (r *Repository) GetClient(ctx context.Context, id int64) (*Client, error) {
 	return &client, nil
 }
 
+// ListClients lists clients that are not in the recycle bin
 func (r *Repository) ListClients(ctx context.Context, query string, limit, offset int) ([]Client, int, error) {
+	return r.listClients(ctx, query, false, limit, offset)
+}
+
+// ListDeletedClients lists soft-deleted clients (the recycle bin)
+func (r *Repository) ListDeletedClients(ctx context.Context, query string, limit, offset int) ([]Client, int, error) {
+	return r.listClients(ctx, query, true, limit, offset)
+}
+
+func (r *Repository) listClients(ctx context.Context, query string, deleted bool, limit, offset int) ([]Client, int, error) {
 	var clients []Client
 	var total int
 
+	whereClause := "WHERE deleted_at IS NULL AND name LIKE ?"
+	orderClause := "ORDER BY name"
+	if deleted {
+		whereClause = "WHERE deleted_at IS NOT NULL AND name LIKE ?"
+		orderClause = "ORDER BY deleted_at DESC"
+	}
+
 	// Count total
-	countQuery := `SELECT COUNT(*) FROM client WHERE name LIKE ?`
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM client %s`, whereClause)
 	searchPattern := "%" + query + "%"
 	err := r.db.QueryRowContext(ctx, countQuery, searchPattern).Scan(&total)
 	if err != nil {
@@ -80,13 +117,13 @@ func (r *Repository) ListClients(ctx context.Context, query string, limit, offse
 	}
 
 	// Get clients
-	listQuery := `
-		SELECT id, name, phone, address, debt_cents, created_at, updated_at 
+	listQuery := fmt.Sprintf(`
+		SELECT id, name, phone, address, debt_cents, created_at, updated_at, deleted_at, price_list_id 
 		FROM client 
-		WHERE name LIKE ? 
-		ORDER BY name 
+		%s 
+		%s 
 		LIMIT ? OFFSET ?
-	`
+	`, whereClause, orderClause)
 	rows, err := r.db.QueryContext(ctx, listQuery, searchPattern, limit, offset)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to list clients: %w", err)
@@ -95,7 +132,7 @@ func (r *Repository) ListClients(ctx context.Context, query string, limit, offse
 
 	for rows.Next() {
 		var client Client
-		err := rows.Scan(&client.ID, &client.Name, &client.Phone, &client.Address, &client.DebtCents, &client.CreatedAt, &client.UpdatedAt)
+		err := rows.Scan(&client.ID, &client.Name, &client.Phone, &client.Address, &client.DebtCents, &client.CreatedAt, &client.UpdatedAt, &client.DeletedAt, &client.PriceListID)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan client: %w", err)
 		}
@@ -106,53 +143,133 @@ func (r *Repository) ListClients(ctx context.Context, query string, limit, offse
 }
 
 func (r *Repository) UpdateClient(ctx context.Context, client Client) (*Client, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := r.getClientWith(ctx, tx, client.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
 		UPDATE client 
 		SET name = ?, phone = ?, address = ?, debt_cents = ?, updated_at = CURRENT_TIMESTAMP
 		WHERE id = ?
 	`
-	_, err := r.db.ExecContext(ctx, query, client.Name, client.Phone, client.Address, client.DebtCents, client.ID)
+	_, err = tx.ExecContext(ctx, query, client.Name, client.Phone, client.Address, client.DebtCents, client.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update client: %w", err)
 	}
-	return r.GetClient(ctx, client.ID)
+
+	after, err := r.getClientWith(ctx, tx, client.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.writeAudit(ctx, tx, AuditEntityClient, client.ID, AuditActionUpdate, before, after); err != nil {
+		return nil, err
+	}
+	if err := r.indexClient(ctx, tx, after); err != nil {
+		return nil, err
+	}
+	if after.Name != before.Name || !sameString(after.Phone, before.Phone) {
+		if err := r.reindexClientOrders(ctx, tx, client.ID); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return after, nil
 }
 
-// DeleteClient attempts to delete a client. It will fail if FK constraints (orders/invoices) reference it.
+// DeleteClient moves a client to the recycle bin by setting deleted_at
 func (r *Repository) DeleteClient(ctx context.Context, id int64) error {
-	// Quick existence check
-	if _, err := r.GetClient(ctx, id); err != nil { return err }
+	return r.setClientDeleted(ctx, id, true)
+}
+
+// RestoreClient brings a soft-deleted client back from the recycle bin
+func (r *Repository) RestoreClient(ctx context.Context, id int64) error {
+	return r.setClientDeleted(ctx, id, false)
+}
+
+func (r *Repository) setClientDeleted(ctx context.Context, id int64, deleted bool) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil { return fmt.Errorf("failed to begin transaction: %w", err) }
+	defer tx.Rollback()
+
+	before, err := r.getClientWith(ctx, tx, id)
+	if err != nil { return err }
+
+	query := `UPDATE client SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL`
+	action := AuditActionDelete
+	if !deleted {
+		query = `UPDATE client SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NOT NULL`
+		action = AuditActionUpdate
+	}
+	res, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to update client deleted state: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil // already in the requested state
+	}
+
+	after, err := r.getClientWith(ctx, tx, id)
+	if err != nil { return err }
+	if err := r.writeAudit(ctx, tx, AuditEntityClient, id, action, before, after); err != nil { return err }
+	if err := r.indexClient(ctx, tx, after); err != nil { return err }
+	if err := tx.Commit(); err != nil { return fmt.Errorf("failed to commit transaction: %w", err) }
+	return nil
+}
+
+// PurgeClient permanently removes a soft-deleted client. It will fail if FK constraints (orders/invoices) reference it.
+func (r *Repository) PurgeClient(ctx context.Context, id int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil { return fmt.Errorf("failed to begin transaction: %w", err) }
+	defer tx.Rollback()
+
+	before, err := r.getClientWith(ctx, tx, id)
+	if err != nil { return err }
+	if before.DeletedAt == nil {
+		return apperr.Conflict("client_not_deleted")
+	}
 	// Attempt delete (will error if referenced due to foreign keys)
-	_, err := r.db.ExecContext(ctx, `DELETE FROM client WHERE id = ?`, id)
+	_, err = tx.ExecContext(ctx, `DELETE FROM client WHERE id = ?`, id)
 	if err != nil {
-		return fmt.Errorf("failed to delete client: %w", err)
+		if isForeignKeyViolation(err) {
+			return apperr.Conflict("client_in_use").Wrap(err)
+		}
+		return fmt.Errorf("failed to purge client: %w", err)
 	}
+	if err := r.writeAudit(ctx, tx, AuditEntityClient, id, AuditActionDelete, before, nil); err != nil { return err }
+	if err := r.removeSearchEntry(ctx, tx, SearchTypeClient, id); err != nil { return err }
+	if err := tx.Commit(); err != nil { return fmt.Errorf("failed to commit transaction: %w", err) }
 	return nil
 }
 
 // Debt Payment operations
 
 func (r *Repository) CreateDebtPayment(ctx context.Context, clientID int64, previousDebt int64, newDebt int64, adjustmentType string, notes *string) (*DebtPayment, error) {
-	debug := false
-	if v, ok := os.LookupEnv("DEBUG_CLIENTS"); ok && v != "" && v != "0" {
-		debug = true
-	}
-	
 	adjustmentCents := newDebt - previousDebt
-	if debug {
-		log.Printf("[debt_payment] Creating debt payment record: client_id=%d, prev=%d, new=%d, adjustment=%d, type=%s", 
-			clientID, previousDebt, newDebt, adjustmentCents, adjustmentType)
+	clientLog.Debug("create debt payment", "client_id", clientID, "previous_cents", previousDebt, "new_cents", newDebt,
+		"adjustment_cents", adjustmentCents, "type", adjustmentType)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	
+	defer tx.Rollback()
+
 	query := `
 		INSERT INTO debt_payment (client_id, previous_debt_cents, new_debt_cents, adjustment_cents, type, notes, created_at)
 		VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
 	`
-	result, err := r.db.ExecContext(ctx, query, clientID, previousDebt, newDebt, adjustmentCents, adjustmentType, notes)
+	result, err := tx.ExecContext(ctx, query, clientID, previousDebt, newDebt, adjustmentCents, adjustmentType, notes)
 	if err != nil {
-		if debug {
-			log.Printf("[debt_payment] SQL error: %v", err)
-		}
+		clientLog.Debug("create debt payment failed", "client_id", clientID, "err", err)
 		return nil, fmt.Errorf("failed to create debt payment record: %w", err)
 	}
 
@@ -172,9 +289,14 @@ func (r *Repository) CreateDebtPayment(ctx context.Context, clientID int64, prev
 		CreatedAt:         time.Now(),
 	}
 
-	if debug {
-		log.Printf("[debt_payment] Created debt payment record: id=%d", id)
+	if err := r.writeAudit(ctx, tx, AuditEntityDebtPayment, id, AuditActionCreate, nil, debtPayment); err != nil {
+		return nil, err
 	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	clientLog.Debug("debt payment created", "debt_payment_id", id)
 	return debtPayment, nil
 }
 
@@ -282,15 +404,8 @@ func (r *Repository) GetClientDebtPayments(ctx context.Context, clientID int64,
 
 // AdjustClientDebt adjusts a client's debt and creates a debt payment record
 func (r *Repository) AdjustClientDebt(ctx context.Context, clientID int64, adjustmentCents int64, notes *string) (*Client, *DebtPayment, error) {
-	debug := false
-	if v, ok := os.LookupEnv("DEBUG_CLIENTS"); ok && v != "" && v != "0" {
-		debug = true
-	}
-	
-	if debug {
-		log.Printf("[debt_payment] AdjustClientDebt: client_id=%d, adjustment=%d", clientID, adjustmentCents)
-	}
-	
+	clientLog.Debug("adjust client debt", "client_id", clientID, "adjustment_cents", adjustmentCents)
+
 	// Start transaction
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -299,26 +414,20 @@ func (r *Repository) AdjustClientDebt(ctx context.Context, clientID int64, adjus
 	defer tx.Rollback()
 
 	// Get current client debt
-	var currentDebt int64
-	err = tx.QueryRowContext(ctx, `SELECT debt_cents FROM client WHERE id = ?`, clientID).Scan(&currentDebt)
+	before, err := r.getClientWith(ctx, tx, clientID)
 	if err != nil {
-		if debug {
-			log.Printf("[debt_payment] Failed to get current debt: %v", err)
-		}
+		clientLog.Debug("adjust client debt: get client failed", "client_id", clientID, "err", err)
 		return nil, nil, fmt.Errorf("failed to get client debt: %w", err)
 	}
+	currentDebt := before.DebtCents
 
 	newDebt := currentDebt + adjustmentCents
-	if debug {
-		log.Printf("[debt_payment] Debt change: %d -> %d (adjustment: %d)", currentDebt, newDebt, adjustmentCents)
-	}
+	clientLog.Debug("debt change", "client_id", clientID, "from_cents", currentDebt, "to_cents", newDebt)
 
 	// Update client debt
 	_, err = tx.ExecContext(ctx, `UPDATE client SET debt_cents = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`, newDebt, clientID)
 	if err != nil {
-		if debug {
-			log.Printf("[debt_payment] Failed to update client debt: %v", err)
-		}
+		clientLog.Debug("adjust client debt: update failed", "client_id", clientID, "err", err)
 		return nil, nil, fmt.Errorf("failed to update client debt: %w", err)
 	}
 
@@ -334,9 +443,7 @@ func (r *Repository) AdjustClientDebt(ctx context.Context, clientID int64, adjus
 		VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
 	`, clientID, currentDebt, newDebt, adjustmentCents, adjustmentType, notes)
 	if err != nil {
-		if debug {
-			log.Printf("[debt_payment] Failed to create debt payment record: %v", err)
-		}
+		clientLog.Debug("adjust client debt: insert payment failed", "client_id", clientID, "err", err)
 		return nil, nil, fmt.Errorf("failed to create debt payment record: %w", err)
 	}
 
@@ -347,16 +454,8 @@ func (r *Repository) AdjustClientDebt(ctx context.Context, clientID int64, adjus
 		return nil, nil, fmt.Errorf("failed to get debt payment ID: %w", err)
 	}
 
-	// Commit transaction
-	if err = tx.Commit(); err != nil {
-		if debug {
-			log.Printf("[debt_payment] Failed to commit transaction: %v", err)
-		}
-		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
 	// Get updated client
-	client, err := r.GetClient(ctx, clientID)
+	client, err := r.getClientWith(ctx, tx, clientID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get updated client: %w", err)
 	}
@@ -373,23 +472,77 @@ func (r *Repository) AdjustClientDebt(ctx context.Context, clientID int64, adjus
 		CreatedAt:         time.Now(),
 	}
 
-	if debug {
-		log.Printf("[debt_payment] AdjustClientDebt completed successfully: debt_payment_id=%d", debtPaymentID)
+	// Audit both the debt change on the client and the payment record
+	if err := r.writeAudit(ctx, tx, AuditEntityClient, clientID, AuditActionUpdate, before, client); err != nil {
+		return nil, nil, err
+	}
+	if err := r.writeAudit(ctx, tx, AuditEntityDebtPayment, debtPaymentID, AuditActionCreate, nil, debtPayment); err != nil {
+		return nil, nil, err
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		clientLog.Debug("adjust client debt: commit failed", "client_id", clientID, "err", err)
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
+	clientLog.Debug("client debt adjusted", "client_id", clientID, "debt_payment_id", debtPaymentID)
+
 	return client, debtPayment, nil
 }
 
 // Product operations
 
+const productColumns = `id, sku, name, description, unit_price_cents, currency, active, category_id, unit, parent_id, attributes, stock_milli, created_at, updated_at, deleted_at`
+
+func scanProduct(row interface{ Scan(...interface{}) error }, product *Product) error {
+	var attributes string
+	err := row.Scan(&product.ID, &product.SKU, &product.Name, &product.Description,
+		&product.UnitPriceCents, &product.Currency, &product.Active, &product.CategoryID, &product.Unit,
+		&product.ParentID, &attributes, &product.Stock, &product.CreatedAt, &product.UpdatedAt, &product.DeletedAt)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(attributes), &product.Attributes); err != nil {
+		return fmt.Errorf("failed to decode product attributes: %w", err)
+	}
+	return nil
+}
+
+// encodeAttributes encodes variant attributes for the attributes column
+func encodeAttributes(attributes Attributes) (string, error) {
+	if attributes == nil {
+		attributes = Attributes{}
+	}
+	data, err := json.Marshal(attributes)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode product attributes: %w", err)
+	}
+	return string(data), nil
+}
+
 func (r *Repository) CreateProduct(ctx context.Context, product Product) (*Product, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	attributes, err := encodeAttributes(product.Attributes)
+	if err != nil {
+		return nil, err
+	}
 	query := `
-		INSERT INTO product (sku, name, description, unit_price_cents, currency, active, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		INSERT INTO product (sku, name, description, unit_price_cents, currency, active, category_id, unit, parent_id, attributes, stock_milli, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
 	`
-	result, err := r.db.ExecContext(ctx, query, product.SKU, product.Name, product.Description,
-		product.UnitPriceCents, product.Currency, product.Active)
+	result, err := tx.ExecContext(ctx, query, product.SKU, product.Name, product.Description,
+		product.UnitPriceCents, product.Currency, product.Active, product.CategoryID, product.Unit,
+		product.ParentID, attributes, product.Stock)
 	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, apperr.Conflict("product_sku_taken").Wrap(err)
+		}
 		return nil, fmt.Errorf("failed to create product: %w", err)
 	}
 
@@ -398,21 +551,34 @@ func (r *Repository) CreateProduct(ctx context.Context, product Product) (*Produ
 		return nil, fmt.Errorf("failed to get product ID: %w", err)
 	}
 
-	product.ID = id
-	product.CreatedAt = time.Now()
-	return &product, nil
+	created, err := r.getProductWith(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.writeAudit(ctx, tx, AuditEntityProduct, id, AuditActionCreate, nil, created); err != nil {
+		return nil, err
+	}
+	if err := r.indexProduct(ctx, tx, created); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return created, nil
 }
 
 func (r *Repository) GetProduct(ctx context.Context, id int64) (*Product, error) {
-	query := `SELECT id, sku, name, description, unit_price_cents, currency, active, created_at, updated_at FROM product WHERE id = ?`
+	return r.getProductWith(ctx, r.db, id)
+}
+
+func (r *Repository) getProductWith(ctx context.Context, q querier, id int64) (*Product, error) {
+	query := `SELECT ` + productColumns + ` FROM product WHERE id = ?`
 
 	var product Product
-	row := r.db.QueryRowContext(ctx, query, id)
-	err := row.Scan(&product.ID, &product.SKU, &product.Name, &product.Description,
-		&product.UnitPriceCents, &product.Currency, &product.Active, &product.CreatedAt, &product.UpdatedAt)
+	err := scanProduct(q.QueryRowContext(ctx, query, id), &product)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("product not found")
+			return nil, apperr.NotFound("product_not_found")
 		}
 		return nil, fmt.Errorf("failed to get product: %w", err)
 	}
@@ -420,18 +586,48 @@ func (r *Repository) GetProduct(ctx context.Context, id int64) (*Product, error)
 	return &product, nil
 }
 
-func (r *Repository) ListProducts(ctx context.Context, query string, active *bool, limit, offset int) ([]Product, int, error) {
+// ListProducts lists products that are not in the recycle bin, variants
+// included. A categoryID limits them to that category and its subcategories.
+func (r *Repository) ListProducts(ctx context.Context, query string, active *bool, categoryID *int64, limit, offset int) ([]Product, int, error) {
+	return r.listProducts(ctx, query, active, categoryID, false, limit, offset)
+}
+
+// ListDeletedProducts lists soft-deleted products (the recycle bin)
+func (r *Repository) ListDeletedProducts(ctx context.Context, query string, limit, offset int) ([]Product, int, error) {
+	return r.listProducts(ctx, query, nil, nil, true, limit, offset)
+}
+
+// inCategoryTree matches the products of a category (the single argument)
+// and of all the categories below it
+const inCategoryTree = `category_id IN (
+		WITH RECURSIVE tree(id) AS (
+			SELECT ?
+			UNION SELECT c.id FROM category c JOIN tree ON c.parent_id = tree.id
+		)
+		SELECT id FROM tree
+	)`
+
+func (r *Repository) listProducts(ctx context.Context, query string, active *bool, categoryID *int64, deleted bool, limit, offset int) ([]Product, int, error) {
 	var products []Product
 	var total int
 
 	// Build WHERE clause
-	whereClause := "WHERE (name LIKE ? OR sku LIKE ?)"
+	whereClause := "WHERE deleted_at IS NULL AND (name LIKE ? OR sku LIKE ?)"
+	orderClause := "ORDER BY name"
+	if deleted {
+		whereClause = "WHERE deleted_at IS NOT NULL AND (name LIKE ? OR sku LIKE ?)"
+		orderClause = "ORDER BY deleted_at DESC"
+	}
 	args := []interface{}{"%" + query + "%", "%" + query + "%"}
 
 	if active != nil {
 		whereClause += " AND active = ?"
 		args = append(args, *active)
 	}
+	if categoryID != nil {
+		whereClause += " AND " + inCategoryTree
+		args = append(args, *categoryID)
+	}
 
 	// Count total
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM product %s", whereClause)
@@ -442,12 +638,12 @@ func (r *Repository) ListProducts(ctx context.Context, query string, active *boo
 
 	// Get products
 	listQuery := fmt.Sprintf(`
-		SELECT id, sku, name, description, unit_price_cents, currency, active, created_at, updated_at 
+		SELECT `+productColumns+`
 		FROM product 
 		%s 
-		ORDER BY name 
+		%s 
 		LIMIT ? OFFSET ?
-	`, whereClause)
+	`, whereClause, orderClause)
 	args = append(args, limit, offset)
 
 	rows, err := r.db.QueryContext(ctx, listQuery, args...)
@@ -458,9 +654,7 @@ func (r *Repository) ListProducts(ctx context.Context, query string, active *boo
 
 	for rows.Next() {
 		var product Product
-		err := rows.Scan(&product.ID, &product.SKU, &product.Name, &product.Description,
-			&product.UnitPriceCents, &product.Currency, &product.Active, &product.CreatedAt, &product.UpdatedAt)
-		if err != nil {
+		if err := scanProduct(rows, &product); err != nil {
 			return nil, 0, fmt.Errorf("failed to scan product: %w", err)
 		}
 		products = append(products, product)
@@ -470,36 +664,116 @@ func (r *Repository) ListProducts(ctx context.Context, query string, active *boo
 }
 
 func (r *Repository) UpdateProduct(ctx context.Context, product Product) (*Product, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil { return nil, fmt.Errorf("failed to begin transaction: %w", err) }
+	defer tx.Rollback()
+
+	before, err := r.getProductWith(ctx, tx, product.ID)
+	if err != nil { return nil, err }
+	attributes, err := encodeAttributes(product.Attributes)
+	if err != nil { return nil, err }
+
+	// parent_id is set once, when a variant is created
 	query := `
 		UPDATE product 
-		SET sku = ?, name = ?, description = ?, unit_price_cents = ?, currency = ?, active = ?, updated_at = CURRENT_TIMESTAMP
+		SET sku = ?, name = ?, description = ?, unit_price_cents = ?, currency = ?, active = ?, category_id = ?, unit = ?, attributes = ?, stock_milli = ?, updated_at = CURRENT_TIMESTAMP
 		WHERE id = ?
 	`
-	_, err := r.db.ExecContext(ctx, query, product.SKU, product.Name, product.Description, product.UnitPriceCents, product.Currency, product.Active, product.ID)
-	if err != nil { return nil, fmt.Errorf("failed to update product: %w", err) }
+	_, err = tx.ExecContext(ctx, query, product.SKU, product.Name, product.Description, product.UnitPriceCents, product.Currency, product.Active, product.CategoryID, product.Unit, attributes, product.Stock, product.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, apperr.Conflict("product_sku_taken").Wrap(err)
+		}
+		return nil, fmt.Errorf("failed to update product: %w", err)
+	}
+	if before.ParentID == nil {
+		// Variants always sit in their parent's category
+		_, err = tx.ExecContext(ctx, `UPDATE product SET category_id = ? WHERE parent_id = ?`, product.CategoryID, product.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update variant categories: %w", err)
+		}
+	}
 
-	return r.GetProduct(ctx, product.ID)
+	after, err := r.getProductWith(ctx, tx, product.ID)
+	if err != nil { return nil, err }
+	if err := r.writeAudit(ctx, tx, AuditEntityProduct, product.ID, AuditActionUpdate, before, after); err != nil { return nil, err }
+	if err := r.indexProduct(ctx, tx, after); err != nil { return nil, err }
+	if err := tx.Commit(); err != nil { return nil, fmt.Errorf("failed to commit transaction: %w", err) }
+	return after, nil
 }
 
-// DeleteProduct deletes a product (will not delete existing order/invoice snapshots since they store name/sku snapshots)
+// DeleteProduct moves a product to the recycle bin by setting deleted_at
 func (r *Repository) DeleteProduct(ctx context.Context, id int64) error {
-	if _, err := r.GetProduct(ctx, id); err != nil { return err }
-	_, err := r.db.ExecContext(ctx, `DELETE FROM product WHERE id = ?`, id)
+	return r.setProductDeleted(ctx, id, true)
+}
+
+// RestoreProduct brings a soft-deleted product back from the recycle bin
+func (r *Repository) RestoreProduct(ctx context.Context, id int64) error {
+	return r.setProductDeleted(ctx, id, false)
+}
+
+func (r *Repository) setProductDeleted(ctx context.Context, id int64, deleted bool) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil { return fmt.Errorf("failed to begin transaction: %w", err) }
+	defer tx.Rollback()
+
+	before, err := r.getProductWith(ctx, tx, id)
+	if err != nil { return err }
+
+	query := `UPDATE product SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL`
+	action := AuditActionDelete
+	if !deleted {
+		query = `UPDATE product SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NOT NULL`
+		action = AuditActionUpdate
+	}
+	res, err := tx.ExecContext(ctx, query, id)
 	if err != nil {
-		return fmt.Errorf("failed to delete product: %w", err)
+		return fmt.Errorf("failed to update product deleted state: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil // already in the requested state
 	}
+
+	after, err := r.getProductWith(ctx, tx, id)
+	if err != nil { return err }
+	if err := r.writeAudit(ctx, tx, AuditEntityProduct, id, action, before, after); err != nil { return err }
+	if err := r.indexProduct(ctx, tx, after); err != nil { return err }
+	if err := tx.Commit(); err != nil { return fmt.Errorf("failed to commit transaction: %w", err) }
+	return nil
+}
+
+// PurgeProduct permanently removes a soft-deleted product (will not delete existing order/invoice snapshots since they store name/sku snapshots)
+func (r *Repository) PurgeProduct(ctx context.Context, id int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil { return fmt.Errorf("failed to begin transaction: %w", err) }
+	defer tx.Rollback()
+
+	before, err := r.getProductWith(ctx, tx, id)
+	if err != nil { return err }
+	if before.DeletedAt == nil {
+		return apperr.Conflict("product_not_deleted")
+	}
+	_, err = tx.ExecContext(ctx, `DELETE FROM product WHERE id = ?`, id)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return apperr.Conflict("product_in_use").Wrap(err)
+		}
+		return fmt.Errorf("failed to purge product: %w", err)
+	}
+	if err := r.writeAudit(ctx, tx, AuditEntityProduct, id, AuditActionDelete, before, nil); err != nil { return err }
+	if err := r.removeSearchEntry(ctx, tx, SearchTypeProduct, id); err != nil { return err }
+	if err := tx.Commit(); err != nil { return fmt.Errorf("failed to commit transaction: %w", err) }
 	return nil
 }
 
 // Order operations (simplified - full implementation would include items handling)
 
 func (r *Repository) CreateOrder(ctx context.Context, draft OrderDraft) (*Order, error) {
-	// TEMP DIAGNOSTIC LOGGING
-	fmt.Printf("[CreateOrder] START client_id=%d items=%d discount=%d\n", draft.ClientID, len(draft.Items), draft.DiscountPercent)
+	orderLog.Debug("create order", "client_id", draft.ClientID, "items", len(draft.Items), "discount_percent", draft.DiscountPercent)
 	// Start transaction
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		fmt.Printf("[CreateOrder] begin tx error: %v\n", err)
+		orderLog.Error("create order failed", "step", "begin", "err", err)
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
@@ -517,12 +791,12 @@ func (r *Repository) CreateOrder(ctx context.Context, draft OrderDraft) (*Order,
 	}
 
 	// Get client's current debt BEFORE adding this order (for PDF snapshot)
-	var clientDebtCents int64
-	err = tx.QueryRowContext(ctx, `SELECT debt_cents FROM client WHERE id = ?`, draft.ClientID).Scan(&clientDebtCents)
+	clientBefore, err := r.getClientWith(ctx, tx, draft.ClientID)
 	if err != nil {
-		fmt.Printf("[CreateOrder] select client debt error: %v\n", err)
+		orderLog.Error("create order failed", "step", "client debt", "client_id", draft.ClientID, "err", err)
 		return nil, fmt.Errorf("failed to get client debt: %w", err)
 	}
+	clientDebtCents := clientBefore.DebtCents
 
 	// Create order with snapshot of debt BEFORE this order is added
 	orderQuery := `
@@ -532,7 +806,7 @@ func (r *Repository) CreateOrder(ctx context.Context, draft OrderDraft) (*Order,
 	result, err := tx.ExecContext(ctx, orderQuery, orderNumber, draft.ClientID, OrderStatusPending,
 		draft.Notes, draft.DiscountPercent, issueDate, draft.DueDate, clientDebtCents)
 	if err != nil {
-		fmt.Printf("[CreateOrder] insert order error: %v\n", err)
+		orderLog.Error("create order failed", "step", "insert order", "err", err)
 		return nil, fmt.Errorf("failed to create order: %w", err)
 	}
 
@@ -544,16 +818,16 @@ func (r *Repository) CreateOrder(ctx context.Context, draft OrderDraft) (*Order,
 	var orderTotalCents int64
 	// Create order items
 	for idx, item := range draft.Items {
-		totalCents := int64(item.Qty) * item.UnitPriceCents
+		totalCents := item.Qty.Amount(item.UnitPriceCents)
 		orderTotalCents += totalCents - (totalCents*int64(item.DiscountPercent))/100
 		itemQuery := `
-			INSERT INTO order_item (order_id, product_id, name_snapshot, sku_snapshot, qty, unit_price_cents, discount_percent, currency, total_cents)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			INSERT INTO order_item (order_id, product_id, name_snapshot, sku_snapshot, qty, qty_milli, unit, unit_price_cents, discount_percent, discount_rule_id, currency, total_cents)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		`
 		_, err := tx.ExecContext(ctx, itemQuery, orderID, item.ProductID, item.NameSnapshot,
-			item.SKUSnapshot, item.Qty, item.UnitPriceCents, item.DiscountPercent, item.Currency, totalCents)
+			item.SKUSnapshot, item.Qty.CeilUnits(), item.Qty, item.Unit, item.UnitPriceCents, item.DiscountPercent, item.DiscountRuleID, item.Currency, totalCents)
 		if err != nil {
-			fmt.Printf("[CreateOrder] insert order item error: %v (idx=%d)\n", err, idx)
+			orderLog.Error("create order failed", "step", "insert item", "item", idx, "err", err)
 			return nil, fmt.Errorf("failed to create order item: %w", err)
 		}
 	}
@@ -564,37 +838,39 @@ func (r *Repository) CreateOrder(ctx context.Context, draft OrderDraft) (*Order,
 	// Increment client's debt by order total (business rule retained)
 	if orderTotalCents > 0 {
 		if _, err := tx.ExecContext(ctx, `UPDATE client SET debt_cents = COALESCE(debt_cents,0) + ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`, orderTotalCents, draft.ClientID); err != nil {
-			fmt.Printf("[CreateOrder] update client debt error: %v\n", err)
+			orderLog.Error("create order failed", "step", "update client debt", "client_id", draft.ClientID, "err", err)
 			return nil, fmt.Errorf("failed to update client debt: %w", err)
 		}
 	}
 
 	// Note: client_debt_snapshot_cents was already set during order insertion to the PREVIOUS debt amount
 
+	created, err := r.getOrderSnapshot(ctx, tx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.writeAudit(ctx, tx, AuditEntityOrder, orderID, AuditActionCreate, nil, created); err != nil {
+		return nil, err
+	}
+	if err := r.indexOrder(ctx, tx, orderID); err != nil {
+		return nil, err
+	}
+	if orderTotalCents > 0 {
+		if err := r.auditClientChange(ctx, tx, clientBefore); err != nil {
+			return nil, err
+		}
+	}
+
 	// Commit transaction
 	if err := tx.Commit(); err != nil {
-			fmt.Printf("[CreateOrder] commit error: %v\n", err)
+			orderLog.Error("create order failed", "step", "commit", "err", err)
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	// Return created order
-	// Load snapshot for return (should be the previous debt amount)
-	var snapshot *int64
-	_ = r.db.QueryRowContext(ctx, `SELECT client_debt_snapshot_cents FROM "order" WHERE id = ?`, orderID).Scan(&snapshot)
-	order := &Order{
-		ID:                       orderID,
-		OrderNumber:              orderNumber,
-		ClientID:                 draft.ClientID,
-		Status:                   OrderStatusPending,
-		Notes:                    draft.Notes,
-		DiscountPercent:          draft.DiscountPercent,
-		IssueDate:                issueDate,
-		DueDate:                  draft.DueDate,
-		CreatedAt:                time.Now(),
-		ClientDebtSnapshotCents:  snapshot,
-	}
-
-	fmt.Printf("[CreateOrder] SUCCESS order_id=%d total=%d items=%d\n", order.ID, orderTotalCents, len(draft.Items))
+	// Return created order (snapshot holds the previous debt amount)
+	order := &created.Order
+
+	orderLog.Debug("order created", "order_id", order.ID, "total_cents", orderTotalCents, "items", len(draft.Items))
 	return order, nil
 }
 
@@ -606,26 +882,22 @@ func (r *Repository) CancelOrderAndAdjustDebt(ctx context.Context, orderID int64
 	defer tx.Rollback()
 
 	// Load order + items and client id
-	var status string
-	var clientID int64
-	err = tx.QueryRowContext(ctx, `SELECT status, client_id FROM "order" WHERE id = ?`, orderID).Scan(&status, &clientID)
-	if err != nil {
-		if err == sql.ErrNoRows { return 0, fmt.Errorf("order not found") }
-		return 0, fmt.Errorf("load order: %w", err)
-	}
+	before, err := r.getOrderSnapshot(ctx, tx, orderID)
+	if err != nil { return 0, err }
+	status, clientID := before.Order.Status, before.Order.ClientID
 	if status == OrderStatusCanceled { // already canceled
 		return 0, nil
 	}
 
 	// Compute order total (same logic used in listing)
-	rows, err := tx.QueryContext(ctx, `SELECT qty, unit_price_cents, discount_percent FROM order_item WHERE order_id = ?`, orderID)
+	rows, err := tx.QueryContext(ctx, `SELECT qty_milli, unit_price_cents, discount_percent FROM order_item WHERE order_id = ?`, orderID)
 	if err != nil { return 0, fmt.Errorf("query items: %w", err) }
 	defer rows.Close()
 	var total int64
 	for rows.Next() {
-		var qty int32; var price int64; var disc int32
+		var qty Quantity; var price int64; var disc int32
 		if err := rows.Scan(&qty, &price, &disc); err != nil { return 0, fmt.Errorf("scan item: %w", err) }
-		line := int64(qty) * price
+		line := qty.Amount(price)
 		line = line - (line*int64(disc))/100
 		total += line
 	}
@@ -648,11 +920,18 @@ func (r *Repository) CancelOrderAndAdjustDebt(ctx context.Context, orderID int64
 		return 0, fmt.Errorf("update order: %w", err)
 	}
 
+	after, err := r.getOrderSnapshot(ctx, tx, orderID)
+	if err != nil { return 0, err }
+	if err := r.writeAudit(ctx, tx, AuditEntityOrder, orderID, AuditActionUpdate, before, after); err != nil { return 0, err }
+
 	var adjusted int64
 	if invoiceCount == 0 && paymentCount == 0 && total > 0 { // Safe to roll back debt
+		clientBefore, err := r.getClientWith(ctx, tx, clientID)
+		if err != nil { return 0, err }
 		if _, err := tx.ExecContext(ctx, `UPDATE client SET debt_cents = CASE WHEN debt_cents - ? < 0 THEN 0 ELSE debt_cents - ? END, updated_at = CURRENT_TIMESTAMP WHERE id = ?`, total, total, clientID); err != nil {
 			return 0, fmt.Errorf("adjust debt: %w", err)
 		}
+		if err := r.auditClientChange(ctx, tx, clientBefore); err != nil { return 0, err }
 		adjusted = total
 	}
 
@@ -660,36 +939,6 @@ func (r *Repository) CancelOrderAndAdjustDebt(ctx context.Context, orderID int64
 	return adjusted, nil
 }
 
-// DeleteCanceledOrdersForClient removes all canceled orders (and their items) for a client. Returns count removed.
-func (r *Repository) DeleteCanceledOrdersForClient(ctx context.Context, clientID int64) (int64, error) {
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil { return 0, fmt.Errorf("begin tx: %w", err) }
-	defer tx.Rollback()
-
-	// Collect canceled order ids
-	rows, err := tx.QueryContext(ctx, `SELECT id FROM "order" WHERE client_id = ? AND status = ?`, clientID, OrderStatusCanceled)
-	if err != nil { return 0, fmt.Errorf("query canceled orders: %w", err) }
-	defer rows.Close()
-	var ids []int64
-	for rows.Next() { var oid int64; if err := rows.Scan(&oid); err != nil { return 0, err }; ids = append(ids, oid) }
-	if len(ids) == 0 { return 0, nil }
-
-	// Delete items then orders
-	// Use IN clause. For large sets we could chunk, but expected small.
-	placeholders := ""
-	for i := range ids { if i>0 { placeholders += "," }; placeholders += "?" }
-	args := make([]interface{}, len(ids))
-	for i,v := range ids { args[i] = v }
-	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM order_item WHERE order_id IN (%s)`, placeholders), args...); err != nil {
-		return 0, fmt.Errorf("delete items: %w", err)
-	}
-	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM "order" WHERE id IN (%s)`, placeholders), args...); err != nil {
-		return 0, fmt.Errorf("delete orders: %w", err)
-	}
-	if err := tx.Commit(); err != nil { return 0, fmt.Errorf("commit: %w", err) }
-	return int64(len(ids)), nil
-}
-
 // ProductOrderUsageStats returns counts of total and active (non-canceled) orders referencing a product
 func (r *Repository) ProductOrderUsageStats(ctx context.Context, productID int64) (total int64, active int64, err error) {
 	queryTotal := `SELECT COUNT(*) FROM order_item WHERE product_id = ?`
@@ -699,6 +948,14 @@ func (r *Repository) ProductOrderUsageStats(ctx context.Context, productID int64
 	return
 }
 
+// CountOrdersForClient returns the number of orders (any status) referencing a client
+func (r *Repository) CountOrdersForClient(ctx context.Context, clientID int64) (int64, error) {
+	var count int64
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM "order" WHERE client_id = ?`, clientID).Scan(&count)
+	if err != nil { return 0, fmt.Errorf("count client orders: %w", err) }
+	return count, nil
+}
+
 // HasActiveOrdersForClient returns true if client has any non-canceled orders
 func (r *Repository) HasActiveOrdersForClient(ctx context.Context, clientID int64) (bool, error) {
 	var count int64
@@ -726,9 +983,96 @@ func (r *Repository) DebugSchema(ctx context.Context) (map[string][]string, erro
 	return result, nil
 }
 
+// orderTotalExpr is an order's total after item discounts (same per-line
+// integer discount rounding as CalcOrderTotals). It is a correlated subquery
+// on idx_order_item_order_id, so only the total filters and sort pay for it,
+// and only for the orders they read.
+const orderTotalExpr = `(
+	SELECT COALESCE(SUM(oi.total_cents - oi.total_cents * oi.discount_percent / 100), 0)
+	FROM order_item oi WHERE oi.order_id = o.id
+)`
+
+// orderListFrom joins each order with its client; page totals are added by
+// fillOrderTotals
+const orderListFrom = `
+	FROM "order" o
+	JOIN client c ON o.client_id = c.id
+`
+
+// orderSortFields maps the sort keys accepted in OrderFilters.Sort to SQL
+// expressions; keep the list in the OrderFilters.Sort comment in step.
+// Only these keys can reach ORDER BY.
+var orderSortFields = map[string]string{
+	"id":           "o.id",
+	"order_number": "o.order_number",
+	"status":       "o.status",
+	"issue_date":   "o.issue_date",
+	"due_date":     "o.due_date",
+	"created_at":   "o.created_at",
+	"client_name":  "c.name",
+	"total":        orderTotalExpr,
+}
+
+// orderSortClause turns "field", "field:asc|desc" or "field asc|desc" into an
+// ORDER BY clause built from whitelisted columns only
+func orderSortClause(sort string) (string, error) {
+	sort = strings.TrimSpace(sort)
+	if sort == "" {
+		return "ORDER BY o.id DESC", nil
+	}
+
+	field, dir := sort, "ASC"
+	if i := strings.IndexAny(sort, ": "); i >= 0 {
+		field, dir = sort[:i], strings.ToUpper(strings.TrimSpace(sort[i+1:]))
+	}
+	column, ok := orderSortFields[strings.ToLower(field)]
+	if !ok {
+		return "", fmt.Errorf("invalid sort field: %q", field)
+	}
+	if dir != "ASC" && dir != "DESC" {
+		return "", fmt.Errorf("invalid sort direction: %q", dir)
+	}
+	// o.id breaks ties so pages are stable
+	return fmt.Sprintf("ORDER BY %s %s, o.id DESC", column, dir), nil
+}
+
+// ValidOrderSort reports whether sort is an accepted OrderFilters.Sort value
+func ValidOrderSort(sort string) bool {
+	_, err := orderSortClause(sort)
+	return err == nil
+}
+
 // ListOrders retrieves orders with pagination and filters
 func (r *Repository) ListOrders(ctx context.Context, filters OrderFilters, limit, offset int) ([]OrderDetail, int, error) {
-	// Build WHERE clause
+	sortClause := "ORDER BY o.id DESC"
+	if filters.Sort != nil {
+		clause, err := orderSortClause(*filters.Sort)
+		if err != nil {
+			return nil, 0, err
+		}
+		sortClause = clause
+	}
+
+	whereClause, args := orderWhereClause(filters)
+
+	// Get total count
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) %s %s`, orderListFrom, whereClause)
+
+	var total int
+	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get orders count: %w", err)
+	}
+
+	orders, err := r.queryOrderDetails(ctx, fmt.Sprintf("%s %s LIMIT ? OFFSET ?", whereClause, sortClause), append(args, limit, offset), filters.SkipItems)
+	if err != nil {
+		return nil, 0, err
+	}
+	return orders, total, nil
+}
+
+// orderWhereClause builds the WHERE clause shared by the order list queries
+func orderWhereClause(filters OrderFilters) (string, []interface{}) {
 	whereClause := "WHERE 1=1"
 	args := []interface{}{}
 
@@ -748,46 +1092,72 @@ func (r *Repository) ListOrders(ctx context.Context, filters OrderFilters, limit
 		args = append(args, queryPattern, queryPattern)
 	}
 
-	// Get total count
-	countQuery := fmt.Sprintf(`
-		SELECT COUNT(*) 
-		FROM "order" o 
-		JOIN client c ON o.client_id = c.id 
-		%s
-	`, whereClause)
+	// Dates are stored as text starting with "YYYY-MM-DD", so day bounds compare
+	// as strings; the upper bound is exclusive of the following day.
+	if filters.IssueDateFrom != nil && *filters.IssueDateFrom != "" {
+		whereClause += " AND o.issue_date >= ?"
+		args = append(args, *filters.IssueDateFrom)
+	}
+	if filters.IssueDateTo != nil && *filters.IssueDateTo != "" {
+		whereClause += " AND o.issue_date < date(?, '+1 day')"
+		args = append(args, *filters.IssueDateTo)
+	}
+	if filters.DueDateFrom != nil && *filters.DueDateFrom != "" {
+		whereClause += " AND o.due_date >= ?"
+		args = append(args, *filters.DueDateFrom)
+	}
+	if filters.DueDateTo != nil && *filters.DueDateTo != "" {
+		whereClause += " AND o.due_date < date(?, '+1 day')"
+		args = append(args, *filters.DueDateTo)
+	}
 
-	var total int
-	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get orders count: %w", err)
+	if filters.MinTotalCents != nil {
+		whereClause += " AND " + orderTotalExpr + " >= ?"
+		args = append(args, *filters.MinTotalCents)
+	}
+	if filters.MaxTotalCents != nil {
+		whereClause += " AND " + orderTotalExpr + " <= ?"
+		args = append(args, *filters.MaxTotalCents)
 	}
 
-	// Determine sort order
-	sortClause := "ORDER BY o.id DESC"
-	if filters.Sort != nil && *filters.Sort != "" {
-		sortClause = fmt.Sprintf("ORDER BY %s", *filters.Sort)
+	if filters.ProductID != nil {
+		whereClause += " AND EXISTS (SELECT 1 FROM order_item oi WHERE oi.order_id = o.id AND oi.product_id = ?)"
+		args = append(args, *filters.ProductID)
 	}
 
-	// Get orders with details
+	if filters.HasUnpaidInvoice != nil {
+		unpaid := `EXISTS (
+			SELECT 1 FROM invoice i
+			WHERE i.order_id = o.id AND i.status NOT IN (?, ?)
+			AND i.total_cents > (SELECT COALESCE(SUM(p.amount_cents), 0) FROM payment p WHERE p.invoice_id = i.id)
+		)`
+		if !*filters.HasUnpaidInvoice {
+			unpaid = "NOT " + unpaid
+		}
+		whereClause += " AND " + unpaid
+		args = append(args, InvoiceStatusPaid, InvoiceStatusCanceled)
+	}
+
+	return whereClause, args
+}
+
+// queryOrderDetails runs the order list SELECT with the given WHERE/ORDER/LIMIT
+// tail and loads items for the whole page unless skipItems is set
+func (r *Repository) queryOrderDetails(ctx context.Context, tail string, args []interface{}, skipItems bool) ([]OrderDetail, error) {
+	// Get orders with details and their SQL-computed totals
 	query := fmt.Sprintf(`
 			SELECT 
 				o.id, o.order_number, o.client_id, o.status, o.notes, 
 				o.discount_percent, o.issue_date, o.due_date, o.client_debt_snapshot_cents,
 				o.created_at, o.updated_at,
 				c.id, c.name, c.phone, c.address, c.debt_cents, c.created_at, c.updated_at
-			FROM "order" o
-			JOIN client c ON o.client_id = c.id
 			%s
 			%s
-			LIMIT ? OFFSET ?
-		`, whereClause, sortClause)
-
-	// Add limit and offset to args
-	queryArgs := append(args, limit, offset)
+		`, orderListFrom, tail)
 
-	rows, err := r.db.QueryContext(ctx, query, queryArgs...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to query orders: %w", err)
+		return nil, fmt.Errorf("failed to query orders: %w", err)
 	}
 	defer rows.Close()
 
@@ -802,27 +1172,83 @@ func (r *Repository) ListOrders(ctx context.Context, filters OrderFilters, limit
 			&order.Client.Address, &order.Client.DebtCents, &order.Client.CreatedAt, &order.Client.UpdatedAt,
 		)
 		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan order: %w", err)
+			return nil, fmt.Errorf("failed to scan order: %w", err)
 		}
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate orders: %w", err)
+	}
+	rows.Close()
 
-		// Get order items
-		items, err := r.getOrderItems(ctx, order.Order.ID)
-		if err != nil {
-			return nil, 0, fmt.Errorf("failed to get order items: %w", err)
-		}
-		order.Items = items
+	if len(orders) == 0 {
+		return orders, nil
+	}
+	ids := make([]int64, len(orders))
+	for i := range orders {
+		ids[i] = orders[i].Order.ID
+	}
+	if err := r.fillOrderTotals(ctx, orders, ids); err != nil {
+		return nil, err
+	}
+	if skipItems {
+		return orders, nil
+	}
 
-		// Calculate totals - pass 0 for discount since global discount is just UI helper
-		subtotal, discount, _, total := CalcOrderTotals(items, 0, 0)
-		order.SubtotalCents = subtotal
-		order.DiscountCents = discount
-		order.TaxCents = 0
-		order.TotalCents = total
+	// Load items for the whole page in one query instead of one per order
+	itemsByOrder, err := r.getOrderItemsForOrders(ctx, r.db, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order items: %w", err)
+	}
+	for i := range orders {
+		orders[i].Items = itemsByOrder[orders[i].Order.ID]
+	}
 
-		orders = append(orders, order)
+	return orders, nil
+}
+
+// fillOrderTotals sets the totals of a page of orders (ids in the same
+// order), aggregating the items of these orders only
+func (r *Repository) fillOrderTotals(ctx context.Context, orders []OrderDetail, ids []int64) error {
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	query := fmt.Sprintf(`
+		SELECT order_id, SUM(total_cents), SUM(total_cents * discount_percent / 100)
+		FROM order_item
+		WHERE order_id IN (%s)
+		GROUP BY order_id
+	`, sqlPlaceholders(len(ids)))
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query order totals: %w", err)
+	}
+	defer rows.Close()
+
+	type totals struct{ subtotal, discount int64 }
+	byOrder := make(map[int64]totals, len(ids))
+	for rows.Next() {
+		var id int64
+		var t totals
+		if err := rows.Scan(&id, &t.subtotal, &t.discount); err != nil {
+			return fmt.Errorf("failed to scan order totals: %w", err)
+		}
+		byOrder[id] = t
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate order totals: %w", err)
 	}
 
-	return orders, total, nil
+	for i := range orders {
+		t := byOrder[orders[i].Order.ID]
+		// Global discount is just a UI helper, so totals only include item-level discounts
+		orders[i].SubtotalCents = t.subtotal
+		orders[i].DiscountCents = t.discount
+		orders[i].TaxCents = 0
+		orders[i].TotalCents = t.subtotal - t.discount
+	}
+	return nil
 }
 
 // GetOrderDetail retrieves a single order with full details
@@ -848,7 +1274,7 @@ func (r *Repository) GetOrderDetail(ctx context.Context, id int64) (*OrderDetail
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("order not found")
+			return nil, apperr.NotFound("order_not_found")
 		}
 		return nil, fmt.Errorf("failed to get order: %w", err)
 	}
@@ -870,6 +1296,32 @@ func (r *Repository) GetOrderDetail(ctx context.Context, id int64) (*OrderDetail
 	return &order, nil
 }
 
+// GetOrderIDByNumber finds an order by its order number
+func (r *Repository) GetOrderIDByNumber(ctx context.Context, number string) (int64, error) {
+	var id int64
+	err := r.db.QueryRowContext(ctx, `SELECT id FROM "order" WHERE order_number = ?`, number).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, apperr.NotFound("order_not_found")
+		}
+		return 0, fmt.Errorf("failed to get order: %w", err)
+	}
+	return id, nil
+}
+
+// GetInvoiceIDByNumber finds an invoice by its invoice number
+func (r *Repository) GetInvoiceIDByNumber(ctx context.Context, number string) (int64, error) {
+	var id int64
+	err := r.db.QueryRowContext(ctx, `SELECT id FROM invoice WHERE invoice_number = ?`, number).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, apperr.NotFound("invoice_not_found")
+		}
+		return 0, fmt.Errorf("failed to get invoice: %w", err)
+	}
+	return id, nil
+}
+
 // CreateInvoice creates a new invoice and its items
 func (r *Repository) CreateInvoice(ctx context.Context, draft InvoiceDraft) (*Invoice, error) {
 	tx, err := r.db.BeginTx(ctx, nil)
@@ -905,21 +1357,17 @@ func (r *Repository) CreateInvoice(ctx context.Context, draft InvoiceDraft) (*In
 
 	// Insert items
 	for _, item := range draft.Items {
-		totalCents := int64(item.Qty) * item.UnitPriceCents
+		totalCents := item.Qty.Amount(item.UnitPriceCents)
 		itemQuery := `
-			INSERT INTO invoice_item (invoice_id, product_id, name_snapshot, sku_snapshot, qty, unit_price_cents, currency, total_cents)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			INSERT INTO invoice_item (invoice_id, product_id, name_snapshot, sku_snapshot, qty, qty_milli, unit, unit_price_cents, currency, total_cents)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		`
-		_, err := tx.ExecContext(ctx, itemQuery, invoiceID, item.ProductID, item.NameSnapshot, item.SKUSnapshot, item.Qty, item.UnitPriceCents, item.Currency, totalCents)
+		_, err := tx.ExecContext(ctx, itemQuery, invoiceID, item.ProductID, item.NameSnapshot, item.SKUSnapshot, item.Qty.CeilUnits(), item.Qty, item.Unit, item.UnitPriceCents, item.Currency, totalCents)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create invoice item: %w", err)
 		}
 	}
 
-	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
 	inv := &Invoice{
 		ID:              invoiceID,
 		InvoiceNumber:   invoiceNumber,
@@ -937,6 +1385,17 @@ func (r *Repository) CreateInvoice(ctx context.Context, draft InvoiceDraft) (*In
 		CreatedAt:       time.Now(),
 	}
 
+	if err := r.writeAudit(ctx, tx, AuditEntityInvoice, invoiceID, AuditActionCreate, nil, struct {
+		Invoice *Invoice           `json:"invoice"`
+		Items   []InvoiceItemDraft `json:"items"`
+	}{inv, draft.Items}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return inv, nil
 }
 
@@ -996,7 +1455,7 @@ func (r *Repository) GetInvoiceDetail(ctx context.Context, id int64) (*InvoiceDe
 	err := r.db.QueryRowContext(ctx, query, id).Scan(&inv.ID, &inv.InvoiceNumber, &inv.OrderID, &inv.ClientID, &inv.Status, &inv.IssueDate, &inv.DueDate, &inv.Notes, &inv.SubtotalCents, &inv.DiscountPercent, &inv.TaxPercent, &inv.TotalCents, &inv.Currency, &inv.CreatedAt, &inv.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("invoice not found")
+			return nil, apperr.NotFound("invoice_not_found")
 		}
 		return nil, fmt.Errorf("failed to get invoice: %w", err)
 	}
@@ -1008,7 +1467,7 @@ func (r *Repository) GetInvoiceDetail(ctx context.Context, id int64) (*InvoiceDe
 	}
 
 	// Get items
-	rows, err := r.db.QueryContext(ctx, `SELECT id, invoice_id, product_id, name_snapshot, sku_snapshot, qty, unit_price_cents, currency, total_cents FROM invoice_item WHERE invoice_id = ? ORDER BY id`, id)
+	rows, err := r.db.QueryContext(ctx, `SELECT id, invoice_id, product_id, name_snapshot, sku_snapshot, qty_milli, unit, unit_price_cents, currency, total_cents FROM invoice_item WHERE invoice_id = ? ORDER BY id`, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query invoice items: %w", err)
 	}
@@ -1017,7 +1476,7 @@ func (r *Repository) GetInvoiceDetail(ctx context.Context, id int64) (*InvoiceDe
 	var items []InvoiceItem
 	for rows.Next() {
 		var it InvoiceItem
-		if err := rows.Scan(&it.ID, &it.InvoiceID, &it.ProductID, &it.NameSnapshot, &it.SKUSnapshot, &it.Qty, &it.UnitPriceCents, &it.Currency, &it.TotalCents); err != nil {
+		if err := rows.Scan(&it.ID, &it.InvoiceID, &it.ProductID, &it.NameSnapshot, &it.SKUSnapshot, &it.Qty, &it.Unit, &it.UnitPriceCents, &it.Currency, &it.TotalCents); err != nil {
 			return nil, fmt.Errorf("failed to scan invoice item: %w", err)
 		}
 		items = append(items, it)
@@ -1062,23 +1521,22 @@ func (r *Repository) UpdateOrder(ctx context.Context, update OrderUpdate) (*Orde
 	}
 	defer tx.Rollback()
 
-	// Capture existing order state for debt diff
-	var existingStatus string
-	var clientID int64
-	if err := tx.QueryRowContext(ctx, `SELECT status, client_id FROM "order" WHERE id = ?`, update.ID).Scan(&existingStatus, &clientID); err != nil {
-		if err == sql.ErrNoRows { return nil, fmt.Errorf("order not found") }
-		return nil, fmt.Errorf("failed to load existing order: %w", err)
+	// Capture existing order state for debt diff and audit trail
+	before, err := r.getOrderSnapshot(ctx, tx, update.ID)
+	if err != nil {
+		return nil, err
 	}
+	clientID := before.Order.ClientID
 
 	// Compute current total before changes (only if we might need debt adjustment)
 	var oldTotal int64
 	{
-		rows, err := tx.QueryContext(ctx, `SELECT qty, unit_price_cents, discount_percent FROM order_item WHERE order_id = ?`, update.ID)
+		rows, err := tx.QueryContext(ctx, `SELECT qty_milli, unit_price_cents, discount_percent FROM order_item WHERE order_id = ?`, update.ID)
 		if err != nil { return nil, fmt.Errorf("failed to load existing items: %w", err) }
 		for rows.Next() {
-			var qty int32; var price int64; var disc int32
+			var qty Quantity; var price int64; var disc int32
 			if err := rows.Scan(&qty, &price, &disc); err != nil { rows.Close(); return nil, fmt.Errorf("scan existing item: %w", err) }
-			line := int64(qty) * price
+			line := qty.Amount(price)
 			line = line - (line*int64(disc))/100
 			oldTotal += line
 		}
@@ -1139,14 +1597,14 @@ func (r *Repository) UpdateOrder(ctx context.Context, update OrderUpdate) (*Orde
 
 		// Insert new items
 		for _, item := range update.Items {
-			totalCents := int64(item.Qty) * item.UnitPriceCents
+			totalCents := item.Qty.Amount(item.UnitPriceCents)
 			orderTotalCents += totalCents - (totalCents*int64(item.DiscountPercent))/100
 			itemQuery := `
-				INSERT INTO order_item (order_id, product_id, name_snapshot, sku_snapshot, qty, unit_price_cents, discount_percent, currency, total_cents)
-				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+				INSERT INTO order_item (order_id, product_id, name_snapshot, sku_snapshot, qty, qty_milli, unit, unit_price_cents, discount_percent, discount_rule_id, currency, total_cents)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 			`
 			_, err := tx.ExecContext(ctx, itemQuery, update.ID, item.ProductID, item.NameSnapshot,
-				item.SKUSnapshot, item.Qty, item.UnitPriceCents, item.DiscountPercent, item.Currency, totalCents)
+				item.SKUSnapshot, item.Qty.CeilUnits(), item.Qty, item.Unit, item.UnitPriceCents, item.DiscountPercent, item.DiscountRuleID, item.Currency, totalCents)
 			if err != nil {
 				return nil, fmt.Errorf("failed to create order item: %w", err)
 			}
@@ -1172,12 +1630,12 @@ func (r *Repository) UpdateOrder(ctx context.Context, update OrderUpdate) (*Orde
 				newTotal = orderTotalCents
 			} else if update.DiscountPercent != nil {
 				var base int64
-				rows, err := tx.QueryContext(ctx, `SELECT qty, unit_price_cents, discount_percent FROM order_item WHERE order_id = ?`, update.ID)
+				rows, err := tx.QueryContext(ctx, `SELECT qty_milli, unit_price_cents, discount_percent FROM order_item WHERE order_id = ?`, update.ID)
 				if err != nil { return nil, fmt.Errorf("recalc items for discount: %w", err) }
 				for rows.Next() {
-					var qty int32; var price int64; var disc int32
+					var qty Quantity; var price int64; var disc int32
 					if err := rows.Scan(&qty, &price, &disc); err != nil { rows.Close(); return nil, fmt.Errorf("scan recalc item: %w", err) }
-					line := int64(qty) * price
+					line := qty.Amount(price)
 					line = line - (line*int64(disc))/100
 					base += line
 				}
@@ -1213,36 +1671,67 @@ func (r *Repository) UpdateOrder(ctx context.Context, update OrderUpdate) (*Orde
 
 	// (Removed balance column) - if future outstanding tracking is needed, compute via invoices/payments
 
+	after, err := r.getOrderSnapshot(ctx, tx, update.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get updated order: %w", err)
+	}
+	if err := r.writeAudit(ctx, tx, AuditEntityOrder, update.ID, AuditActionUpdate, before, after); err != nil {
+		return nil, err
+	}
+	if err := r.indexOrder(ctx, tx, update.ID); err != nil {
+		return nil, err
+	}
+
 	// Commit transaction
 	if err := tx.Commit(); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	// Get updated order
+	return &after.Order, nil
+}
+
+// getOrderWith loads a single order header row
+func (r *Repository) getOrderWith(ctx context.Context, q querier, id int64) (*Order, error) {
 	var order Order
 	query := `SELECT id, order_number, client_id, status, notes, discount_percent, issue_date, due_date, client_debt_snapshot_cents, created_at, updated_at FROM "order" WHERE id = ?`
-	err = r.db.QueryRowContext(ctx, query, update.ID).Scan(
+	err := q.QueryRowContext(ctx, query, id).Scan(
 		&order.ID, &order.OrderNumber, &order.ClientID, &order.Status, &order.Notes,
 		&order.DiscountPercent, &order.IssueDate, &order.DueDate, &order.ClientDebtSnapshotCents,
 		&order.CreatedAt, &order.UpdatedAt,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get updated order: %w", err)
+		if err == sql.ErrNoRows {
+			return nil, apperr.NotFound("order_not_found")
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
 	}
-
 	return &order, nil
 }
 
+// auditClientChange records a client update whose previous state is before,
+// re-reading the current row inside tx as the after state
+func (r *Repository) auditClientChange(ctx context.Context, tx *sql.Tx, before *Client) error {
+	after, err := r.getClientWith(ctx, tx, before.ID)
+	if err != nil {
+		return err
+	}
+	return r.writeAudit(ctx, tx, AuditEntityClient, before.ID, AuditActionUpdate, before, after)
+}
+
 // getOrderItems is a helper function to get items for an order
 func (r *Repository) getOrderItems(ctx context.Context, orderID int64) ([]OrderItem, error) {
+	return r.getOrderItemsWith(ctx, r.db, orderID)
+}
+
+func (r *Repository) getOrderItemsWith(ctx context.Context, q querier, orderID int64) ([]OrderItem, error) {
 	query := `
-		SELECT id, order_id, product_id, name_snapshot, sku_snapshot, qty, unit_price_cents, discount_percent, currency, total_cents
+		SELECT id, order_id, product_id, name_snapshot, sku_snapshot, qty_milli, unit, unit_price_cents, discount_percent, discount_rule_id, currency, total_cents
 		FROM order_item 
 		WHERE order_id = ?
 		ORDER BY id
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, orderID)
+	rows, err := q.QueryContext(ctx, query, orderID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query order items: %w", err)
 	}
@@ -1253,7 +1742,7 @@ func (r *Repository) getOrderItems(ctx context.Context, orderID int64) ([]OrderI
 		var item OrderItem
 		err := rows.Scan(
 			&item.ID, &item.OrderID, &item.ProductID, &item.NameSnapshot,
-			&item.SKUSnapshot, &item.Qty, &item.UnitPriceCents, &item.DiscountPercent, &item.Currency, &item.TotalCents,
+			&item.SKUSnapshot, &item.Qty, &item.Unit, &item.UnitPriceCents, &item.DiscountPercent, &item.DiscountRuleID, &item.Currency, &item.TotalCents,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan order item: %w", err)
@@ -1264,6 +1753,48 @@ func (r *Repository) getOrderItems(ctx context.Context, orderID int64) ([]OrderI
 	return items, nil
 }
 
+// getOrderItemsForOrders loads the items of several orders with a single IN (...) query
+func (r *Repository) getOrderItemsForOrders(ctx context.Context, q querier, orderIDs []int64) (map[int64][]OrderItem, error) {
+	result := make(map[int64][]OrderItem, len(orderIDs))
+	if len(orderIDs) == 0 {
+		return result, nil
+	}
+
+	args := make([]interface{}, len(orderIDs))
+	for i, id := range orderIDs {
+		args[i] = id
+	}
+	query := fmt.Sprintf(`
+		SELECT id, order_id, product_id, name_snapshot, sku_snapshot, qty_milli, unit, unit_price_cents, discount_percent, discount_rule_id, currency, total_cents
+		FROM order_item 
+		WHERE order_id IN (%s)
+		ORDER BY order_id, id
+	`, sqlPlaceholders(len(orderIDs)))
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item OrderItem
+		err := rows.Scan(
+			&item.ID, &item.OrderID, &item.ProductID, &item.NameSnapshot,
+			&item.SKUSnapshot, &item.Qty, &item.Unit, &item.UnitPriceCents, &item.DiscountPercent, &item.DiscountRuleID, &item.Currency, &item.TotalCents,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan order item: %w", err)
+		}
+		result[item.OrderID] = append(result[item.OrderID], item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate order items: %w", err)
+	}
+
+	return result, nil
+}
+
 func (r *Repository) generateOrderNumber(ctx context.Context, tx *sql.Tx) (string, error) {
 	year := time.Now().Year()
 
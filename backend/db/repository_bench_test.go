@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+// Size of the seeded order history for the list benchmarks
+const (
+	benchClients       = 500
+	benchOrders        = 20000
+	benchItemsPerOrder = 3
+)
+
+// seedOrders creates a temporary database with benchOrders orders of
+// benchItemsPerOrder lines each, spread over benchClients clients
+func seedOrders(b *testing.B) *Repository {
+	b.Helper()
+	conn, err := Connect(filepath.Join(b.TempDir(), "bench.db"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { conn.Close() })
+	if err := conn.ApplySchemaFile("schema.sql"); err != nil {
+		b.Fatal(err)
+	}
+
+	ctx := context.Background()
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer tx.Rollback()
+	for c := 1; c <= benchClients; c++ {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO client (id, name) VALUES (?, ?)`, c, fmt.Sprintf("Client %d", c)); err != nil {
+			b.Fatal(err)
+		}
+	}
+	for o := 1; o <= benchOrders; o++ {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO "order" (id, order_number, client_id, status, issue_date)
+			VALUES (?, ?, ?, ?, date('2024-01-01', ?))
+		`, o, fmt.Sprintf("ORD-%06d", o), o%benchClients+1, OrderStatusPending, fmt.Sprintf("+%d days", o%365))
+		if err != nil {
+			b.Fatal(err)
+		}
+		for i := 1; i <= benchItemsPerOrder; i++ {
+			price := int64(100 * i)
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO order_item (order_id, name_snapshot, qty, qty_milli, unit_price_cents, discount_percent, total_cents)
+				VALUES (?, ?, ?, ?, ?, ?, ?)
+			`, o, fmt.Sprintf("Product %d", i), i, Units(int64(i)), price, (o+i)%3*5, price*int64(i))
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		b.Fatal(err)
+	}
+	return NewRepository(conn)
+}
+
+// BenchmarkListOrders lists a page of the seeded history, with and without
+// line items; a page should cost the same however many orders there are
+func BenchmarkListOrders(b *testing.B) {
+	repo := seedOrders(b)
+	ctx := context.Background()
+
+	for _, skipItems := range []bool{true, false} {
+		b.Run(fmt.Sprintf("SkipItems=%t", skipItems), func(b *testing.B) {
+			filters := OrderFilters{SkipItems: skipItems}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				orders, total, err := repo.ListOrders(ctx, filters, 50, (i%100)*50)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if total != benchOrders || len(orders) != 50 {
+					b.Fatalf("got %d of %d orders", len(orders), total)
+				}
+			}
+		})
+	}
+}
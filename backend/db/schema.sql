@@ -40,6 +40,10 @@ CREATE TABLE IF NOT EXISTS "order" (
     updated_at DATETIME
 );
 
+-- Soft delete (recycle bin) columns for clients and products
+ALTER TABLE client ADD COLUMN IF NOT EXISTS deleted_at DATETIME;
+ALTER TABLE product ADD COLUMN IF NOT EXISTS deleted_at DATETIME;
+
 -- Ensure snapshot column exists on order (added in later versions)
 ALTER TABLE "order" ADD COLUMN IF NOT EXISTS client_debt_snapshot_cents INTEGER;
 
@@ -113,11 +117,165 @@ CREATE TABLE IF NOT EXISTS debt_payment (
     created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
 
+CREATE TABLE IF NOT EXISTS audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    entity TEXT NOT NULL,
+    entity_id INTEGER NOT NULL,
+    action TEXT NOT NULL CHECK(action IN ('CREATE', 'UPDATE', 'DELETE')),
+    before_json TEXT,
+    after_json TEXT,
+    user TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Named price lists (e.g. retail, wholesale, distributor) with per-product
+-- prices valid between two dates (YYYY-MM-DD, inclusive; NULL for no bound)
+CREATE TABLE IF NOT EXISTS price_list (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT UNIQUE NOT NULL,
+    currency TEXT NOT NULL DEFAULT 'DZD',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME
+);
+
+CREATE TABLE IF NOT EXISTS product_price (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    price_list_id INTEGER NOT NULL REFERENCES price_list(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES product(id) ON DELETE CASCADE,
+    unit_price_cents INTEGER NOT NULL CHECK(unit_price_cents >= 0),
+    valid_from TEXT,
+    valid_to TEXT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME
+);
+
+-- Default price list of a client; products without a price in it use their own price
+ALTER TABLE client ADD COLUMN IF NOT EXISTS price_list_id INTEGER REFERENCES price_list(id) ON DELETE SET NULL;
+
+-- Product categories; discount rules may target a whole category
+CREATE TABLE IF NOT EXISTS category (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT UNIQUE NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME
+);
+
+ALTER TABLE product ADD COLUMN IF NOT EXISTS category_id INTEGER REFERENCES category(id) ON DELETE SET NULL;
+
+-- Automatic discounts: a percentage for lines of at least min_qty units of a
+-- product, of a category's products, or of any product when both are NULL,
+-- between two dates (YYYY-MM-DD, inclusive; NULL for no bound)
+CREATE TABLE IF NOT EXISTS discount_rule (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    product_id INTEGER REFERENCES product(id) ON DELETE CASCADE,
+    category_id INTEGER REFERENCES category(id) ON DELETE CASCADE,
+    min_qty INTEGER NOT NULL DEFAULT 1 CHECK(min_qty > 0),
+    percent INTEGER NOT NULL CHECK(percent > 0 AND percent <= 100),
+    valid_from TEXT,
+    valid_to TEXT,
+    active INTEGER NOT NULL DEFAULT 1,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME
+);
+
+-- Rule that set the discount of an order line (NULL for manual discounts)
+ALTER TABLE order_item ADD COLUMN IF NOT EXISTS discount_rule_id INTEGER REFERENCES discount_rule(id) ON DELETE SET NULL;
+
+-- Units of measure: products are sold and priced in a base unit (piece, kg,
+-- m, ...) and may also be sold in packaging units holding a fixed quantity of
+-- it, e.g. a carton of 12 pieces (factor_milli 12000)
+ALTER TABLE product ADD COLUMN IF NOT EXISTS unit TEXT NOT NULL DEFAULT 'piece';
+
+CREATE TABLE IF NOT EXISTS product_unit (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    product_id INTEGER NOT NULL REFERENCES product(id) ON DELETE CASCADE,
+    unit TEXT NOT NULL,
+    factor_milli INTEGER NOT NULL CHECK(factor_milli > 0),
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME,
+    UNIQUE(product_id, unit)
+);
+
+-- Exact decimal quantities in thousandths of the line's unit. The integer qty
+-- columns cannot be dropped in place; they keep the quantity rounded up.
+ALTER TABLE order_item ADD COLUMN IF NOT EXISTS qty_milli INTEGER;
+ALTER TABLE order_item ADD COLUMN IF NOT EXISTS unit TEXT NOT NULL DEFAULT 'piece';
+UPDATE order_item SET qty_milli = qty * 1000 WHERE qty_milli IS NULL;
+ALTER TABLE invoice_item ADD COLUMN IF NOT EXISTS qty_milli INTEGER;
+ALTER TABLE invoice_item ADD COLUMN IF NOT EXISTS unit TEXT NOT NULL DEFAULT 'piece';
+UPDATE invoice_item SET qty_milli = qty * 1000 WHERE qty_milli IS NULL;
+
+-- Category tree: a category may sit under a parent category
+ALTER TABLE category ADD COLUMN IF NOT EXISTS parent_id INTEGER REFERENCES category(id) ON DELETE SET NULL;
+
+-- Variants: a product with a parent_id is a variant of that product (e.g. a
+-- size or color of it), with its own SKU, price and stock. Attributes is a
+-- JSON object of the values that set it apart, e.g. {"size":"30 cm"}. Stock
+-- is the quantity on hand in thousandths of the product's unit.
+ALTER TABLE product ADD COLUMN IF NOT EXISTS parent_id INTEGER REFERENCES product(id);
+ALTER TABLE product ADD COLUMN IF NOT EXISTS attributes TEXT NOT NULL DEFAULT '{}';
+ALTER TABLE product ADD COLUMN IF NOT EXISTS stock_milli INTEGER NOT NULL DEFAULT 0;
+
+-- Company profile printed on every document (single row, id = 1)
+CREATE TABLE IF NOT EXISTS company_settings (
+    id INTEGER PRIMARY KEY CHECK(id = 1),
+    name_ar TEXT NOT NULL,
+    name_fr TEXT,
+    address TEXT,
+    phones TEXT NOT NULL DEFAULT '[]', -- JSON array of phone numbers
+    rc TEXT,
+    nif TEXT,
+    nis TEXT,
+    ai TEXT,
+    logo BLOB,
+    logo_type TEXT, -- PNG or JPG
+    footer_text TEXT,
+    updated_at DATETIME
+);
+
+INSERT OR IGNORE INTO company_settings (id, name_ar, address, phones)
+VALUES (1, 'البركة للإنتاج الصناعي للأدوات المنزلية', 'قمار ولاية الوادي ص.ب 39400-331', '["032 23 19 99"]');
+
+-- Application settings (see AppSettings), one JSON-encoded value per field
+CREATE TABLE IF NOT EXISTS settings (
+    key TEXT PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Installation secrets generated on first use (e.g. the key signing document QR codes)
+CREATE TABLE IF NOT EXISTS app_secret (
+    name TEXT PRIMARY KEY,
+    value BLOB NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Named users holding one of the licensed seats, registered the first time
+-- they sign in; an admin releases the seat of someone who has left
+CREATE TABLE IF NOT EXISTS licensed_user (
+    name TEXT PRIMARY KEY,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Full-text search over clients, products and orders. Content is normalized in Go
+-- (Arabic alef forms, taa marbuta, tashkeel) before it is written here.
+CREATE VIRTUAL TABLE IF NOT EXISTS search_index USING fts5(
+    entity UNINDEXED,
+    entity_id UNINDEXED,
+    title UNINDEXED,
+    subtitle UNINDEXED,
+    content,
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+
 -- Indexes (idempotent)
 CREATE INDEX IF NOT EXISTS idx_client_name ON client(name);
+CREATE INDEX IF NOT EXISTS idx_client_deleted_at ON client(deleted_at);
 CREATE INDEX IF NOT EXISTS idx_product_name ON product(name);
 CREATE INDEX IF NOT EXISTS idx_product_sku ON product(sku);
 CREATE INDEX IF NOT EXISTS idx_product_active ON product(active);
+CREATE INDEX IF NOT EXISTS idx_product_deleted_at ON product(deleted_at);
 CREATE INDEX IF NOT EXISTS idx_order_client_id ON "order"(client_id);
 CREATE INDEX IF NOT EXISTS idx_order_status ON "order"(status);
 CREATE INDEX IF NOT EXISTS idx_order_issue_date ON "order"(issue_date);
@@ -133,6 +291,16 @@ CREATE INDEX IF NOT EXISTS idx_payment_invoice_id ON payment(invoice_id);
 CREATE INDEX IF NOT EXISTS idx_payment_paid_at ON payment(paid_at);
 CREATE INDEX IF NOT EXISTS idx_debt_payment_client_id ON debt_payment(client_id);
 CREATE INDEX IF NOT EXISTS idx_debt_payment_created_at ON debt_payment(created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log(entity, entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
+CREATE INDEX IF NOT EXISTS idx_product_price_lookup ON product_price(price_list_id, product_id);
+CREATE INDEX IF NOT EXISTS idx_client_price_list_id ON client(price_list_id);
+CREATE INDEX IF NOT EXISTS idx_product_category_id ON product(category_id);
+CREATE INDEX IF NOT EXISTS idx_discount_rule_product_id ON discount_rule(product_id);
+CREATE INDEX IF NOT EXISTS idx_discount_rule_category_id ON discount_rule(category_id);
+CREATE INDEX IF NOT EXISTS idx_product_unit_product_id ON product_unit(product_id);
+CREATE INDEX IF NOT EXISTS idx_category_parent_id ON category(parent_id);
+CREATE INDEX IF NOT EXISTS idx_product_parent_id ON product(parent_id);
 
 -- Recreate views safely
 DROP VIEW IF EXISTS vw_revenue_by_month;
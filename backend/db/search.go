@@ -0,0 +1,301 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Search result types
+const (
+	SearchTypeClient  = "client"
+	SearchTypeProduct = "product"
+	SearchTypeOrder   = "order"
+)
+
+// searchRowIDBase separates entity types inside search_index so a row can be
+// replaced by rowid without scanning the UNINDEXED columns.
+var searchRowIDBase = map[string]int64{
+	SearchTypeClient:  1 << 40,
+	SearchTypeProduct: 2 << 40,
+	SearchTypeOrder:   3 << 40,
+}
+
+func searchRowID(entity string, id int64) int64 { return searchRowIDBase[entity] + id }
+
+// NormalizeSearchText folds text so that spelling variants match:
+// lower-cases Latin, strips Arabic tashkeel and tatweel, unifies alef forms,
+// maps taa marbuta to haa and alef maqsura to yaa, and converts Arabic-Indic digits.
+func NormalizeSearchText(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		switch {
+		case r >= 0x064B && r <= 0x065F, r == 0x0670, r == 0x0640: // tashkeel, superscript alef, tatweel
+			continue
+		case r == 'أ' || r == 'إ' || r == 'آ' || r == 'ٱ':
+			b.WriteRune('ا')
+		case r == 'ة':
+			b.WriteRune('ه')
+		case r == 'ى':
+			b.WriteRune('ي')
+		case r >= '٠' && r <= '٩':
+			b.WriteRune('0' + (r - '٠'))
+		case r >= '۰' && r <= '۹':
+			b.WriteRune('0' + (r - '۰'))
+		default:
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
+
+// buildMatchQuery turns free user input into an FTS5 query where every term
+// must match as a prefix. Only letters and digits survive tokenization, so
+// user input can't inject FTS5 operators.
+func buildMatchQuery(q string) string {
+	var terms []string
+	for _, tok := range strings.FieldsFunc(NormalizeSearchText(q), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		terms = append(terms, `"`+tok+`"*`)
+	}
+	return strings.Join(terms, " ")
+}
+
+// digitsOnly strips separators from phone numbers so "0555 12 34" also matches "05551234"
+func digitsOnly(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	var b strings.Builder
+	for _, r := range NormalizeSearchText(*s) {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	out := b.String()
+	return &out
+}
+
+func joinSearchParts(parts ...*string) string {
+	var out []string
+	for _, p := range parts {
+		if p != nil && *p != "" {
+			out = append(out, *p)
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+func (r *Repository) putSearchEntry(ctx context.Context, q querier, entity string, id int64, title, subtitle, content string) error {
+	rowID := searchRowID(entity, id)
+	if _, err := q.ExecContext(ctx, `DELETE FROM search_index WHERE rowid = ?`, rowID); err != nil {
+		return fmt.Errorf("failed to clear search entry: %w", err)
+	}
+	_, err := q.ExecContext(ctx, `INSERT INTO search_index (rowid, entity, entity_id, title, subtitle, content) VALUES (?, ?, ?, ?, ?, ?)`,
+		rowID, entity, id, title, subtitle, NormalizeSearchText(content))
+	if err != nil {
+		return fmt.Errorf("failed to write search entry: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) removeSearchEntry(ctx context.Context, q querier, entity string, id int64) error {
+	if _, err := q.ExecContext(ctx, `DELETE FROM search_index WHERE rowid = ?`, searchRowID(entity, id)); err != nil {
+		return fmt.Errorf("failed to remove search entry: %w", err)
+	}
+	return nil
+}
+
+// indexClient indexes a client by name, phone and address
+func (r *Repository) indexClient(ctx context.Context, q querier, client *Client) error {
+	if client.DeletedAt != nil {
+		return r.removeSearchEntry(ctx, q, SearchTypeClient, client.ID)
+	}
+	subtitle := joinSearchParts(client.Phone, client.Address)
+	content := joinSearchParts(&client.Name, client.Phone, digitsOnly(client.Phone), client.Address)
+	return r.putSearchEntry(ctx, q, SearchTypeClient, client.ID, client.Name, subtitle, content)
+}
+
+// indexProduct indexes a product by name, SKU and description
+func (r *Repository) indexProduct(ctx context.Context, q querier, product *Product) error {
+	if product.DeletedAt != nil {
+		return r.removeSearchEntry(ctx, q, SearchTypeProduct, product.ID)
+	}
+	subtitle := joinSearchParts(product.SKU)
+	content := joinSearchParts(&product.Name, product.SKU, product.Description)
+	return r.putSearchEntry(ctx, q, SearchTypeProduct, product.ID, product.Name, subtitle, content)
+}
+
+// indexOrder indexes an order by number, client name/phone, notes and the products it contains
+func (r *Repository) indexOrder(ctx context.Context, q querier, orderID int64) error {
+	var orderNumber, clientName string
+	var clientPhone, notes *string
+	err := q.QueryRowContext(ctx, `
+		SELECT o.order_number, o.notes, c.name, c.phone
+		FROM "order" o JOIN client c ON o.client_id = c.id
+		WHERE o.id = ?
+	`, orderID).Scan(&orderNumber, &notes, &clientName, &clientPhone)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return r.removeSearchEntry(ctx, q, SearchTypeOrder, orderID)
+		}
+		return fmt.Errorf("failed to load order for search index: %w", err)
+	}
+
+	items, err := r.getOrderItemsWith(ctx, q, orderID)
+	if err != nil {
+		return err
+	}
+	parts := []*string{&orderNumber, &clientName, clientPhone, digitsOnly(clientPhone), notes}
+	for i := range items {
+		parts = append(parts, &items[i].NameSnapshot, items[i].SKUSnapshot)
+	}
+	return r.putSearchEntry(ctx, q, SearchTypeOrder, orderID, orderNumber, clientName, joinSearchParts(parts...))
+}
+
+// reindexClientOrders refreshes the order entries of a client (their content embeds the client name)
+func (r *Repository) reindexClientOrders(ctx context.Context, q querier, clientID int64) error {
+	rows, err := q.QueryContext(ctx, `SELECT id FROM "order" WHERE client_id = ?`, clientID)
+	if err != nil {
+		return fmt.Errorf("failed to query client orders: %w", err)
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan order id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	for _, id := range ids {
+		if err := r.indexOrder(ctx, q, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EnsureSearchIndex rebuilds the search index when it is empty, e.g. on the
+// first start after upgrading an existing database.
+func (r *Repository) EnsureSearchIndex(ctx context.Context) error {
+	var indexed int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM search_index`).Scan(&indexed); err != nil {
+		return fmt.Errorf("failed to count search index: %w", err)
+	}
+	if indexed > 0 {
+		return nil
+	}
+	return r.RebuildSearchIndex(ctx)
+}
+
+// RebuildSearchIndex clears and repopulates the search index from all live rows
+func (r *Repository) RebuildSearchIndex(ctx context.Context) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM search_index`); err != nil {
+		return fmt.Errorf("failed to clear search index: %w", err)
+	}
+
+	collectIDs := func(query string) ([]int64, error) {
+		rows, err := tx.QueryContext(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		var ids []int64
+		for rows.Next() {
+			var id int64
+			if err := rows.Scan(&id); err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+		return ids, rows.Err()
+	}
+
+	clientIDs, err := collectIDs(`SELECT id FROM client WHERE deleted_at IS NULL`)
+	if err != nil {
+		return fmt.Errorf("failed to list clients for search index: %w", err)
+	}
+	for _, id := range clientIDs {
+		client, err := r.getClientWith(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if err := r.indexClient(ctx, tx, client); err != nil {
+			return err
+		}
+	}
+
+	productIDs, err := collectIDs(`SELECT id FROM product WHERE deleted_at IS NULL`)
+	if err != nil {
+		return fmt.Errorf("failed to list products for search index: %w", err)
+	}
+	for _, id := range productIDs {
+		product, err := r.getProductWith(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if err := r.indexProduct(ctx, tx, product); err != nil {
+			return err
+		}
+	}
+
+	orderIDs, err := collectIDs(`SELECT id FROM "order"`)
+	if err != nil {
+		return fmt.Errorf("failed to list orders for search index: %w", err)
+	}
+	for _, id := range orderIDs {
+		if err := r.indexOrder(ctx, tx, id); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GlobalSearch searches clients, products and orders, best matches first
+func (r *Repository) GlobalSearch(ctx context.Context, q string, limit int) ([]SearchResult, error) {
+	match := buildMatchQuery(q)
+	if match == "" {
+		return []SearchResult{}, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT entity, entity_id, title, subtitle, bm25(search_index)
+		FROM search_index
+		WHERE search_index MATCH ?
+		ORDER BY bm25(search_index)
+		LIMIT ?
+	`, match, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+	defer rows.Close()
+
+	results := []SearchResult{}
+	for rows.Next() {
+		var res SearchResult
+		if err := rows.Scan(&res.Type, &res.ID, &res.Title, &res.Subtitle, &res.Rank); err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		results = append(results, res)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate search results: %w", err)
+	}
+	return results, nil
+}
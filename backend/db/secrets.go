@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"fmt"
+)
+
+// SecretDocumentKey names the key that signs document QR codes
+const SecretDocumentKey = "document_signing_key"
+
+// SecretAdminPassword names the bcrypt hash of the admin password
+const SecretAdminPassword = "admin_password_hash"
+
+// GetOrCreateSecret returns the named installation secret, generating size
+// random bytes the first time it is requested
+func (r *Repository) GetOrCreateSecret(ctx context.Context, name string, size int) ([]byte, error) {
+	value := make([]byte, size)
+	if _, err := rand.Read(value); err != nil {
+		return nil, fmt.Errorf("failed to generate secret: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, `INSERT OR IGNORE INTO app_secret (name, value) VALUES (?, ?)`, name, value); err != nil {
+		return nil, fmt.Errorf("failed to store secret: %w", err)
+	}
+
+	var stored []byte
+	if err := r.db.QueryRowContext(ctx, `SELECT value FROM app_secret WHERE name = ?`, name).Scan(&stored); err != nil {
+		return nil, fmt.Errorf("failed to get secret: %w", err)
+	}
+	return stored, nil
+}
+
+// GetSecret returns the named installation secret, or nil when it has not
+// been set
+func (r *Repository) GetSecret(ctx context.Context, name string) ([]byte, error) {
+	var value []byte
+	if err := r.db.QueryRowContext(ctx, `SELECT value FROM app_secret WHERE name = ?`, name).Scan(&value); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get secret: %w", err)
+	}
+	return value, nil
+}
+
+// SetSecret stores the named installation secret, replacing any previous value
+func (r *Repository) SetSecret(ctx context.Context, name string, value []byte) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO app_secret (name, value) VALUES (?, ?)
+		ON CONFLICT(name) DO UPDATE SET value = excluded.value
+	`, name, value)
+	if err != nil {
+		return fmt.Errorf("failed to store secret: %w", err)
+	}
+	return nil
+}
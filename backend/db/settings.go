@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// DefaultAppSettings are the settings of a new installation
+func DefaultAppSettings() AppSettings {
+	return AppSettings{
+		DefaultCurrency: "DZD",
+		DefaultPageSize: 20,
+		MaxPageSize:     100,
+		WindowWidth:     1200,
+		WindowHeight:    800,
+		LogLevel:        "info",
+		LogLevels:       map[string]string{},
+		DiscountCaps:    map[string]int{},
+		Locale:          "ar",
+	}
+}
+
+// GetAppSettings reads the application settings; fields never saved keep their defaults
+func (r *Repository) GetAppSettings(ctx context.Context) (*AppSettings, error) {
+	return r.getAppSettingsWith(ctx, r.db)
+}
+
+func (r *Repository) getAppSettingsWith(ctx context.Context, q querier) (*AppSettings, error) {
+	rows, err := q.QueryContext(ctx, `SELECT key, value FROM settings`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settings: %w", err)
+	}
+	defer rows.Close()
+
+	stored := map[string]json.RawMessage{}
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan setting: %w", err)
+		}
+		stored[key] = json.RawMessage(value)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate settings: %w", err)
+	}
+
+	settings := DefaultAppSettings()
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode settings: %w", err)
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("failed to decode settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// UpdateAppSettings saves every field of settings
+func (r *Repository) UpdateAppSettings(ctx context.Context, settings AppSettings) (*AppSettings, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := r.getAppSettingsWith(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode settings: %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to encode settings: %w", err)
+	}
+	query := `
+		INSERT INTO settings (key, value, updated_at) VALUES (?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at
+	`
+	for key, value := range fields {
+		if _, err := tx.ExecContext(ctx, query, key, string(value)); err != nil {
+			return nil, fmt.Errorf("failed to update setting %s: %w", key, err)
+		}
+	}
+
+	after, err := r.getAppSettingsWith(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.writeAudit(ctx, tx, AuditEntitySettings, 1, AuditActionUpdate, before, after); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return after, nil
+}
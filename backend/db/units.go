@@ -0,0 +1,136 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	apperr "barakaERP/backend/domain/errors"
+)
+
+// Product unit operations
+
+func scanProductUnit(row interface{ Scan(...interface{}) error }, unit *ProductUnit) error {
+	return row.Scan(&unit.ID, &unit.ProductID, &unit.Unit, &unit.Factor, &unit.CreatedAt, &unit.UpdatedAt)
+}
+
+// ListProductUnits lists the packaging units of a product, smallest first
+func (r *Repository) ListProductUnits(ctx context.Context, productID int64) ([]ProductUnit, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, product_id, unit, factor_milli, created_at, updated_at
+		FROM product_unit
+		WHERE product_id = ?
+		ORDER BY factor_milli, unit
+	`, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list product units: %w", err)
+	}
+	defer rows.Close()
+
+	units := []ProductUnit{}
+	for rows.Next() {
+		var unit ProductUnit
+		if err := scanProductUnit(rows, &unit); err != nil {
+			return nil, fmt.Errorf("failed to scan product unit: %w", err)
+		}
+		units = append(units, unit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate product units: %w", err)
+	}
+	return units, nil
+}
+
+func (r *Repository) getProductUnitWith(ctx context.Context, q querier, id int64) (*ProductUnit, error) {
+	query := `SELECT id, product_id, unit, factor_milli, created_at, updated_at FROM product_unit WHERE id = ?`
+	var unit ProductUnit
+	if err := scanProductUnit(q.QueryRowContext(ctx, query, id), &unit); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperr.NotFound("product_unit_not_found")
+		}
+		return nil, fmt.Errorf("failed to get product unit: %w", err)
+	}
+	return &unit, nil
+}
+
+// SaveProductUnit creates a packaging unit, or updates it when unit.ID is set
+func (r *Repository) SaveProductUnit(ctx context.Context, unit ProductUnit) (*ProductUnit, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var before interface{} // nil on create
+	action := AuditActionCreate
+	if unit.ID > 0 {
+		existing, err := r.getProductUnitWith(ctx, tx, unit.ID)
+		if err != nil {
+			return nil, err
+		}
+		before, action = existing, AuditActionUpdate
+		_, err = tx.ExecContext(ctx, `
+			UPDATE product_unit SET product_id = ?, unit = ?, factor_milli = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?
+		`, unit.ProductID, unit.Unit, unit.Factor, unit.ID)
+	} else {
+		var result sql.Result
+		result, err = tx.ExecContext(ctx, `
+			INSERT INTO product_unit (product_id, unit, factor_milli, created_at, updated_at)
+			VALUES (?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		`, unit.ProductID, unit.Unit, unit.Factor)
+		if err == nil {
+			unit.ID, err = result.LastInsertId()
+		}
+	}
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, apperr.Conflict("product_unit_taken").Wrap(err)
+		}
+		return nil, fmt.Errorf("failed to save product unit: %w", err)
+	}
+
+	after, err := r.getProductUnitWith(ctx, tx, unit.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.writeAudit(ctx, tx, AuditEntityProductUnit, unit.ID, action, before, after); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return after, nil
+}
+
+// DeleteProductUnit removes a packaging unit; order lines keep their unit
+func (r *Repository) DeleteProductUnit(ctx context.Context, id int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil { return fmt.Errorf("failed to begin transaction: %w", err) }
+	defer tx.Rollback()
+
+	before, err := r.getProductUnitWith(ctx, tx, id)
+	if err != nil { return err }
+	if _, err := tx.ExecContext(ctx, `DELETE FROM product_unit WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete product unit: %w", err)
+	}
+	if err := r.writeAudit(ctx, tx, AuditEntityProductUnit, id, AuditActionDelete, before, nil); err != nil { return err }
+	if err := tx.Commit(); err != nil { return fmt.Errorf("failed to commit transaction: %w", err) }
+	return nil
+}
+
+// UnitFactor returns how many base units of product one unit holds: 1 for
+// its base unit, the packaging unit's factor otherwise. Units the product is
+// not sold in are a validation error.
+func (r *Repository) UnitFactor(ctx context.Context, product *Product, unit string) (Quantity, error) {
+	if unit == "" || unit == product.Unit {
+		return Units(1), nil
+	}
+	var factor Quantity
+	err := r.db.QueryRowContext(ctx, `SELECT factor_milli FROM product_unit WHERE product_id = ? AND unit = ?`, product.ID, unit).Scan(&factor)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, apperr.Validation("unit", "unknown_unit").With("unit", unit)
+		}
+		return 0, fmt.Errorf("failed to get unit factor: %w", err)
+	}
+	return factor, nil
+}
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	apperr "barakaERP/backend/domain/errors"
+)
+
+// Licensed user operations
+
+// ListLicensedUsers lists the users holding a seat, by name
+func (r *Repository) ListLicensedUsers(ctx context.Context) ([]LicensedUser, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT name, created_at FROM licensed_user ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list licensed users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []LicensedUser{}
+	for rows.Next() {
+		var user LicensedUser
+		if err := rows.Scan(&user.Name, &user.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan licensed user: %w", err)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate licensed users: %w", err)
+	}
+	return users, nil
+}
+
+// ClaimSeat registers name as a licensed user unless limit users already are
+// (0 means unlimited). It reports whether name holds a seat afterwards.
+func (r *Repository) ClaimSeat(ctx context.Context, name string, limit int) (bool, error) {
+	held, err := r.holdsSeat(ctx, name)
+	if err != nil || held {
+		return held, err
+	}
+	// One statement, so two sessions cannot both take the last seat
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO licensed_user (name)
+		SELECT ? WHERE ? = 0 OR (SELECT COUNT(*) FROM licensed_user) < ?
+		ON CONFLICT(name) DO NOTHING
+	`, name, limit, limit)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim seat: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		return true, nil
+	}
+	// Claimed meanwhile by another session, or no seat left
+	return r.holdsSeat(ctx, name)
+}
+
+// ReleaseSeat frees the seat held by name
+func (r *Repository) ReleaseSeat(ctx context.Context, name string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM licensed_user WHERE name = ?`, name)
+	if err != nil {
+		return fmt.Errorf("failed to release seat: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return apperr.NotFound("licensed_user_not_found")
+	}
+	return nil
+}
+
+func (r *Repository) holdsSeat(ctx context.Context, name string) (bool, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM licensed_user WHERE name = ?`, name).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check seat: %w", err)
+	}
+	return count > 0, nil
+}
@@ -1,16 +1,22 @@
 package db
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
 )
 
 // CalcOrderTotals calculates order totals based on items, discount and tax percentages
 // Note: discountPct is ignored - global discount is just UI convenience for setting item discounts
 func CalcOrderTotals(items []OrderItem, discountPct, taxPct int) (subtotal, discount, tax, total int64) {
-	// Calculate subtotal and discount from items only
+	// Calculate subtotal and discount from items only; line totals come from
+	// the exact quantity, rounded to the cent once per line
 	for _, item := range items {
-		itemSubtotal := item.TotalCents
+		itemSubtotal := item.Qty.Amount(item.UnitPriceCents)
 		itemDiscount := (itemSubtotal * int64(item.DiscountPercent)) / 100
 		subtotal += itemSubtotal
 		discount += itemDiscount
@@ -120,7 +126,7 @@ func ValidateTaxPercent(pct int) error {
 }
 
 // ValidateQuantity validates item quantity (must be positive)
-func ValidateQuantity(qty int) error {
+func ValidateQuantity(qty Quantity) error {
 	if qty <= 0 {
 		return fmt.Errorf("quantity must be greater than 0")
 	}
@@ -169,8 +175,8 @@ func IsValidPaymentMethod(method string) bool {
 }
 
 // CalculateItemTotal calculates the total for an order/invoice item
-func CalculateItemTotal(qty int, unitPriceCents int64) int64 {
-	return int64(qty) * unitPriceCents
+func CalculateItemTotal(qty Quantity, unitPriceCents int64) int64 {
+	return qty.Amount(unitPriceCents)
 }
 
 // CalculateInvoiceBalance calculates remaining balance for an invoice
@@ -181,3 +187,32 @@ func CalculateInvoiceBalance(totalCents int64, payments []Payment) int64 {
 	}
 	return totalCents - paidCents
 }
+
+// sameString compares two optional strings by value
+func sameString(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// sqlPlaceholders returns "?, ?, ..." with n placeholders for IN (...) clauses
+func sqlPlaceholders(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// isForeignKeyViolation reports whether err is SQLite refusing a change that
+// would break a foreign key, e.g. deleting a client its orders reference
+func isForeignKeyViolation(err error) bool {
+	var e *sqlite.Error
+	return errors.As(err, &e) && e.Code() == sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY
+}
+
+// isUniqueViolation reports whether err is a duplicate value in a UNIQUE column
+func isUniqueViolation(err error) bool {
+	var e *sqlite.Error
+	return errors.As(err, &e) && e.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+}
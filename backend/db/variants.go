@@ -0,0 +1,51 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// Product variant operations. Variants are products with a parent_id and
+// are created, updated and deleted through the product operations.
+
+// ListProductVariants lists the variants of a product that are not in the
+// recycle bin, by name
+func (r *Repository) ListProductVariants(ctx context.Context, parentID int64) ([]Product, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+productColumns+`
+		FROM product
+		WHERE parent_id = ? AND deleted_at IS NULL
+		ORDER BY name, id
+	`, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list product variants: %w", err)
+	}
+	defer rows.Close()
+
+	variants := []Product{}
+	for rows.Next() {
+		var variant Product
+		if err := scanProduct(rows, &variant); err != nil {
+			return nil, fmt.Errorf("failed to scan product variant: %w", err)
+		}
+		variants = append(variants, variant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate product variants: %w", err)
+	}
+	return variants, nil
+}
+
+// CountProductVariants counts the variants of a product, those in the
+// recycle bin too when includeDeleted is set
+func (r *Repository) CountProductVariants(ctx context.Context, parentID int64, includeDeleted bool) (int64, error) {
+	query := `SELECT COUNT(*) FROM product WHERE parent_id = ? AND deleted_at IS NULL`
+	if includeDeleted {
+		query = `SELECT COUNT(*) FROM product WHERE parent_id = ?`
+	}
+	var count int64
+	if err := r.db.QueryRowContext(ctx, query, parentID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count product variants: %w", err)
+	}
+	return count, nil
+}
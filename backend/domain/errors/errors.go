@@ -0,0 +1,133 @@
+// Package errors defines the typed errors services return to the frontend.
+// Each error carries a code for errors.Is and a message key; the message
+// itself comes from the "errors" section of the i18n catalog, in the locale
+// of the request when the error is sent to the frontend.
+package errors
+
+import (
+	"errors"
+	"barakaERP/backend/i18n"
+)
+
+// Code classifies an error
+type Code string
+
+// Error codes
+const (
+	CodeNotFound   Code = "not_found"
+	CodeValidation Code = "validation"
+	CodeConflict   Code = "conflict"
+	CodeForbidden  Code = "forbidden"
+	CodeInternal   Code = "internal"
+)
+
+// Sentinels for errors.Is: any error of the same code matches, e.g.
+// errors.Is(err, errors.ErrNotFound)
+var (
+	ErrNotFound   = &Error{Code: CodeNotFound}
+	ErrValidation = &Error{Code: CodeValidation}
+	ErrConflict   = &Error{Code: CodeConflict}
+	ErrForbidden  = &Error{Code: CodeForbidden}
+	ErrInternal   = &Error{Code: CodeInternal}
+)
+
+// Error is a domain error. It is also the payload sent to the frontend.
+type Error struct {
+	Code    Code           `json:"code"`
+	Key     string         `json:"key"`             // message key under "errors" in the locale files
+	Field   string         `json:"field,omitempty"` // invalid field, for validation errors
+	Params  map[string]any `json:"params,omitempty"`
+	Message string         `json:"message"` // set by Localize, or the cause of internal errors
+	Err     error          `json:"-"`
+}
+
+// Error returns the message in the default locale
+func (e *Error) Error() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	if e.Key != "" && i18n.Has(i18n.Default, "errors."+e.Key) {
+		return i18n.T(i18n.Default, "errors."+e.Key, e.Params)
+	}
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return string(e.Code)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches errors of the same code; a target with a key must match it too
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return t.Code == e.Code && (t.Key == "" || t.Key == e.Key)
+}
+
+// With returns a copy of e with a message parameter set, e.g. the item number
+// of an invalid order line
+func (e *Error) With(name string, value any) *Error {
+	c := *e
+	c.Params = make(map[string]any, len(e.Params)+1)
+	for k, v := range e.Params {
+		c.Params[k] = v
+	}
+	c.Params[name] = value
+	return &c
+}
+
+// Wrap returns a copy of e caused by err
+func (e *Error) Wrap(err error) *Error {
+	c := *e
+	c.Err = err
+	return &c
+}
+
+// Localize returns a copy of e with Message in locale. Internal errors keep
+// the message of their cause.
+func (e *Error) Localize(locale string) *Error {
+	c := *e
+	if c.Message == "" {
+		c.Message = i18n.T(locale, "errors."+e.Key, e.Params)
+	}
+	return &c
+}
+
+// NotFound reports a missing record
+func NotFound(key string) *Error {
+	return &Error{Code: CodeNotFound, Key: key}
+}
+
+// Validation reports invalid input in field ("" when not tied to one field)
+func Validation(field, key string) *Error {
+	return &Error{Code: CodeValidation, Key: key, Field: field}
+}
+
+// Conflict reports an operation the current state does not allow, such as
+// deleting a record others still reference
+func Conflict(key string) *Error {
+	return &Error{Code: CodeConflict, Key: key}
+}
+
+// Forbidden reports an operation the current user or license may not perform
+func Forbidden(key string) *Error {
+	return &Error{Code: CodeForbidden, Key: key}
+}
+
+// Internal wraps an unexpected failure
+func Internal(err error) *Error {
+	return &Error{Code: CodeInternal, Key: "internal", Message: err.Error(), Err: err}
+}
+
+// From returns the domain error in err's chain, or err as an internal error
+func From(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return Internal(err)
+}
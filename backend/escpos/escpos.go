@@ -0,0 +1,134 @@
+// Package escpos builds raw command streams for ESC/POS thermal receipt printers
+package escpos
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+)
+
+// Command prefixes
+const (
+	esc = 0x1b
+	gs  = 0x1d
+	lf  = 0x0a
+)
+
+// Text alignment values for Align
+const (
+	AlignLeft   byte = 0
+	AlignCenter byte = 1
+	AlignRight  byte = 2
+)
+
+// maxRasterRows caps the height of one GS v 0 image; taller images are sent
+// in bands because many printers have small receive buffers
+const maxRasterRows = 256
+
+// Builder accumulates an ESC/POS byte stream
+type Builder struct {
+	buf bytes.Buffer
+}
+
+// NewBuilder creates a builder whose stream starts by resetting the printer
+func NewBuilder() *Builder {
+	b := &Builder{}
+	b.buf.Write([]byte{esc, '@'})
+	return b
+}
+
+// Bytes returns the stream built so far
+func (b *Builder) Bytes() []byte {
+	return b.buf.Bytes()
+}
+
+// CodePage selects character code table n (ESC t n). Table numbers differ
+// between printer models; see the printer's self-test page.
+func (b *Builder) CodePage(n byte) *Builder {
+	b.buf.Write([]byte{esc, 't', n})
+	return b
+}
+
+// Align sets the justification of following lines (ESC a n)
+func (b *Builder) Align(align byte) *Builder {
+	b.buf.Write([]byte{esc, 'a', align})
+	return b
+}
+
+// Bold turns emphasized printing on or off (ESC E n)
+func (b *Builder) Bold(on bool) *Builder {
+	b.buf.Write([]byte{esc, 'E', boolByte(on)})
+	return b
+}
+
+// Size sets character magnification, 1 to 8 in each direction (GS ! n)
+func (b *Builder) Size(width, height int) *Builder {
+	b.buf.Write([]byte{gs, '!', byte(clamp(width)-1)<<4 | byte(clamp(height)-1)})
+	return b
+}
+
+// Line writes text already encoded for the selected code page, then a line feed
+func (b *Builder) Line(text []byte) *Builder {
+	b.buf.Write(text)
+	b.buf.WriteByte(lf)
+	return b
+}
+
+// Feed prints and feeds n lines (ESC d n)
+func (b *Builder) Feed(n byte) *Builder {
+	b.buf.Write([]byte{esc, 'd', n})
+	return b
+}
+
+// Cut feeds to the cutter and performs a partial cut (GS V 66 0)
+func (b *Builder) Cut() *Builder {
+	b.buf.Write([]byte{gs, 'V', 66, 0})
+	return b
+}
+
+// Raster prints img as a 1-bit raster image (GS v 0). Pixels darker than
+// mid-gray are printed; the image should be at most the printer's dot width.
+func (b *Builder) Raster(img image.Image) *Builder {
+	bounds := img.Bounds()
+	widthBytes := (bounds.Dx() + 7) / 8
+	for top := bounds.Min.Y; top < bounds.Max.Y; top += maxRasterRows {
+		rows := bounds.Max.Y - top
+		if rows > maxRasterRows {
+			rows = maxRasterRows
+		}
+		b.buf.Write([]byte{gs, 'v', '0', 0,
+			byte(widthBytes), byte(widthBytes >> 8),
+			byte(rows), byte(rows >> 8)})
+		line := make([]byte, widthBytes)
+		for y := top; y < top+rows; y++ {
+			for i := range line {
+				line[i] = 0
+			}
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				if gray := color.GrayModel.Convert(img.At(x, y)).(color.Gray); gray.Y < 128 {
+					dx := x - bounds.Min.X
+					line[dx/8] |= 0x80 >> (dx % 8)
+				}
+			}
+			b.buf.Write(line)
+		}
+	}
+	return b
+}
+
+func boolByte(on bool) byte {
+	if on {
+		return 1
+	}
+	return 0
+}
+
+func clamp(n int) int {
+	if n < 1 {
+		return 1
+	}
+	if n > 8 {
+		return 8
+	}
+	return n
+}
@@ -0,0 +1,74 @@
+package escpos
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestBuilderCommands(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(b *Builder)
+		want  []byte
+	}{
+		{"reset", func(b *Builder) {}, nil},
+		{"code page", func(b *Builder) { b.CodePage(50) }, []byte{0x1b, 't', 50}},
+		{"align right", func(b *Builder) { b.Align(AlignRight) }, []byte{0x1b, 'a', 2}},
+		{"bold", func(b *Builder) { b.Bold(true).Bold(false) }, []byte{0x1b, 'E', 1, 0x1b, 'E', 0}},
+		{"size", func(b *Builder) { b.Size(2, 3) }, []byte{0x1d, '!', 0x12}},
+		{"size clamped", func(b *Builder) { b.Size(0, 9) }, []byte{0x1d, '!', 0x07}},
+		{"line", func(b *Builder) { b.Line([]byte("abc")) }, []byte{'a', 'b', 'c', 0x0a}},
+		{"feed", func(b *Builder) { b.Feed(4) }, []byte{0x1b, 'd', 4}},
+		{"cut", func(b *Builder) { b.Cut() }, []byte{0x1d, 'V', 66, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBuilder()
+			tt.build(b)
+			want := append([]byte{0x1b, '@'}, tt.want...)
+			if got := b.Bytes(); !bytes.Equal(got, want) {
+				t.Errorf("got % x, want % x", got, want)
+			}
+		})
+	}
+}
+
+func TestBuilderRaster(t *testing.T) {
+	// 10x2 image: the first row has dots at x=0 and x=9, the second is blank
+	img := image.NewGray(image.Rect(0, 0, 10, 2))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	img.SetGray(0, 0, color.Gray{})
+	img.SetGray(9, 0, color.Gray{Y: 100})
+
+	got := NewBuilder().Raster(img).Bytes()
+	want := []byte{0x1b, '@',
+		0x1d, 'v', '0', 0, 2, 0, 2, 0,
+		0x80, 0x40,
+		0x00, 0x00,
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("got % x, want % x", got, want)
+	}
+}
+
+func TestBuilderRasterBands(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 8, maxRasterRows+1))
+	got := NewBuilder().Raster(img).Bytes()
+
+	// one full band and one band of a single row, each row one byte wide
+	header := func(rows int) []byte {
+		return []byte{0x1d, 'v', '0', 0, 1, 0, byte(rows), byte(rows >> 8)}
+	}
+	want := []byte{0x1b, '@'}
+	want = append(want, header(maxRasterRows)...)
+	want = append(want, bytes.Repeat([]byte{0xff}, maxRasterRows)...)
+	want = append(want, header(1)...)
+	want = append(want, 0xff)
+	if !bytes.Equal(got, want) {
+		t.Errorf("got %d bytes, want %d", len(got), len(want))
+	}
+}
@@ -0,0 +1,79 @@
+package i18n
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FormatDate formats the date part of t, e.g. 2025-03-14 or 14/03/2025
+func FormatDate(locale string, t time.Time) string {
+	return t.Format(T(locale, "format.date", nil))
+}
+
+// FormatDateTime formats t with minutes, e.g. 14/03/2025 09:30
+func FormatDateTime(locale string, t time.Time) string {
+	return t.Format(T(locale, "format.datetime", nil))
+}
+
+// FormatAmount formats an amount in cents with the locale's decimal and
+// digit group separators, e.g. 1500.00, 1,500.00 or 1 500,00
+func FormatAmount(locale string, cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	units := groupDigits(locale, strconv.FormatInt(cents/100, 10))
+	sub := strconv.FormatInt(cents%100+100, 10)[1:]
+	return sign + units + T(locale, "format.decimal", nil) + sub
+}
+
+// FormatQuantity formats a quantity in thousandths of a unit without
+// trailing zeros, e.g. 12, 1.5 or 1 250,125
+func FormatQuantity(locale string, milli int64) string {
+	sign := ""
+	if milli < 0 {
+		sign, milli = "-", -milli
+	}
+	s := sign + groupDigits(locale, strconv.FormatInt(milli/1000, 10))
+	if frac := milli % 1000; frac != 0 {
+		s += T(locale, "format.decimal", nil) + strings.TrimRight(strconv.FormatInt(frac+1000, 10)[1:], "0")
+	}
+	return s
+}
+
+// UnitLabel is the name of a unit of measure in locale (e.g. kg, carton),
+// or the unit code itself when the catalog has none
+func UnitLabel(locale, unit string) string {
+	if Has(locale, "unit."+unit) {
+		return T(locale, "unit."+unit, nil)
+	}
+	return unit
+}
+
+// groupDigits inserts the locale's digit group separator every 3 digits
+func groupDigits(locale, digits string) string {
+	group := T(locale, "format.group", nil)
+	if group == "" {
+		return digits
+	}
+	var b strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteString(group)
+		}
+		b.WriteRune(digit)
+	}
+	return b.String()
+}
+
+// FormatMoney formats an amount in cents with its currency (DZD when empty)
+func FormatMoney(locale string, cents int64, currency string) string {
+	if currency == "" {
+		currency = "DZD"
+	}
+	return T(locale, "format.money", map[string]interface{}{
+		"amount":   FormatAmount(locale, cents),
+		"currency": currency,
+	})
+}
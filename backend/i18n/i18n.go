@@ -0,0 +1,141 @@
+// Package i18n is the message catalog of the backend: error messages,
+// document labels and the date and number formats of each locale.
+//
+// Catalogs are the JSON files in locales/, nested like the frontend ones and
+// addressed by dotted keys ("errors.client_not_found", "doc.phone").
+// Messages may contain {name} placeholders filled from parameters.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// Supported locales
+const (
+	Arabic  = "ar"
+	English = "en"
+	French  = "fr"
+)
+
+// Default is used when no locale is chosen and for keys a locale lacks
+const Default = Arabic
+
+// Locales lists the supported locales
+var Locales = []string{Arabic, English, French}
+
+var catalogs = mustLoadCatalogs()
+
+func mustLoadCatalogs() map[string]map[string]string {
+	all := make(map[string]map[string]string, len(Locales))
+	for _, locale := range Locales {
+		data, err := localeFiles.ReadFile("locales/" + locale + ".json")
+		if err != nil {
+			panic(fmt.Sprintf("i18n: missing catalog %s: %v", locale, err))
+		}
+		var tree map[string]interface{}
+		if err := json.Unmarshal(data, &tree); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalog %s: %v", locale, err))
+		}
+		messages := map[string]string{}
+		flatten("", tree, messages)
+		all[locale] = messages
+	}
+	return all
+}
+
+func flatten(prefix string, tree map[string]interface{}, out map[string]string) {
+	for k, v := range tree {
+		switch val := v.(type) {
+		case string:
+			out[prefix+k] = val
+		case map[string]interface{}:
+			flatten(prefix+k+".", val, out)
+		}
+	}
+}
+
+// Normalize maps a language tag such as "fr-DZ" or "AR" to a supported
+// locale, or returns "" when it is not supported
+func Normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	if _, ok := catalogs[tag]; ok {
+		return tag
+	}
+	return ""
+}
+
+// orDefault returns the supported locale for tag, falling back to Default
+func orDefault(tag string) string {
+	if locale := Normalize(tag); locale != "" {
+		return locale
+	}
+	return Default
+}
+
+// Has reports whether the locale's catalog (or the default one) defines key
+func Has(locale, key string) bool {
+	_, ok := lookup(locale, key)
+	return ok
+}
+
+// T returns the message for key in locale with its {name} placeholders
+// filled from params. Keys missing from the locale come from the default
+// catalog; unknown keys are returned as is.
+func T(locale, key string, params map[string]interface{}) string {
+	msg, ok := lookup(locale, key)
+	if !ok {
+		return key
+	}
+	return Fill(msg, params)
+}
+
+func lookup(locale, key string) (string, bool) {
+	if msg, ok := catalogs[orDefault(locale)][key]; ok {
+		return msg, true
+	}
+	msg, ok := catalogs[Default][key]
+	return msg, ok
+}
+
+// Fill replaces the {name} placeholders of msg that params define. Others
+// are left in place, so document labels can still bind to document data.
+func Fill(msg string, params map[string]interface{}) string {
+	if len(params) == 0 {
+		return msg
+	}
+	pairs := make([]string, 0, 2*len(params))
+	for name, v := range params {
+		pairs = append(pairs, "{"+name+"}", fmt.Sprint(v))
+	}
+	return strings.NewReplacer(pairs...).Replace(msg)
+}
+
+// Direction is the reading direction of locale: "rtl" or "ltr"
+func Direction(locale string) string {
+	return T(locale, "format.direction", nil)
+}
+
+type localeKey struct{}
+
+// WithLocale returns a context carrying the locale of a request
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, orDefault(locale))
+}
+
+// FromContext returns the request locale, or Default when none is set
+func FromContext(ctx context.Context) string {
+	if locale, ok := ctx.Value(localeKey{}).(string); ok {
+		return locale
+	}
+	return Default
+}
@@ -0,0 +1,169 @@
+// Package logging provides leveled, structured logging (log/slog) per
+// subsystem, written to a size-rotated file and kept in memory for the
+// support screen.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Subsystems with their own log level
+const (
+	App     = "app"
+	DB      = "db"
+	Clients = "clients" // clients and debt payments
+	Orders  = "orders"
+	PDF     = "pdf"
+	License = "license"
+)
+
+// Subsystems lists the subsystems whose level can be configured
+var Subsystems = []string{App, DB, Clients, Orders, PDF, License}
+
+// manager routes every subsystem logger to one output and one ring of recent entries
+type manager struct {
+	mu           sync.RWMutex
+	out          io.Writer
+	defaultLevel slog.Level
+	levels       map[string]slog.Level
+	recent       *ring
+	closer       io.Closer
+}
+
+var std = &manager{
+	out:          os.Stderr,
+	defaultLevel: slog.LevelInfo,
+	levels:       map[string]slog.Level{},
+	recent:       newRing(2000),
+}
+
+// Setup sends logs to path, rotated once it exceeds maxSize bytes with
+// backups older files kept (app.log.1 is the most recent), and to stdout.
+// Output from the standard log package goes to the App subsystem at Info.
+func Setup(path string, maxSize int64, backups int) error {
+	file, err := OpenRotatingFile(path, maxSize, backups)
+	if err != nil {
+		return err
+	}
+	std.mu.Lock()
+	old := std.closer
+	std.out = io.MultiWriter(file, os.Stdout)
+	std.closer = file
+	std.mu.Unlock()
+	if old != nil {
+		old.Close()
+	}
+	slog.SetDefault(For(App))
+	return nil
+}
+
+// Close flushes and closes the log file; later output goes to stderr
+func Close() error {
+	std.mu.Lock()
+	defer std.mu.Unlock()
+	std.out = os.Stderr
+	if std.closer == nil {
+		return nil
+	}
+	err := std.closer.Close()
+	std.closer = nil
+	return err
+}
+
+// For returns the logger of a subsystem. Records carry a "subsystem" attribute.
+func For(subsystem string) *slog.Logger {
+	text := slog.NewTextHandler(writerFunc(std.write), &slog.HandlerOptions{Level: slog.LevelDebug - 4})
+	return slog.New(&handler{
+		Handler:   text.WithAttrs([]slog.Attr{slog.String("subsystem", subsystem)}),
+		subsystem: subsystem,
+	})
+}
+
+// SetLevels sets the default level and per-subsystem overrides, replacing previous ones
+func SetLevels(defaultLevel slog.Level, levels map[string]slog.Level) {
+	std.mu.Lock()
+	defer std.mu.Unlock()
+	std.defaultLevel = defaultLevel
+	std.levels = map[string]slog.Level{}
+	for sub, level := range levels {
+		std.levels[sub] = level
+	}
+}
+
+// ParseLevel reads debug, info, warn or error (case insensitive)
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+		return 0, fmt.Errorf("unknown log level %q", s)
+	}
+	return level, nil
+}
+
+func (m *manager) level(subsystem string) slog.Level {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if level, ok := m.levels[subsystem]; ok {
+		return level
+	}
+	return m.defaultLevel
+}
+
+func (m *manager) write(p []byte) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.out.Write(p)
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
+
+// handler filters records by their subsystem's level and records them in
+// the ring before formatting them as text
+type handler struct {
+	slog.Handler
+	subsystem string
+	group     string            // attribute key prefix from WithGroup
+	attrs     map[string]string // attributes from WithAttrs, for Entry
+}
+
+func (h *handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= std.level(h.subsystem)
+}
+
+func (h *handler) Handle(ctx context.Context, r slog.Record) error {
+	entry := Entry{Time: r.Time, Level: r.Level.String(), Subsystem: h.subsystem, Message: r.Message}
+	if len(h.attrs) > 0 || r.NumAttrs() > 0 {
+		entry.Attrs = make(map[string]string, len(h.attrs)+r.NumAttrs())
+		for k, v := range h.attrs {
+			entry.Attrs[k] = v
+		}
+		r.Attrs(func(a slog.Attr) bool {
+			entry.Attrs[h.group+a.Key] = a.Value.Resolve().String()
+			return true
+		})
+	}
+	std.recent.add(entry)
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	merged := make(map[string]string, len(h.attrs)+len(attrs))
+	for k, v := range h.attrs {
+		merged[k] = v
+	}
+	for _, a := range attrs {
+		merged[h.group+a.Key] = a.Value.Resolve().String()
+	}
+	return &handler{Handler: h.Handler.WithAttrs(attrs), subsystem: h.subsystem, group: h.group, attrs: merged}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{Handler: h.Handler.WithGroup(name), subsystem: h.subsystem, group: h.group + name + ".", attrs: h.attrs}
+}
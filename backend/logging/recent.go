@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Entry is a log record kept in memory for the support screen
+type Entry struct {
+	Time      time.Time         `json:"time"`
+	Level     string            `json:"level"`
+	Subsystem string            `json:"subsystem"`
+	Message   string            `json:"message"`
+	Attrs     map[string]string `json:"attrs,omitempty"`
+}
+
+// ring holds the latest entries, overwriting the oldest
+type ring struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    int
+	full    bool
+}
+
+func newRing(size int) *ring {
+	return &ring{entries: make([]Entry, size)}
+}
+
+func (r *ring) add(e Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[r.next] = e
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Recent returns up to limit of the latest entries at minLevel or above,
+// newest first; subsystem, if not empty, keeps only that subsystem's
+func Recent(minLevel slog.Level, subsystem string, limit int) []Entry {
+	r := std.recent
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := r.next
+	if r.full {
+		count = len(r.entries)
+	}
+	var out []Entry
+	for i := 1; i <= count && len(out) < limit; i++ {
+		e := r.entries[(r.next-i+len(r.entries))%len(r.entries)]
+		if subsystem != "" && e.Subsystem != subsystem {
+			continue
+		}
+		var level slog.Level
+		if level.UnmarshalText([]byte(e.Level)) != nil || level < minLevel {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
@@ -0,0 +1,112 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeLines writes each line to f
+func writeLines(t *testing.T, f *RotatingFile, lines ...string) {
+	t.Helper()
+	for _, line := range lines {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// checkFile compares the content of path with want; a missing file reads as ""
+func checkFile(t *testing.T, path, want string) {
+	t.Helper()
+	got, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("%s: got %q, want %q", filepath.Base(path), got, want)
+	}
+}
+
+func TestRotatingFileShiftsBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	f, err := OpenRotatingFile(path, 8, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	// each line fills the file, so every write after the first rotates
+	writeLines(t, f, "first\n", "second\n", "third\n", "fourth\n")
+	checkFile(t, path, "fourth\n")
+	checkFile(t, path+".1", "third\n")
+	checkFile(t, path+".2", "second\n")
+	checkFile(t, path+".3", "")
+}
+
+func TestRotatingFileAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := OpenRotatingFile(path, 9, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	// the existing content counts towards the maximum size
+	writeLines(t, f, "a\n", "b\n", "c\n")
+	checkFile(t, path, "c\n")
+	checkFile(t, path+".1", "old\na\nb\n")
+}
+
+func TestRotatingFileWithoutBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	f, err := OpenRotatingFile(path, 8, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	writeLines(t, f, "first\n", "second\n")
+	checkFile(t, path, "second\n")
+	checkFile(t, path+".1", "")
+}
+
+func TestRotatingFileRenameFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	// a directory in the way of the first backup makes the rename fail
+	if err := os.MkdirAll(filepath.Join(path+".1", "busy"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := OpenRotatingFile(path, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	writeLines(t, f, "first\n", "second\n")
+	checkFile(t, path, "first\nsecond\n")
+
+	// once the way is clear, the next write rotates
+	if err := os.RemoveAll(path + ".1"); err != nil {
+		t.Fatal(err)
+	}
+	writeLines(t, f, "third\n")
+	checkFile(t, path, "third\n")
+	checkFile(t, path+".1", "first\nsecond\n")
+}
+
+func TestRotatingFileClosed(t *testing.T) {
+	f, err := OpenRotatingFile(filepath.Join(t.TempDir(), "app.log"), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("late\n")); err != os.ErrClosed {
+		t.Errorf("got %v, want os.ErrClosed", err)
+	}
+}
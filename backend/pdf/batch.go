@@ -0,0 +1,168 @@
+package pdf
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"barakaERP/backend/db"
+	apperr "barakaERP/backend/domain/errors"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Batch export formats
+const (
+	BatchMerged = "pdf" // one PDF, each order starting on a new page
+	BatchZIP    = "zip" // a ZIP archive with one PDF per order
+)
+
+// CheckBatchFormat rejects a format that is not one of the batch formats
+func CheckBatchFormat(format string) error {
+	if format != BatchMerged && format != BatchZIP {
+		return apperr.Validation("format", "unknown_export_format").With("format", format) // Unknown batch format
+	}
+	return nil
+}
+
+// maxBatchWorkers bounds the goroutines preparing a batch and rendering ZIP exports
+const maxBatchWorkers = 8
+
+// BatchProgress is reported after each order of a batch export
+type BatchProgress struct {
+	Done        int    `json:"done"`
+	Total       int    `json:"total"`
+	OrderNumber string `json:"order_number"`
+}
+
+// GenerateOrdersPDF renders many orders at once, either merged into one PDF
+// or as a ZIP of individual PDFs. Orders are prepared by a bounded worker
+// pool, which also renders each PDF of a ZIP export. A merged PDF is a single
+// fpdf document that cannot be assembled from separately rendered files, so
+// its pages are rendered serially, in order, once the data is ready. progress,
+// if not nil, is called after each order and may be called from several
+// goroutines. locale is as for GenerateOrderPDF.
+func (g *OrderPDFGenerator) GenerateOrdersPDF(ctx context.Context, orders []db.OrderDetail, company db.CompanySettings, format, locale string, progress func(BatchProgress)) ([]byte, error) {
+	if err := CheckBatchFormat(format); err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return nil, apperr.NotFound("no_orders_to_export") // No orders to export
+	}
+	tpl, err := LoadTemplate(g.templateDir, TemplateOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	done := 0
+	report := func(i int) {
+		if progress == nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		done++
+		progress(BatchProgress{Done: done, Total: len(orders), OrderNumber: orders[i].Order.OrderNumber})
+	}
+
+	docs := make([]map[string]interface{}, len(orders))
+	files := make([][]byte, len(orders))
+	err = runWorkers(ctx, len(orders), func(i int) error {
+		data, err := g.orderData(orders[i], company)
+		if err != nil {
+			return fmt.Errorf("order %s: %w", orders[i].Order.OrderNumber, err)
+		}
+		if format == BatchMerged {
+			docs[i] = data
+			return nil
+		}
+		if files[i], err = renderTemplate(tpl, g.fonts, data, &company, locale); err != nil {
+			return fmt.Errorf("order %s: %w", orders[i].Order.OrderNumber, err)
+		}
+		report(i)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if format == BatchMerged {
+		return renderDocuments(tpl, g.fonts, docs, &company, locale, report)
+	}
+	return zipFiles(orders, files)
+}
+
+// runWorkers calls job for 0..n-1 on at most maxBatchWorkers goroutines and
+// returns the first error; remaining jobs are skipped after an error or when
+// ctx is canceled
+func runWorkers(ctx context.Context, n int, job func(i int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := runtime.NumCPU()
+	if workers > maxBatchWorkers {
+		workers = maxBatchWorkers
+	}
+	if workers > n {
+		workers = n
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := job(i); err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// zipFiles packs one PDF per order, named after the order number
+func zipFiles(orders []db.OrderDetail, files [][]byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	now := time.Now()
+	for i, order := range orders {
+		name := strings.NewReplacer("/", "-", `\`, "-").Replace(order.Order.OrderNumber) + ".pdf"
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return nil, fmt.Errorf("failed to add %s to archive: %w", name, err)
+		}
+		if _, err := w.Write(files[i]); err != nil {
+			return nil, fmt.Errorf("failed to add %s to archive: %w", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write archive: %w", err)
+	}
+	return buf.Bytes(), nil
+}
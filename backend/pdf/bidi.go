@@ -0,0 +1,354 @@
+package pdf
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/01walid/goarabic"
+	"golang.org/x/text/unicode/bidi"
+)
+
+// textDirection is the base (paragraph) direction used to lay out a line
+type textDirection int
+
+const (
+	dirAuto textDirection = iota // from the first strong character (UAX #9 rules P2–P3)
+	dirRTL
+	dirLTR
+)
+
+// lrm is LEFT-TO-RIGHT MARK; prefixed to force an LTR paragraph because the
+// bidi package only lets us force RTL
+const lrm = '\u200e'
+
+// visualText converts logical text into the order fpdf must draw it in
+// (strictly left to right), following the Unicode Bidirectional Algorithm:
+// Arabic letters are shaped into presentation forms, runs are resolved with
+// golang.org/x/text/unicode/bidi, then reordered per rule L2 with mirrored
+// brackets in RTL runs. Mixed strings like "مقلاة 28cm Tefal", phone numbers
+// and SKUs inside Arabic sentences keep their own reading order.
+func visualText(s string, base textDirection) string {
+	if !hasRTL(s) {
+		return s
+	}
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = visualLine(line, base)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func visualLine(s string, base textDirection) string {
+	if s == "" {
+		return s
+	}
+	shaped := goarabic.ToGlyph(s)
+	if base == dirAuto {
+		base = firstStrongDirection(shaped)
+	}
+
+	var opts []bidi.Option
+	baseLevel := 0
+	if base == dirRTL {
+		opts = append(opts, bidi.DefaultDirection(bidi.RightToLeft))
+		baseLevel = 1
+	} else {
+		shaped = string(lrm) + shaped
+	}
+
+	var p bidi.Paragraph
+	if _, err := p.SetString(shaped, opts...); err != nil {
+		return s
+	}
+	order, err := p.Order()
+	if err != nil || order.NumRuns() == 0 {
+		return s
+	}
+
+	// The package resolves run directions but neither exposes embedding levels
+	// nor reorders runs, so levels are rebuilt from the runs here.
+	var runes []rune
+	var levels []int
+	lastStrong := bidi.L
+	if baseLevel == 1 {
+		lastStrong = bidi.R
+	}
+	for i := 0; i < order.NumRuns(); i++ {
+		run := order.Run(i)
+		rs := []rune(run.String())
+		for j, r := range rs {
+			class := runeClass(r)
+			level := baseLevel
+			switch {
+			case run.Direction() == bidi.RightToLeft:
+				// Odd level: 1 in an LTR paragraph and in an RTL one alike
+				level = 1
+			case baseLevel == 1:
+				// LTR text (Latin, numbers) embedded in an RTL paragraph
+				level = 2
+			case isNumberPart(rs, j) && (class == bidi.AN || lastStrong != bidi.L):
+				// Numbers following Arabic in an LTR paragraph stay with the Arabic (W2, I1)
+				level = 2
+			}
+			switch class {
+			case bidi.L:
+				lastStrong = bidi.L
+			case bidi.R, bidi.AL:
+				lastStrong = bidi.R
+			}
+			runes = append(runes, r)
+			levels = append(levels, level)
+		}
+	}
+
+	resolveBrackets(runes, levels, baseLevel)
+
+	// L4: mirror brackets at odd levels
+	for i, r := range runes {
+		if levels[i]%2 == 1 {
+			if prop, _ := bidi.LookupRune(r); prop.IsBracket() {
+				runes[i] = mirrorRune(r)
+			}
+		}
+	}
+
+	// L2: from the highest level down to 1, reverse every sequence at that level or higher
+	maxLevel := 0
+	for _, l := range levels {
+		if l > maxLevel {
+			maxLevel = l
+		}
+	}
+	for level := maxLevel; level >= 1; level-- {
+		for i := 0; i < len(runes); {
+			if levels[i] < level {
+				i++
+				continue
+			}
+			j := i
+			for j < len(runes) && levels[j] >= level {
+				j++
+			}
+			reverseRunes(runes[i:j])
+			reverseInts(levels[i:j])
+			i = j
+		}
+	}
+
+	out := make([]rune, 0, len(runes))
+	for _, r := range runes {
+		if r != lrm {
+			out = append(out, r)
+		}
+	}
+	return string(out)
+}
+
+// resolveBrackets applies rule N0, which the bidi package leaves out: a
+// bracket pair enclosing strong text of the paragraph direction takes that
+// direction, and one enclosing only opposite text takes the opposite one when
+// the text before it is opposite too. Neutrals next to a bracket whose level
+// changes are resolved again (N1–N2), so "مقلاة (28cm)" in an LTR paragraph
+// keeps "(28cm)" together.
+func resolveBrackets(runes []rune, levels []int, baseLevel int) {
+	var open []int
+	for i, r := range runes {
+		prop, _ := bidi.LookupRune(r)
+		switch {
+		case prop.IsOpeningBracket():
+			open = append(open, i)
+		case prop.IsBracket():
+			// BD16: pair with the nearest matching opening bracket, dropping those inside
+			for k := len(open) - 1; k >= 0; k-- {
+				if mirrorRune(runes[open[k]]) != r {
+					continue
+				}
+				if level, ok := bracketPairLevel(runes, open[k], i, baseLevel); ok {
+					for _, b := range []int{open[k], i} {
+						if levels[b] != level {
+							levels[b] = level
+							resolveNeutralsAround(runes, levels, b, baseLevel)
+						}
+					}
+				}
+				open = open[:k]
+				break
+			}
+		}
+	}
+}
+
+// bracketPairLevel returns the level N0 gives the brackets at open and
+// close, or false when they enclose no strong text (N0 d)
+func bracketPairLevel(runes []rune, open, close, baseLevel int) (int, bool) {
+	embedding := bidi.L
+	if baseLevel == 1 {
+		embedding = bidi.R
+	}
+	opposite := false
+	for k := open + 1; k < close; k++ {
+		if c, ok := strongClass(runes, k, baseLevel); ok {
+			if c == embedding {
+				return baseLevel, true
+			}
+			opposite = true
+		}
+	}
+	if !opposite {
+		return 0, false
+	}
+	// N0 c: opposite only when the text before is opposite too (sos is not)
+	for k := open - 1; k >= 0; k-- {
+		if c, ok := strongClass(runes, k, baseLevel); ok {
+			if c != embedding {
+				return baseLevel + 1, true
+			}
+			break
+		}
+	}
+	return baseLevel, true
+}
+
+// strongClass is the direction N0 sees at rs[i]: L or R, numbers counting as
+// R unless Latin text precedes them (W7), false for neutrals
+func strongClass(rs []rune, i, baseLevel int) (bidi.Class, bool) {
+	switch runeClass(rs[i]) {
+	case bidi.L:
+		return bidi.L, true
+	case bidi.R, bidi.AL, bidi.AN:
+		return bidi.R, true
+	case bidi.EN:
+		for k := i - 1; k >= 0; k-- {
+			switch runeClass(rs[k]) {
+			case bidi.L:
+				return bidi.L, true
+			case bidi.R, bidi.AL:
+				return bidi.R, true
+			}
+		}
+		if baseLevel == 0 {
+			return bidi.L, true
+		}
+		return bidi.R, true
+	}
+	return 0, false
+}
+
+// resolveNeutralsAround re-applies N1–N2 to the neutrals on either side of
+// the bracket at b: they take the direction of the text around them when both
+// sides agree, the paragraph direction otherwise
+func resolveNeutralsAround(runes []rune, levels []int, b, baseLevel int) {
+	isR := func(i int) bool {
+		if i < 0 || i >= len(runes) {
+			return baseLevel == 1 // sos / eos
+		}
+		return levels[i]%2 == 1 || (levels[i] > baseLevel && isNumberPart(runes, i))
+	}
+	resolve := func(start, end int) { // neutrals in [start, end)
+		if start >= end {
+			return
+		}
+		level := baseLevel
+		if before, after := isR(start-1), isR(end); before == after {
+			switch {
+			case before:
+				level = 1
+			case baseLevel == 1:
+				level = 2
+			}
+		}
+		for i := start; i < end; i++ {
+			levels[i] = level
+		}
+	}
+
+	start := b
+	for start > 0 && isNeutral(runes, start-1) {
+		start--
+	}
+	resolve(start, b)
+	end := b + 1
+	for end < len(runes) && isNeutral(runes, end) {
+		end++
+	}
+	resolve(b+1, end)
+}
+
+// isNeutral reports whether rs[i] is whitespace or punctuation left to the
+// neutral rules; brackets and separators inside numbers are not
+func isNeutral(rs []rune, i int) bool {
+	prop, _ := bidi.LookupRune(rs[i])
+	if prop.IsBracket() {
+		return false
+	}
+	switch prop.Class() {
+	case bidi.WS, bidi.ON, bidi.S:
+		return true
+	case bidi.ES, bidi.CS, bidi.ET:
+		return !isNumberPart(rs, i)
+	}
+	return false
+}
+
+// mirrorRune returns the mirrored glyph of a bracket, e.g. ')' for '('
+func mirrorRune(r rune) rune {
+	return []rune(bidi.ReverseString(string(r)))[0]
+}
+
+// firstStrongDirection applies rules P2–P3: the first L, R or AL character decides
+func firstStrongDirection(s string) textDirection {
+	for _, r := range s {
+		switch runeClass(r) {
+		case bidi.L:
+			return dirLTR
+		case bidi.R, bidi.AL:
+			return dirRTL
+		}
+	}
+	return dirLTR
+}
+
+func runeClass(r rune) bidi.Class {
+	prop, _ := bidi.LookupRune(r)
+	return prop.Class()
+}
+
+// isNumberPart reports whether rs[i] is a digit, or a separator between two digits
+// (as in "1,500.00"), which the algorithm treats as part of the number (W4)
+func isNumberPart(rs []rune, i int) bool {
+	isDigit := func(k int) bool {
+		if k < 0 || k >= len(rs) {
+			return false
+		}
+		c := runeClass(rs[k])
+		return c == bidi.EN || c == bidi.AN
+	}
+	switch runeClass(rs[i]) {
+	case bidi.EN, bidi.AN:
+		return true
+	case bidi.ES, bidi.CS, bidi.ET:
+		return isDigit(i-1) && isDigit(i+1)
+	}
+	return false
+}
+
+func hasRTL(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Arabic, r) || unicode.Is(unicode.Hebrew, r) {
+			return true
+		}
+	}
+	return false
+}
+
+func reverseRunes(rs []rune) {
+	for i, j := 0, len(rs)-1; i < j; i, j = i+1, j-1 {
+		rs[i], rs[j] = rs[j], rs[i]
+	}
+}
+
+func reverseInts(xs []int) {
+	for i, j := 0, len(xs)-1; i < j; i, j = i+1, j-1 {
+		xs[i], xs[j] = xs[j], xs[i]
+	}
+}
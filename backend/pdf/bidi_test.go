@@ -0,0 +1,81 @@
+package pdf
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// bidiCases are the tricky strings checked against testdata/bidi/<name>-<dir>.golden
+var bidiCases = []struct {
+	name string
+	text string
+}{
+	{"product-latin-brand", "مقلاة 28cm Tefal"},
+	{"phone-groups", "الهاتف: 0555 12 34 56"},
+	{"sku", "المرجع SKU-1234 متوفر"},
+	{"mirrored-brackets", "السعر (1,500.00 دج) [خصم 10%]"},
+	{"latin-first", "Order 12: مقلاة (28cm)"},
+	{"multiline", "العميل: سعيد\nTel 021 55 66 77"},
+}
+
+func TestVisualTextGolden(t *testing.T) {
+	directions := []struct {
+		name string
+		dir  textDirection
+	}{
+		{"rtl", dirRTL},
+		{"auto", dirAuto},
+	}
+	for _, tc := range bidiCases {
+		for _, d := range directions {
+			name := tc.name + "-" + d.name
+			t.Run(name, func(t *testing.T) {
+				got := visualText(tc.text, d.dir) + "\n"
+				checkGolden(t, filepath.Join("testdata", "bidi", name+".golden"), []byte(got))
+			})
+		}
+	}
+}
+
+func TestVisualTextLatinOnly(t *testing.T) {
+	for _, s := range []string{"SKU-1234", "Tefal 28cm (x2)", ""} {
+		if got := visualText(s, dirRTL); got != s {
+			t.Errorf("visualText(%q) = %q, want it unchanged", s, got)
+		}
+	}
+}
+
+// checkGolden compares got with the golden file at path, rewriting it
+// instead when the tests run with -update
+func checkGolden(t *testing.T, path string, got []byte) {
+	t.Helper()
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("missing golden file (run with -update): %v", err)
+	}
+	if string(got) == string(want) {
+		return
+	}
+	if len(got) < 200 && len(want) < 200 {
+		t.Errorf("output differs from %s\n got: %q\nwant: %q", path, got, want)
+		return
+	}
+	at := 0
+	for at < len(got) && at < len(want) && got[at] == want[at] {
+		at++
+	}
+	t.Errorf("output differs from %s at byte %d (got %d bytes, want %d)", path, at, len(got), len(want))
+}
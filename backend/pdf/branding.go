@@ -0,0 +1,118 @@
+package pdf
+
+import (
+	"bytes"
+	"barakaERP/backend/db"
+	"barakaERP/backend/i18n"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// drawCompanyHeader renders the company profile at the current line: logo and
+// French identity with registration numbers on the left, Arabic name, address
+// and phones on the right, then a separator rule. The cursor ends below it.
+// The layout is bilingual in every locale; only the labels follow locale.
+func drawCompanyHeader(pdf *fpdf.Fpdf, company *db.CompanySettings, locale string) {
+	left, _, right, _ := pdf.GetMargins()
+	pageW, _ := pdf.GetPageSize()
+	halfW := (pageW - left - right) / 2
+	y := pdf.GetY()
+	bottom := y
+
+	pdf.SetFont(FontAmiri, "", 10)
+
+	// Logo
+	textX := left
+	if len(company.Logo) > 0 && company.LogoType != nil {
+		opts := fpdf.ImageOptions{ImageType: *company.LogoType}
+		info := pdf.RegisterImageOptionsReader("company-logo", opts, bytes.NewReader(company.Logo))
+		if pdf.Ok() && info != nil && info.Height() > 0 {
+			h := 18.0
+			w := info.Width() * h / info.Height()
+			if w > 30 {
+				w, h = 30, info.Height()*30/info.Width()
+			}
+			pdf.ImageOptions("company-logo", left, y, w, h, false, opts, 0, "")
+			textX = left + w + 3
+			bottom = y + h
+		} else {
+			// A broken logo must not prevent the document from printing
+			pdf.ClearError()
+		}
+	}
+
+	// French name and registration numbers (LTR)
+	pdf.SetXY(textX, y)
+	if company.NameFR != nil && *company.NameFR != "" {
+		pdf.SetFont(FontAmiri, "", 11)
+		pdf.CellFormat(left+halfW-textX, 6, visualText(*company.NameFR, dirLTR), "", 2, "L", false, 0, "")
+	}
+	pdf.SetFont(FontAmiri, "", 8)
+	for _, reg := range companyRegistrations(company) {
+		pdf.CellFormat(left+halfW-textX, 4, reg, "", 2, "L", false, 0, "")
+	}
+	if pdf.GetY() > bottom {
+		bottom = pdf.GetY()
+	}
+
+	// Arabic name, address and phones (RTL)
+	pdf.SetXY(left+halfW, y)
+	pdf.SetFont(FontAmiri, "", 14)
+	pdf.CellFormat(halfW, 7, visualText(company.NameAR, dirRTL), "", 2, "R", false, 0, "")
+	pdf.SetFont(FontAmiri, "", 9)
+	if company.Address != nil && *company.Address != "" {
+		pdf.CellFormat(halfW, 5, visualText(*company.Address, dirRTL), "", 2, "R", false, 0, "")
+	}
+	if len(company.Phones) > 0 {
+		// LRM keeps the spaced digit groups of the numbers in their written order
+		phones := i18n.T(locale, "doc.phone", nil) + string(lrm) + strings.Join(company.Phones, " / ")
+		pdf.CellFormat(halfW, 5, visualText(phones, dirRTL), "", 2, "R", false, 0, "")
+	}
+	if pdf.GetY() > bottom {
+		bottom = pdf.GetY()
+	}
+
+	pdf.SetDrawColor(180, 180, 180)
+	pdf.Line(left, bottom+2, pageW-right, bottom+2)
+	pdf.SetDrawColor(0, 0, 0)
+	pdf.SetXY(left, bottom+4)
+}
+
+// drawCompanyFooter prints the company footer text and registration numbers
+// at the bottom of the page; it is called from the page footer
+func drawCompanyFooter(pdf *fpdf.Fpdf, company *db.CompanySettings) {
+	left, _, right, _ := pdf.GetMargins()
+	pageW, _ := pdf.GetPageSize()
+	w := pageW - left - right
+
+	pdf.SetY(-15)
+	pdf.SetFont(FontAmiri, "", 8)
+	pdf.SetTextColor(100, 100, 100)
+	if company.FooterText != nil && *company.FooterText != "" {
+		pdf.CellFormat(w, 4, visualText(*company.FooterText, dirAuto), "", 2, "C", false, 0, "")
+	}
+	if regs := companyRegistrations(company); len(regs) > 0 {
+		pdf.CellFormat(w, 4, strings.Join(regs, "   "), "", 2, "C", false, 0, "")
+	}
+	pdf.SetTextColor(0, 0, 0)
+}
+
+// companyRegistrations lists the legal registration numbers that are set
+func companyRegistrations(company *db.CompanySettings) []string {
+	var regs []string
+	for _, reg := range []struct {
+		label string
+		value *string
+	}{
+		{"RC", company.RC},
+		{"NIF", company.NIF},
+		{"NIS", company.NIS},
+		{"AI", company.AI},
+	} {
+		if reg.value != nil && *reg.value != "" {
+			regs = append(regs, reg.label+": "+*reg.value)
+		}
+	}
+	return regs
+}
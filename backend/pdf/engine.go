@@ -0,0 +1,711 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"barakaERP/backend/db"
+	"barakaERP/backend/i18n"
+	"strings"
+	"time"
+
+	"github.com/01walid/goarabic"
+	"github.com/go-pdf/fpdf"
+)
+
+// renderer lays out a Template on an fpdf document. Every block renders inside
+// a horizontal band [x, x+w] starting at the current Y and leaves Y below itself.
+type renderer struct {
+	pdf     *fpdf.Fpdf
+	tpl     *Template
+	rtl     bool
+	data    map[string]interface{}
+	company *db.CompanySettings
+	fonts   *FontRegistry
+	locale  string
+	// firstPage and pagesAlias number pages within this document when several
+	// documents share one PDF
+	firstPage  int
+	pagesAlias string
+}
+
+// renderTemplate renders tpl against data in locale, which sets the labels,
+// formats and (unless the template fixes it) the direction. company is used
+// by company_header blocks and the page footer; it may be nil.
+func renderTemplate(tpl *Template, fonts *FontRegistry, data map[string]interface{}, company *db.CompanySettings, locale string) ([]byte, error) {
+	return renderDocuments(tpl, fonts, []map[string]interface{}{data}, company, locale, nil)
+}
+
+// renderDocuments renders tpl once per data set into a single PDF, each
+// document starting on a new page with its own page numbering. rendered, if
+// not nil, is called after each document.
+func renderDocuments(tpl *Template, fonts *FontRegistry, docs []map[string]interface{}, company *db.CompanySettings, locale string, rendered func(i int)) ([]byte, error) {
+	orientation := tpl.Page.Orientation
+	if orientation == "" {
+		orientation = "P"
+	}
+	var pdf *fpdf.Fpdf
+	if tpl.Page.Width > 0 {
+		pdf = fpdf.NewCustom(&fpdf.InitType{
+			OrientationStr: orientation,
+			UnitStr:        "mm",
+			Size:           fpdf.SizeType{Wd: tpl.Page.Width, Ht: tpl.Page.Height},
+		})
+	} else {
+		pdf = fpdf.New(orientation, "mm", tpl.Page.Size, "")
+	}
+
+	margins := []float64{20, 20, 20, 20}
+	if len(tpl.Page.Margins) == 4 {
+		margins = tpl.Page.Margins
+	}
+	pdf.SetMargins(margins[0], margins[1], margins[2])
+	pdf.SetAutoPageBreak(true, margins[3])
+
+	// The footer of a document's last page is drawn by the next AddPage (or
+	// on close), so it must still see that document's renderer
+	var current *renderer
+	pdf.SetFooterFunc(func() {
+		if current != nil {
+			current.footer()
+		}
+	})
+
+	// Only the fonts the template uses are embedded in the document
+	rtl := tpl.Direction == "rtl" || (tpl.Direction == "" && i18n.Direction(locale) == "rtl")
+	if err := fonts.addTo(pdf, templateFonts(tpl, fonts, rtl)); err != nil {
+		return nil, err
+	}
+
+	pageW, _ := pdf.GetPageSize()
+	for i, data := range docs {
+		pdf.AddPage()
+		current = &renderer{
+			pdf:        pdf,
+			tpl:        tpl,
+			rtl:        rtl,
+			data:       data,
+			company:    company,
+			fonts:      fonts,
+			locale:     locale,
+			firstPage:  pdf.PageNo(),
+			pagesAlias: fmt.Sprintf("{nb%d}", i+1),
+		}
+		current.setFont("", "", tpl.FontSize)
+		current.blocks(tpl.Blocks, margins[0], pageW-margins[0]-margins[2], nil)
+		pdf.RegisterAlias(current.pagesAlias, fmt.Sprint(pdf.PageNo()-current.firstPage+1))
+		if rendered != nil {
+			rendered(i)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to generate PDF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// footer prints the company footer and the page number at the bottom of every page
+func (r *renderer) footer() {
+	pdf := r.pdf
+	if r.company != nil && r.tpl.CompanyFooter {
+		drawCompanyFooter(pdf, r.company)
+	}
+	if r.tpl.PageNumbers != "" {
+		left, _, right, _ := pdf.GetMargins()
+		pageW, _ := pdf.GetPageSize()
+		page := fmt.Sprint(pdf.PageNo() - r.firstPage + 1)
+		label := r.interpolate(r.tpl.PageNumbers, map[string]interface{}{"page": page, "pages": r.pagesAlias})
+		pdf.SetY(-7)
+		r.setFont("", "", 8)
+		pdf.SetTextColor(100, 100, 100)
+		pdf.CellFormat(pageW-left-right, 4, r.display(label), "", 0, "C", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	}
+}
+
+// setFont selects family (the template's default when empty) in style, or the
+// closest installed font
+func (r *renderer) setFont(family, style string, size float64) {
+	if family == "" {
+		family = r.tpl.Font
+	}
+	key := r.fonts.resolveFor(family, style, r.rtl)
+	r.pdf.SetFont(key.family, key.style, size)
+}
+
+// templateFonts lists the fonts tpl can select. Amiri regular is always
+// included: the company header and footer are drawn with it.
+func templateFonts(tpl *Template, fonts *FontRegistry, rtl bool) map[fontKey]bool {
+	keys := map[fontKey]bool{
+		{FontAmiri, StyleRegular}:                     true,
+		fonts.resolveFor(tpl.Font, StyleRegular, rtl): true,
+	}
+	var walk func(blocks []Block, family string)
+	walk = func(blocks []Block, family string) {
+		for _, b := range blocks {
+			f := b.Font
+			if f == "" {
+				f = family
+			}
+			keys[fonts.resolveFor(f, b.Style, rtl)] = true
+			walk(b.Rows, f)
+			for _, c := range b.Columns {
+				walk(c.Blocks, family)
+			}
+		}
+	}
+	walk(tpl.Blocks, tpl.Font)
+	return keys
+}
+
+func (r *renderer) blocks(blocks []Block, x, w float64, row map[string]interface{}) {
+	for _, b := range blocks {
+		if b.When != "" {
+			if v, _ := r.resolve(b.When, row); !truthy(v) {
+				continue
+			}
+		}
+		r.block(b, x, w, row)
+		r.setFont("", "", r.tpl.FontSize)
+	}
+}
+
+func (r *renderer) block(b Block, x, w float64, row map[string]interface{}) {
+	pdf := r.pdf
+	size := b.Size
+	if size <= 0 {
+		size = r.tpl.FontSize
+	}
+	r.setFont(b.Font, b.Style, size)
+	h := b.Height
+	if h <= 0 {
+		h = size * 0.5
+	}
+
+	switch b.Type {
+	case BlockCompanyHeader:
+		if r.company != nil {
+			pdf.SetX(x)
+			drawCompanyHeader(pdf, r.company, r.locale)
+		}
+
+	case BlockText:
+		rows := []map[string]interface{}{row}
+		if b.Source != "" {
+			rows = r.list(b.Source, row)
+		}
+		for _, scope := range rows {
+			for _, line := range r.wrap(r.interpolate(b.Text, scope), w) {
+				r.ensureSpace(x, h)
+				pdf.SetX(x)
+				pdf.CellFormat(w, h, line, "", 0, r.align(b.Align), false, 0, "")
+				pdf.SetXY(x, pdf.GetY()+h)
+			}
+		}
+
+	case BlockField:
+		r.ensureSpace(x, h)
+		label := r.interpolate(b.Label, row)
+		value := r.interpolate(b.Value, row)
+		pdf.SetX(x)
+		pdf.CellFormat(w, h, r.display(label+value), "", 0, r.align(b.Align), false, 0, "")
+		pdf.SetXY(x, pdf.GetY()+h)
+
+	case BlockTable:
+		r.table(b, x, w, row)
+
+	case BlockTotals:
+		valueW := b.ValueWidth
+		if valueW <= 0 {
+			valueW = 35
+		}
+		for _, line := range b.Rows {
+			if line.When != "" {
+				if v, _ := r.resolve(line.When, row); !truthy(v) {
+					continue
+				}
+			}
+			lineSize := line.Size
+			if lineSize <= 0 {
+				lineSize = size
+			}
+			font := line.Font
+			if font == "" {
+				font = b.Font
+			}
+			r.setFont(font, line.Style, lineSize)
+			lineH := line.Height
+			if lineH <= 0 {
+				lineH = h
+			}
+			r.ensureSpace(x, lineH)
+			y := pdf.GetY()
+			label := r.display(r.interpolate(line.Label, row))
+			value := r.display(r.interpolate(line.Value, row))
+			if r.rtl {
+				pdf.SetXY(x, y)
+				pdf.CellFormat(valueW, lineH, value, "1", 0, alignOr(line.Align, "L"), false, 0, "")
+				pdf.CellFormat(w-valueW, lineH, label, "", 0, "R", false, 0, "")
+			} else {
+				pdf.SetXY(x, y)
+				pdf.CellFormat(w-valueW, lineH, label, "", 0, "R", false, 0, "")
+				pdf.CellFormat(valueW, lineH, value, "1", 0, alignOr(line.Align, "R"), false, 0, "")
+			}
+			pdf.SetXY(x, y+lineH)
+		}
+
+	case BlockColumns:
+		startY := pdf.GetY()
+		bottom := startY
+		widths := scaleWidths(b.Columns, w)
+		for i, col := range b.Columns {
+			cx := r.columnX(x, w, widths, i)
+			pdf.SetXY(cx, startY)
+			r.blocks(col.Blocks, cx, widths[i], row)
+			if pdf.GetY() > bottom {
+				bottom = pdf.GetY()
+			}
+		}
+		pdf.SetXY(x, bottom)
+
+	case BlockSpacer:
+		pdf.SetXY(x, pdf.GetY()+b.Height)
+
+	case BlockQR:
+		payload := r.interpolate(b.Value, row)
+		if payload == "" {
+			break
+		}
+		qrSize := b.Height
+		if qrSize <= 0 {
+			qrSize = 25
+		}
+		r.ensureSpace(x, qrSize)
+		qx := x
+		switch r.align(b.Align) {
+		case "C":
+			qx = x + (w-qrSize)/2
+		case "R":
+			qx = x + w - qrSize
+		}
+		y := pdf.GetY()
+		drawQR(pdf, payload, qx, y, qrSize)
+		pdf.SetXY(x, y+qrSize)
+
+	case BlockLine:
+		y := pdf.GetY() + 1
+		pdf.SetDrawColor(180, 180, 180)
+		pdf.Line(x, y, x+w, y)
+		pdf.SetDrawColor(0, 0, 0)
+		pdf.SetXY(x, y+1)
+	}
+}
+
+// table draws a header row and one bordered row per element of b.Source.
+// Cell text wraps to the column width and rows grow to fit. When a row does
+// not fit on the page, the running subtotal of b.Carry is printed, a new page
+// is started, and the header and the subtotal brought forward are repeated.
+func (r *renderer) table(b Block, x, w float64, row map[string]interface{}) {
+	pdf := r.pdf
+	rowH := b.Height
+	if rowH <= 0 {
+		rowH = 7
+	}
+	size, _ := pdf.GetFontSize()
+	lineH := size * 0.45
+	widths := scaleWidths(b.Columns, w)
+
+	header := func() {
+		y := pdf.GetY()
+		pdf.SetFillColor(240, 240, 240)
+		for i, col := range b.Columns {
+			pdf.SetXY(r.columnX(x, w, widths, i), y)
+			pdf.CellFormat(widths[i], rowH+1, r.display(r.interpolate(col.Title, row)), "1", 0, r.align(col.Align), true, 0, "")
+		}
+		pdf.SetFillColor(255, 255, 255)
+		pdf.SetXY(x, y+rowH+1)
+	}
+	var carried int64
+	carryRow := func() {
+		if b.CarryLabel == "" {
+			return
+		}
+		last := len(b.Columns) - 1
+		labelX := x
+		// The label sits against the subtotal it describes
+		labelAlign := "R"
+		if r.rtl {
+			labelX, labelAlign = x+widths[last], "L"
+		}
+		y := pdf.GetY()
+		pdf.SetXY(labelX, y)
+		pdf.CellFormat(w-widths[last], rowH, r.display(r.interpolate(b.CarryLabel, row)), "1", 0, labelAlign, false, 0, "")
+		pdf.SetXY(r.columnX(x, w, widths, last), y)
+		value := ""
+		if b.Carry != "" {
+			value = r.display(r.format(carried, "money", row))
+		}
+		pdf.CellFormat(widths[last], rowH, value, "1", 0, r.align(b.Columns[last].Align), false, 0, "")
+		pdf.SetXY(x, y+rowH)
+	}
+
+	r.ensureSpace(x, 2*rowH+1)
+	header()
+
+	for _, scope := range r.list(b.Source, row) {
+		cells := make([][]string, len(b.Columns))
+		height := rowH
+		for i, col := range b.Columns {
+			cells[i] = r.wrap(r.interpolate(col.Value, scope), widths[i])
+			if h := float64(len(cells[i]))*lineH + rowH - lineH; h > height {
+				height = h
+			}
+		}
+
+		// Keep room below the row for the carried-forward line
+		reserve := 0.0
+		if b.CarryLabel != "" {
+			reserve = rowH
+		}
+		if !r.fits(height + reserve) {
+			carryRow()
+			pdf.AddPage()
+			pdf.SetX(x)
+			header()
+			carryRow()
+		}
+
+		y := pdf.GetY()
+		for i, col := range b.Columns {
+			cx := r.columnX(x, w, widths, i)
+			pdf.Rect(cx, y, widths[i], height, "D")
+			top := y + (height-float64(len(cells[i]))*lineH)/2
+			for k, line := range cells[i] {
+				pdf.SetXY(cx, top+float64(k)*lineH)
+				pdf.CellFormat(widths[i], lineH, line, "", 0, r.align(col.Align), false, 0, "")
+			}
+		}
+		pdf.SetXY(x, y+height)
+		if b.Carry != "" {
+			v, _ := r.resolve(b.Carry, scope)
+			carried += toInt64(v)
+		}
+	}
+}
+
+// wrap splits logical text into lines that fit width w (less the cell
+// padding) and returns them in visual order. Widths are measured on the
+// shaped text, since Arabic presentation forms differ from the base letters.
+func (r *renderer) wrap(s string, w float64) []string {
+	w -= 2 * r.pdf.GetCellMargin()
+	var lines []string
+	for _, para := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if line == "" || r.textWidth(candidate) <= w {
+				line = candidate
+				continue
+			}
+			lines = append(lines, line)
+			line = word
+		}
+		// A single word wider than the cell is broken between characters
+		for line != "" && r.textWidth(line) > w {
+			runes := []rune(line)
+			n := len(runes) - 1
+			for n > 1 && r.textWidth(string(runes[:n])) > w {
+				n--
+			}
+			lines = append(lines, string(runes[:n]))
+			line = string(runes[n:])
+		}
+		lines = append(lines, line)
+	}
+	for i, line := range lines {
+		lines[i] = r.display(line)
+	}
+	return lines
+}
+
+func (r *renderer) textWidth(s string) float64 {
+	return r.pdf.GetStringWidth(goarabic.ToGlyph(s))
+}
+
+// ensureSpace starts a new page when h would not fit above the bottom margin.
+// Multi-cell rows need this up front; fpdf's own break would split them.
+func (r *renderer) ensureSpace(x, h float64) {
+	if !r.fits(h) {
+		r.pdf.AddPage()
+		r.pdf.SetX(x)
+	}
+}
+
+// fits reports whether h fits between the current Y and the bottom margin
+func (r *renderer) fits(h float64) bool {
+	_, pageH := r.pdf.GetPageSize()
+	_, bottom := r.pdf.GetAutoPageBreak()
+	return r.pdf.GetY()+h <= pageH-bottom
+}
+
+// columnX returns the left edge of column i; in RTL documents the first
+// column is the rightmost one
+func (r *renderer) columnX(x, w float64, widths []float64, i int) float64 {
+	offset := 0.0
+	for j := 0; j < i; j++ {
+		offset += widths[j]
+	}
+	if r.rtl {
+		return x + w - offset - widths[i]
+	}
+	return x + offset
+}
+
+func scaleWidths(cols []Column, w float64) []float64 {
+	total := 0.0
+	for _, c := range cols {
+		total += c.Width
+	}
+	widths := make([]float64, len(cols))
+	for i, c := range cols {
+		widths[i] = c.Width / total * w
+	}
+	return widths
+}
+
+func (r *renderer) align(a string) string {
+	if r.rtl {
+		return alignOr(a, "R")
+	}
+	return alignOr(a, "L")
+}
+
+func alignOr(a, def string) string {
+	if a == "" {
+		return def
+	}
+	return a
+}
+
+// display converts a whole logical line to visual order in the template's
+// reading direction, so Arabic values in LTR documents (and Latin ones in RTL
+// documents) are placed by the bidi algorithm rather than word by word
+func (r *renderer) display(s string) string {
+	if r.rtl {
+		return visualText(s, dirRTL)
+	}
+	return visualText(s, dirLTR)
+}
+
+// interpolate replaces {path} and {path|format} placeholders with bound
+// values and {t:key} with the catalog message for key, itself interpolated
+// so labels can contain bindings
+func (r *renderer) interpolate(s string, row map[string]interface{}) string {
+	var out strings.Builder
+	for {
+		start := strings.IndexByte(s, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			break
+		}
+		out.WriteString(s[:start])
+
+		expr := s[start+1 : start+end]
+		path, format := expr, ""
+		if i := strings.IndexByte(expr, '|'); i >= 0 {
+			path, format = expr[:i], expr[i+1:]
+		}
+		if key, ok := strings.CutPrefix(path, "t:"); ok {
+			out.WriteString(r.interpolate(i18n.T(r.locale, key, nil), row))
+		} else {
+			v, _ := r.resolve(path, row)
+			out.WriteString(r.format(v, format, row))
+		}
+
+		s = s[start+end+1:]
+	}
+	out.WriteString(s)
+	return out.String()
+}
+
+// resolve looks a dotted path up in the current row first, then in the
+// document data. A "t:key" path resolves to the catalog message, so a block
+// can be shown only in the locales that define text for it.
+func (r *renderer) resolve(path string, row map[string]interface{}) (interface{}, bool) {
+	if key, ok := strings.CutPrefix(path, "t:"); ok {
+		return i18n.T(r.locale, key, nil), i18n.Has(r.locale, key)
+	}
+	if row != nil {
+		if v, ok := lookup(row, path); ok {
+			return v, true
+		}
+	}
+	return lookup(r.data, path)
+}
+
+func lookup(m map[string]interface{}, path string) (interface{}, bool) {
+	var cur interface{} = m
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = obj[key]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// list resolves a binding to the rows of a table or repeated text block
+func (r *renderer) list(path string, row map[string]interface{}) []map[string]interface{} {
+	v, _ := r.resolve(path, row)
+	items, _ := v.([]interface{})
+	rows := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		if m, ok := item.(map[string]interface{}); ok {
+			rows = append(rows, m)
+		} else {
+			// Scalar lists (e.g. phones) bind as {value}
+			rows = append(rows, map[string]interface{}{"value": item})
+		}
+	}
+	return rows
+}
+
+func (r *renderer) format(v interface{}, format string, row map[string]interface{}) string {
+	if v == nil {
+		return ""
+	}
+	switch format {
+	case "money":
+		currency, _ := r.resolve("currency", row)
+		cur, _ := currency.(string)
+		return i18n.FormatMoney(r.locale, toInt64(v), cur)
+	case "date", "datetime":
+		s, _ := v.(string)
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return s
+		}
+		if format == "date" {
+			return i18n.FormatDate(r.locale, t)
+		}
+		return i18n.FormatDateTime(r.locale, t)
+	case "words", "words_ar", "words_fr", "words_en":
+		currency, _ := r.resolve("currency", row)
+		cur, _ := currency.(string)
+		lang := strings.TrimPrefix(format, "words_")
+		if format == "words" {
+			lang = r.locale
+		}
+		return amountToWords(toInt64(v), cur, lang)
+	case "qty":
+		qty, err := db.ParseQuantity(fmt.Sprint(v))
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		unit, _ := r.resolve("unit", row)
+		code, _ := unit.(string)
+		return quantityLabel(r.locale, qty, code)
+	case "percent":
+		return fmt.Sprintf("%d%%", toInt64(v))
+	case "int":
+		return fmt.Sprintf("%d", toInt64(v))
+	}
+	switch val := v.(type) {
+	case string:
+		return val
+	case json.Number:
+		return val.String()
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// quantityLabel formats a quantity with its unit (e.g. "1.5 kg"); pieces, the
+// default unit, are shown as a bare number
+func quantityLabel(locale string, qty db.Quantity, unit string) string {
+	label := i18n.FormatQuantity(locale, int64(qty))
+	if unit == "" || unit == db.DefaultUnit {
+		return label
+	}
+	return label + " " + i18n.UnitLabel(locale, unit)
+}
+
+func toInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case json.Number:
+		if i, err := n.Int64(); err == nil {
+			return i
+		}
+		f, _ := n.Float64()
+		return int64(f)
+	case float64:
+		return int64(n)
+	case int64:
+		return n
+	case int:
+		return int64(n)
+	}
+	return 0
+}
+
+func truthy(v interface{}) bool {
+	switch val := v.(type) {
+	case nil:
+		return false
+	case string:
+		return val != ""
+	case bool:
+		return val
+	case json.Number:
+		f, err := val.Float64()
+		return err != nil || f != 0
+	case []interface{}:
+		return len(val) > 0
+	case map[string]interface{}:
+		return len(val) > 0
+	}
+	return true
+}
+
+// documentData converts a document value (OrderDetail, InvoiceDetail) into the
+// generic map templates bind against, using its JSON field names. extra keys
+// are merged at the top level for computed values.
+func documentData(v interface{}, extra map[string]interface{}) (map[string]interface{}, error) {
+	data, err := jsonMap(v)
+	if err != nil {
+		return nil, err
+	}
+	computed, err := jsonMap(extra)
+	if err != nil {
+		return nil, err
+	}
+	for k, val := range computed {
+		data[k] = val
+	}
+	return data, nil
+}
+
+func jsonMap(v interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode document data: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber() // keep cents exact
+	var data map[string]interface{}
+	if err := dec.Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to decode document data: %w", err)
+	}
+	return data, nil
+}
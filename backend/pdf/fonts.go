@@ -0,0 +1,198 @@
+package pdf
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-pdf/fpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gobolditalic"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+)
+
+//go:embed embedded/fonts/frontendsrcassetsfontsAmiri-Regular.ttf
+var amiriFont []byte
+
+// Built-in font families
+const (
+	FontAmiri = "Amiri" // Arabic and Latin; the default
+	FontGo    = "Go"    // Latin only, with bold and italic
+)
+
+// Font styles as used by fpdf: regular, bold, italic and bold italic
+const (
+	StyleRegular    = ""
+	StyleBold       = "B"
+	StyleItalic     = "I"
+	StyleBoldItalic = "BI"
+)
+
+// fontFileStyles maps the style suffix of font file names to font styles
+var fontFileStyles = map[string]string{
+	"regular":    StyleRegular,
+	"bold":       StyleBold,
+	"italic":     StyleItalic,
+	"bolditalic": StyleBoldItalic,
+}
+
+type fontKey struct {
+	family, style string
+}
+
+// FontRegistry holds the TrueType fonts documents can use. Font files are read
+// once and kept in memory; each document only adds the fonts its template uses.
+// Safe for concurrent use.
+type FontRegistry struct {
+	mu     sync.RWMutex
+	fonts  map[fontKey][]byte
+	arabic map[fontKey]bool // fonts with Arabic letters
+}
+
+// NewFontRegistry creates a registry with the built-in fonts: Amiri regular
+// and the Go family in all four styles
+func NewFontRegistry() *FontRegistry {
+	r := &FontRegistry{fonts: map[fontKey][]byte{}, arabic: map[fontKey]bool{}}
+	r.fonts[fontKey{FontAmiri, StyleRegular}] = amiriFont
+	r.arabic[fontKey{FontAmiri, StyleRegular}] = true
+	r.fonts[fontKey{FontGo, StyleRegular}] = goregular.TTF
+	r.fonts[fontKey{FontGo, StyleBold}] = gobold.TTF
+	r.fonts[fontKey{FontGo, StyleItalic}] = goitalic.TTF
+	r.fonts[fontKey{FontGo, StyleBoldItalic}] = gobolditalic.TTF
+	return r
+}
+
+// Register adds (or replaces) a font. ttf must be a TrueType font.
+func (r *FontRegistry) Register(family, style string, ttf []byte) error {
+	family = strings.TrimSpace(family)
+	if family == "" {
+		return fmt.Errorf("font family is required")
+	}
+	style = normalizeStyle(style)
+	f, err := sfnt.Parse(ttf)
+	if err != nil {
+		return fmt.Errorf("invalid font %s %s: %w", family, style, err)
+	}
+	// Alef stands for the Arabic block: fonts without it are Latin-only
+	var buf sfnt.Buffer
+	alef, err := f.GlyphIndex(&buf, 'ا')
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fonts[fontKey{family, style}] = ttf
+	r.arabic[fontKey{family, style}] = err == nil && alef != 0
+	return nil
+}
+
+// LoadDir registers every <Family>-<Style>.ttf in dir, where Style is Regular,
+// Bold, Italic or BoldItalic (e.g. Amiri-Bold.ttf). A missing directory is not
+// an error; invalid files are skipped and reported together.
+func (r *FontRegistry) LoadDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.ttf"))
+	if err != nil {
+		return fmt.Errorf("failed to list fonts: %w", err)
+	}
+	var failed []string
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		family, style := name, StyleRegular
+		if i := strings.LastIndexByte(name, '-'); i > 0 {
+			if s, ok := fontFileStyles[strings.ToLower(name[i+1:])]; ok {
+				family, style = name[:i], s
+			}
+		}
+		data, err := os.ReadFile(path)
+		if err == nil {
+			err = r.Register(family, style, data)
+		}
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", filepath.Base(path), err))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to load fonts: %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// Families lists the registered font families
+func (r *FontRegistry) Families() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	seen := map[string]bool{}
+	var families []string
+	for key := range r.fonts {
+		if !seen[key.family] {
+			seen[key.family] = true
+			families = append(families, key.family)
+		}
+	}
+	sort.Strings(families)
+	return families
+}
+
+// resolve picks the registered font closest to the requested one: the style
+// falls back to regular (Amiri has no bold unless one is installed) and an
+// unknown family to Amiri
+func (r *FontRegistry) resolve(family, style string) fontKey {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if family == "" {
+		family = FontAmiri
+	}
+	style = normalizeStyle(style)
+	for _, key := range []fontKey{{family, style}, {family, StyleRegular}, {FontAmiri, style}} {
+		if _, ok := r.fonts[key]; ok {
+			return key
+		}
+	}
+	return fontKey{FontAmiri, StyleRegular}
+}
+
+// resolveFor is resolve for a document in the given direction: right-to-left
+// documents are Arabic, so a Latin-only font gives way to Amiri in that style
+func (r *FontRegistry) resolveFor(family, style string, rtl bool) fontKey {
+	key := r.resolve(family, style)
+	if !rtl {
+		return key
+	}
+	r.mu.RLock()
+	arabic := r.arabic[key]
+	r.mu.RUnlock()
+	if arabic {
+		return key
+	}
+	return r.resolve(FontAmiri, style)
+}
+
+// addTo registers the given fonts with a document
+func (r *FontRegistry) addTo(pdf *fpdf.Fpdf, keys map[fontKey]bool) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for key := range keys {
+		pdf.AddUTF8FontFromBytes(key.family, key.style, r.fonts[key])
+	}
+	if err := pdf.Error(); err != nil {
+		return fmt.Errorf("failed to load fonts: %w", err)
+	}
+	return nil
+}
+
+func normalizeStyle(style string) string {
+	style = strings.ToUpper(style)
+	bold, italic := strings.Contains(style, "B"), strings.Contains(style, "I")
+	switch {
+	case bold && italic:
+		return StyleBoldItalic
+	case bold:
+		return StyleBold
+	case italic:
+		return StyleItalic
+	}
+	return StyleRegular
+}
@@ -0,0 +1,287 @@
+package pdf
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"barakaERP/backend/db"
+	"barakaERP/backend/escpos"
+	"barakaERP/backend/i18n"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Receipt formats accepted by GenerateReceipt
+const (
+	ReceiptPDF80        = "pdf-80"
+	ReceiptPDF58        = "pdf-58"
+	ReceiptESCPOS80     = "escpos-80" // raster text, works on any ESC/POS printer
+	ReceiptESCPOS58     = "escpos-58"
+	ReceiptESCPOS80Text = "escpos-80-cp1256" // Windows-1256 text for printers with Arabic firmware
+	ReceiptESCPOS58Text = "escpos-58-cp1256"
+)
+
+// DefaultArabicCodePage is the ESC t table commonly assigned to Windows-1256
+// (WPC1256) on Epson-compatible printers
+const DefaultArabicCodePage = 50
+
+// ReceiptOptions controls ESC/POS receipt output
+type ReceiptOptions struct {
+	PaperWidth int // 58 or 80 (mm)
+	// Bitmap prints every line as a raster image drawn with the Arabic font, so
+	// shaping does not depend on the printer. Otherwise text is sent in logical
+	// order encoded as Windows-1256 and the printer firmware must shape it.
+	Bitmap    bool
+	CodePage  byte      // ESC t table selected in text mode
+	PrintedAt time.Time // printed on the receipt; zero means now
+	Locale    string    // labels, formats and direction; the default locale when empty
+}
+
+// GenerateReceipt renders the cash-register receipt of an order in one of the
+// Receipt* formats: a narrow PDF or a raw ESC/POS stream
+func (g *OrderPDFGenerator) GenerateReceipt(orderDetail db.OrderDetail, company db.CompanySettings, format, locale string) ([]byte, error) {
+	switch format {
+	case ReceiptPDF80:
+		return g.GenerateReceiptPDF(orderDetail, company, 80, locale)
+	case ReceiptPDF58:
+		return g.GenerateReceiptPDF(orderDetail, company, 58, locale)
+	case ReceiptESCPOS80, ReceiptESCPOS58, ReceiptESCPOS80Text, ReceiptESCPOS58Text:
+		opts := ReceiptOptions{
+			PaperWidth: 80,
+			Bitmap:     !strings.HasSuffix(format, "-cp1256"),
+			CodePage:   DefaultArabicCodePage,
+			Locale:     locale,
+		}
+		if strings.HasPrefix(format, "escpos-58") {
+			opts.PaperWidth = 58
+		}
+		return GenerateReceiptESCPOS(orderDetail, company, opts)
+	}
+	return nil, fmt.Errorf("unknown receipt format %q", format)
+}
+
+// receiptLine is one line of an ESC/POS receipt. When value is set, text is a
+// label at the start of the line (the right, in RTL locales) and value sits at the end.
+type receiptLine struct {
+	text   string
+	value  string
+	center bool
+	large  bool
+	rule   bool
+}
+
+// GenerateReceiptESCPOS renders the receipt of an order as an ESC/POS byte
+// stream. Output only depends on its inputs, so it can be compared byte for byte.
+func GenerateReceiptESCPOS(orderDetail db.OrderDetail, company db.CompanySettings, opts ReceiptOptions) ([]byte, error) {
+	if opts.PaperWidth != 58 && opts.PaperWidth != 80 {
+		return nil, fmt.Errorf("unsupported paper width %dmm", opts.PaperWidth)
+	}
+	if opts.PrintedAt.IsZero() {
+		opts.PrintedAt = time.Now()
+	}
+	lines := receiptLines(orderDetail, company, opts.PrintedAt, opts.Locale)
+
+	b := escpos.NewBuilder()
+	if opts.Bitmap {
+		img, err := rasterReceipt(lines, opts.PaperWidth, i18n.Direction(opts.Locale) == "rtl")
+		if err != nil {
+			return nil, err
+		}
+		b.Raster(img)
+	} else {
+		writeTextReceipt(b, lines, opts)
+	}
+	return b.Feed(4).Cut().Bytes(), nil
+}
+
+// receiptLines lays out the same content as the receipt PDF template
+func receiptLines(orderDetail db.OrderDetail, company db.CompanySettings, printedAt time.Time, locale string) []receiptLine {
+	label := func(key string) string {
+		return strings.TrimSpace(i18n.T(locale, key, nil))
+	}
+
+	lines := []receiptLine{{text: company.NameAR, center: true, large: true}}
+	if company.Address != nil && *company.Address != "" {
+		lines = append(lines, receiptLine{text: *company.Address, center: true})
+	}
+	for _, phone := range company.Phones {
+		lines = append(lines, receiptLine{text: phone, center: true})
+	}
+	lines = append(lines,
+		receiptLine{rule: true},
+		receiptLine{text: label("doc.order_number"), value: orderDetail.Order.OrderNumber},
+		receiptLine{text: label("doc.date"), value: i18n.FormatDateTime(locale, orderDetail.Order.IssueDate)},
+		receiptLine{text: i18n.T(locale, "doc.client", nil) + orderDetail.Client.Name},
+		receiptLine{rule: true},
+	)
+
+	currency := ""
+	for _, item := range orderDetail.Items {
+		currency = item.Currency
+		discountAmount := (item.TotalCents * int64(item.DiscountPercent)) / 100
+		qty := quantityLabel(locale, item.Qty, item.Unit) + " × " + i18n.FormatMoney(locale, item.UnitPriceCents, item.Currency)
+		if item.DiscountPercent > 0 {
+			qty += fmt.Sprintf(" -%d%%", item.DiscountPercent)
+		}
+		lines = append(lines,
+			receiptLine{text: item.NameSnapshot},
+			receiptLine{text: qty, value: i18n.FormatMoney(locale, item.TotalCents-discountAmount, item.Currency)},
+		)
+	}
+
+	_, discount, _, total := db.CalcOrderTotals(orderDetail.Items, 0, 0)
+	lines = append(lines, receiptLine{rule: true})
+	if discount > 0 {
+		lines = append(lines, receiptLine{text: label("doc.discount_total"), value: "-" + i18n.FormatMoney(locale, discount, currency)})
+	}
+	lines = append(lines,
+		receiptLine{text: label("doc.total"), value: i18n.FormatMoney(locale, total, currency), large: true},
+		receiptLine{rule: true},
+	)
+	if company.FooterText != nil && *company.FooterText != "" {
+		lines = append(lines, receiptLine{text: *company.FooterText, center: true})
+	}
+	return append(lines, receiptLine{text: i18n.FormatDateTime(locale, printedAt), center: true})
+}
+
+// writeTextReceipt sends the lines as Windows-1256 text, padded to the
+// printer's characters per line (Font A: 32 on 58mm paper, 48 on 80mm)
+func writeTextReceipt(b *escpos.Builder, lines []receiptLine, opts ReceiptOptions) {
+	cols := 48
+	if opts.PaperWidth == 58 {
+		cols = 32
+	}
+	enc := encoding.ReplaceUnsupported(charmap.Windows1256.NewEncoder())
+	encode := func(s string) []byte {
+		out, err := enc.Bytes([]byte(s))
+		if err != nil {
+			return []byte(s)
+		}
+		return out
+	}
+
+	// Lines start on the right in RTL locales
+	start := escpos.AlignLeft
+	if i18n.Direction(opts.Locale) == "rtl" {
+		start = escpos.AlignRight
+	}
+
+	b.CodePage(opts.CodePage)
+	for _, line := range lines {
+		width := cols
+		if line.large {
+			b.Size(2, 2).Bold(true)
+			width = cols / 2
+		}
+		switch {
+		case line.rule:
+			b.Align(escpos.AlignLeft).Line([]byte(strings.Repeat("-", cols)))
+		case line.center:
+			b.Align(escpos.AlignCenter).Line(encode(line.text))
+		case line.value != "":
+			gap := width - len([]rune(line.text)) - len([]rune(line.value))
+			if gap < 1 {
+				gap = 1
+			}
+			b.Align(start).Line(encode(line.text + strings.Repeat(" ", gap) + line.value))
+		default:
+			b.Align(start).Line(encode(line.text))
+		}
+		if line.large {
+			b.Size(1, 1).Bold(false)
+		}
+	}
+	b.Align(escpos.AlignLeft)
+}
+
+// Raster layout in printer dots (203 dpi): 384 dots print on 58mm paper, 576 on 80mm
+const (
+	rasterFontSize  = 24
+	rasterLargeSize = 34
+	rasterPadding   = 4
+)
+
+var (
+	receiptFontOnce sync.Once
+	receiptFont     *opentype.Font
+	receiptFontErr  error
+)
+
+// rasterReceipt draws the lines with the Amiri font into one 1-bit friendly
+// image as wide as the printable area, starting on the right when rtl
+func rasterReceipt(lines []receiptLine, paperWidth int, rtl bool) (image.Image, error) {
+	receiptFontOnce.Do(func() {
+		receiptFont, receiptFontErr = opentype.Parse(amiriFont)
+	})
+	if receiptFontErr != nil {
+		return nil, fmt.Errorf("failed to load receipt font: %w", receiptFontErr)
+	}
+	faces := map[bool]font.Face{}
+	for large, size := range map[bool]float64{false: rasterFontSize, true: rasterLargeSize} {
+		face, err := opentype.NewFace(receiptFont, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load receipt font: %w", err)
+		}
+		defer face.Close()
+		faces[large] = face
+	}
+
+	width := 576
+	if paperWidth == 58 {
+		width = 384
+	}
+	height := 0
+	for _, line := range lines {
+		height += faces[line.large].Metrics().Height.Ceil()
+	}
+
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	y := 0
+	for _, line := range lines {
+		face := faces[line.large]
+		lineH := face.Metrics().Height.Ceil()
+		d := &font.Drawer{Dst: img, Src: image.Black, Face: face}
+		baseline := fixed.I(y) + face.Metrics().Ascent
+		put := func(s string, x fixed.Int26_6) {
+			d.Dot = fixed.Point26_6{X: x, Y: baseline}
+			d.DrawString(s)
+		}
+		textW := func(s string) fixed.Int26_6 {
+			return d.MeasureString(s)
+		}
+
+		switch {
+		case line.rule:
+			mid := y + lineH/2
+			for x := rasterPadding; x < width-rasterPadding; x++ {
+				img.Pix[mid*img.Stride+x] = 0
+				img.Pix[(mid+1)*img.Stride+x] = 0
+			}
+		case line.center:
+			s := visualText(line.text, dirAuto)
+			put(s, (fixed.I(width)-textW(s))/2)
+		case rtl:
+			s := visualText(line.text, dirRTL)
+			put(s, fixed.I(width-rasterPadding)-textW(s))
+			if line.value != "" {
+				put(visualText(line.value, dirLTR), fixed.I(rasterPadding))
+			}
+		default:
+			put(visualText(line.text, dirLTR), fixed.I(rasterPadding))
+			if line.value != "" {
+				s := visualText(line.value, dirLTR)
+				put(s, fixed.I(width-rasterPadding)-textW(s))
+			}
+		}
+		y += lineH
+	}
+	return img, nil
+}
@@ -0,0 +1,64 @@
+package pdf
+
+import (
+	"barakaERP/backend/db"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// receiptFixture is an order with a discounted line, an Arabic client and a
+// Latin product name, issued and printed at fixed times
+func receiptFixture() (db.OrderDetail, db.CompanySettings, time.Time) {
+	address := "شارع الحرية، الجزائر"
+	footer := "شكرا لزيارتكم"
+	sku := "SKU-1234"
+	order := db.OrderDetail{
+		Order: db.Order{
+			ID:          12,
+			OrderNumber: "ORD-000012",
+			Status:      db.OrderStatusPending,
+			IssueDate:   time.Date(2025, 3, 14, 9, 30, 0, 0, time.UTC),
+		},
+		Client: db.Client{ID: 3, Name: "سعيد بن علي"},
+		Items: []db.OrderItem{
+			{NameSnapshot: "مقلاة 28cm Tefal", SKUSnapshot: &sku, Qty: db.Units(2), Unit: "piece", UnitPriceCents: 350000, Currency: "DZD", TotalCents: 700000, DiscountPercent: 10},
+			{NameSnapshot: "سكر", Qty: db.Quantity(1500), Unit: "kg", UnitPriceCents: 12000, Currency: "DZD", TotalCents: 18000},
+		},
+	}
+	company := db.CompanySettings{
+		NameAR:     "متجر البركة",
+		Address:    &address,
+		Phones:     []string{"0555 12 34 56"},
+		FooterText: &footer,
+	}
+	return order, company, time.Date(2025, 3, 14, 10, 5, 0, 0, time.UTC)
+}
+
+func TestGenerateReceiptESCPOSGolden(t *testing.T) {
+	order, company, printedAt := receiptFixture()
+	tests := []struct {
+		format string
+		opts   ReceiptOptions
+	}{
+		{ReceiptESCPOS58Text, ReceiptOptions{PaperWidth: 58, CodePage: DefaultArabicCodePage, Locale: "ar"}},
+		{ReceiptESCPOS80, ReceiptOptions{PaperWidth: 80, Bitmap: true, Locale: "ar"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			tt.opts.PrintedAt = printedAt
+			got, err := GenerateReceiptESCPOS(order, company, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, filepath.Join("testdata", "receipt", tt.format+".golden"), got)
+		})
+	}
+}
+
+func TestGenerateReceiptESCPOSPaperWidth(t *testing.T) {
+	order, company, printedAt := receiptFixture()
+	if _, err := GenerateReceiptESCPOS(order, company, ReceiptOptions{PaperWidth: 76, PrintedAt: printedAt}); err == nil {
+		t.Error("want an error for 76mm paper")
+	}
+}
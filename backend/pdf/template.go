@@ -0,0 +1,191 @@
+package pdf
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+//go:embed templates/*.json
+var defaultTemplates embed.FS
+
+// Default template names
+const (
+	TemplateOrder     = "order"
+	TemplateInvoice   = "invoice"
+	TemplateReceipt   = "receipt" // 80mm thermal paper
+	TemplateReceipt58 = "receipt58"
+)
+
+// Template describes a document layout declaratively. Text, labels and values
+// are interpolation strings: "{client.name}" or "{order.issue_date|date}" bind to
+// the document data (the JSON form of OrderDetail/InvoiceDetail plus computed
+// fields); formats are money, date, datetime, percent, int, qty (a decimal
+// quantity followed by the row's unit) and words (an amount in cents spelled
+// out in the document locale) or words_ar/words_fr/words_en.
+// "{t:doc.total}" inserts a message of the i18n catalog in the document locale;
+// messages may bind to the data themselves, and "t:key" works as a When binding
+// so a block can be left out in locales where the message is empty.
+type Template struct {
+	Name      string   `json:"name"`
+	Page      PageSpec `json:"page"`
+	Direction string   `json:"direction"` // rtl or ltr; follows the locale when empty
+	FontSize  float64  `json:"font_size"`
+	// Font is the default font family (see FontRegistry); Amiri when empty.
+	// Latin-only families cannot show Arabic text.
+	Font   string  `json:"font,omitempty"`
+	Blocks []Block `json:"blocks"`
+	// CompanyFooter prints the company footer text and registration numbers on every page
+	CompanyFooter bool `json:"company_footer"`
+	// PageNumbers is printed at the bottom of every page with {page} and
+	// {pages} replaced, e.g. "{t:doc.page_of}"; empty disables it
+	PageNumbers string `json:"page_numbers,omitempty"`
+}
+
+// PageSpec sets paper size and margins in millimeters
+type PageSpec struct {
+	Size        string    `json:"size"`        // A4, A5, Letter... ignored when Width is set
+	Orientation string    `json:"orientation"` // P or L
+	Width       float64   `json:"width"`       // custom page width, e.g. 80 for receipts
+	Height      float64   `json:"height"`
+	Margins     []float64 `json:"margins"` // left, top, right, bottom
+}
+
+// Block types
+const (
+	BlockCompanyHeader = "company_header" // logo, names, address, phones, registration numbers
+	BlockText          = "text"           // wrapped text; repeated per row when Source is set
+	BlockField         = "field"          // label followed by a value in reading direction
+	BlockTable         = "table"          // bordered table over a list (Source), split across pages
+	BlockTotals        = "totals"         // label/value rows with the value boxed at the line end
+	BlockColumns       = "columns"        // side-by-side stacks of blocks
+	BlockSpacer        = "spacer"         // vertical gap of Height
+	BlockLine          = "line"           // horizontal rule
+	BlockQR            = "qr"             // QR code of Value, Height mm square
+)
+
+// Block is one element of a template. Only the fields relevant to its type are used.
+type Block struct {
+	Type       string   `json:"type"`
+	When       string   `json:"when,omitempty"`   // render only if this binding is non-empty
+	Source     string   `json:"source,omitempty"` // list binding for table and repeated text
+	Text       string   `json:"text,omitempty"`
+	Label      string   `json:"label,omitempty"`
+	Value      string   `json:"value,omitempty"`
+	Size       float64  `json:"size,omitempty"`   // font size in points
+	Font       string   `json:"font,omitempty"`   // font family; the template's when empty
+	Style      string   `json:"style,omitempty"`  // B, I or BI; falls back to regular if not installed
+	Height     float64  `json:"height,omitempty"` // line height (or gap for spacer) in mm
+	Align      string   `json:"align,omitempty"`  // L, C or R; defaults to the reading direction
+	Columns    []Column `json:"columns,omitempty"`
+	Rows       []Block  `json:"rows,omitempty"`        // totals rows (label/value/when/size)
+	ValueWidth float64  `json:"value_width,omitempty"` // totals value box width in mm
+	// Carry is a row amount binding (e.g. "net_cents") summed into the subtotal
+	// carried forward when a table continues on the next page; CarryLabel
+	// titles that row, which is printed at the page break and repeated below
+	// the header of the next page
+	Carry      string `json:"carry,omitempty"`
+	CarryLabel string `json:"carry_label,omitempty"`
+}
+
+// Column is a table column or one stack of a columns block. Widths are
+// relative weights scaled to the available width, so layouts follow the paper size.
+type Column struct {
+	Width  float64 `json:"width"`
+	Title  string  `json:"title,omitempty"`
+	Value  string  `json:"value,omitempty"`
+	Align  string  `json:"align,omitempty"`
+	Blocks []Block `json:"blocks,omitempty"`
+}
+
+// ParseTemplate decodes and validates a JSON template
+func ParseTemplate(data []byte) (*Template, error) {
+	var tpl Template
+	if err := json.Unmarshal(data, &tpl); err != nil {
+		return nil, fmt.Errorf("invalid template JSON: %w", err)
+	}
+	if tpl.Direction != "" && tpl.Direction != "rtl" && tpl.Direction != "ltr" {
+		return nil, fmt.Errorf("invalid template direction %q", tpl.Direction)
+	}
+	if tpl.FontSize <= 0 {
+		tpl.FontSize = 10
+	}
+	if tpl.Page.Width == 0 && tpl.Page.Size == "" {
+		tpl.Page.Size = "A4"
+	}
+	if (tpl.Page.Width > 0) != (tpl.Page.Height > 0) {
+		return nil, fmt.Errorf("custom page size needs both width and height")
+	}
+	if len(tpl.Page.Margins) != 0 && len(tpl.Page.Margins) != 4 {
+		return nil, fmt.Errorf("page margins must be [left, top, right, bottom]")
+	}
+	if err := validateBlocks(tpl.Blocks); err != nil {
+		return nil, err
+	}
+	return &tpl, nil
+}
+
+func validateBlocks(blocks []Block) error {
+	for i, b := range blocks {
+		switch b.Type {
+		case BlockCompanyHeader, BlockText, BlockField, BlockSpacer, BlockLine, BlockQR:
+		case BlockTotals:
+			if len(b.Rows) == 0 {
+				return fmt.Errorf("block %d: totals needs rows", i)
+			}
+		case BlockTable, BlockColumns:
+			if len(b.Columns) == 0 {
+				return fmt.Errorf("block %d: %s needs columns", i, b.Type)
+			}
+			if b.Type == BlockTable && b.Source == "" {
+				return fmt.Errorf("block %d: table needs a source", i)
+			}
+			for j, c := range b.Columns {
+				if c.Width <= 0 {
+					return fmt.Errorf("block %d column %d: width must be positive", i, j)
+				}
+				if err := validateBlocks(c.Blocks); err != nil {
+					return fmt.Errorf("block %d column %d: %w", i, j, err)
+				}
+			}
+		default:
+			return fmt.Errorf("block %d: unknown type %q", i, b.Type)
+		}
+		switch b.Align {
+		case "", "L", "C", "R":
+		default:
+			return fmt.Errorf("block %d: invalid align %q", i, b.Align)
+		}
+	}
+	return nil
+}
+
+// LoadTemplate returns the named template, preferring <dir>/<name>.json when
+// dir is set and the file exists so layouts can be changed without a rebuild
+func LoadTemplate(dir, name string) (*Template, error) {
+	if dir != "" {
+		data, err := os.ReadFile(filepath.Join(dir, name+".json"))
+		if err == nil {
+			tpl, err := ParseTemplate(data)
+			if err != nil {
+				return nil, fmt.Errorf("template %s: %w", name, err)
+			}
+			return tpl, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read template %s: %w", name, err)
+		}
+	}
+
+	data, err := defaultTemplates.ReadFile("templates/" + name + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("unknown template %q", name)
+	}
+	tpl, err := ParseTemplate(data)
+	if err != nil {
+		return nil, fmt.Errorf("default template %s: %w", name, err)
+	}
+	return tpl, nil
+}
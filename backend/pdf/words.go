@@ -0,0 +1,383 @@
+package pdf
+
+import (
+	"strings"
+)
+
+// currencyWords holds the names of a currency and its hundredth
+type currencyWords struct {
+	ar, arSub arNoun
+	fr, frSub frNoun
+	en, enSub enNoun
+}
+
+// arNoun holds the forms an Arabic counted noun takes: after 1 (and round
+// hundreds), 2, 3–10 and 11–99
+type arNoun struct {
+	singular, dual, plural, accusative string
+}
+
+type frNoun struct {
+	singular, plural string
+}
+
+type enNoun struct {
+	singular, plural string
+}
+
+var currencyNames = map[string]currencyWords{
+	"DZD": {
+		ar:    arNoun{"دينار جزائري", "ديناران جزائريان", "دنانير جزائرية", "دينارا جزائريا"},
+		arSub: arNoun{"سنتيم", "سنتيمان", "سنتيمات", "سنتيما"},
+		fr:    frNoun{"dinar algérien", "dinars algériens"},
+		frSub: frNoun{"centime", "centimes"},
+		en:    enNoun{"Algerian dinar", "Algerian dinars"},
+		enSub: enNoun{"centime", "centimes"},
+	},
+	"EUR": {
+		ar:    arNoun{"يورو", "يورو", "يورو", "يورو"},
+		arSub: arNoun{"سنت", "سنتان", "سنتات", "سنتا"},
+		fr:    frNoun{"euro", "euros"},
+		frSub: frNoun{"centime", "centimes"},
+		en:    enNoun{"euro", "euros"},
+		enSub: enNoun{"cent", "cents"},
+	},
+	"USD": {
+		ar:    arNoun{"دولار أمريكي", "دولاران أمريكيان", "دولارات أمريكية", "دولارا أمريكيا"},
+		arSub: arNoun{"سنت", "سنتان", "سنتات", "سنتا"},
+		fr:    frNoun{"dollar américain", "dollars américains"},
+		frSub: frNoun{"cent", "cents"},
+		en:    enNoun{"US dollar", "US dollars"},
+		enSub: enNoun{"cent", "cents"},
+	},
+}
+
+// amountToWords spells out an amount in cents for the "amount in words" line
+// of documents (تفقيط), e.g. 150000 DZD in Arabic is "ألف وخمسمائة دينار جزائري"
+// and in French "mille cinq cents dinars algériens". lang is "ar", "fr" or
+// "en"; anything else is Arabic.
+// Unknown currencies are named by their code.
+func amountToWords(cents int64, currency, lang string) string {
+	if currency == "" {
+		currency = "DZD"
+	}
+	names, ok := currencyNames[currency]
+	if !ok {
+		names = currencyNames["DZD"]
+		names.ar = arNoun{currency, currency, currency, currency}
+		names.fr = frNoun{currency, currency}
+		names.en = enNoun{currency, currency}
+	}
+
+	// split before dropping the sign: -math.MinInt64 does not fit in an int64
+	negative := cents < 0
+	units, sub := cents/100, cents%100
+	if negative {
+		units, sub = -units, -sub
+	}
+
+	var words string
+	if lang == "en" {
+		switch {
+		case units == 0 && sub > 0:
+			words = enCount(sub, names.enSub)
+		case sub > 0:
+			words = enCount(units, names.en) + " and " + enCount(sub, names.enSub)
+		default:
+			words = enCount(units, names.en)
+		}
+		if negative {
+			words = "minus " + words
+		}
+		return words
+	}
+	if lang == "fr" {
+		switch {
+		case units == 0 && sub > 0:
+			words = frCount(sub, names.frSub)
+		case sub > 0:
+			words = frCount(units, names.fr) + " et " + frCount(sub, names.frSub)
+		default:
+			words = frCount(units, names.fr)
+		}
+		if negative {
+			words = "moins " + words
+		}
+		return words
+	}
+
+	switch {
+	case units == 0 && sub > 0:
+		words = arCount(sub, names.arSub, true)
+	case units == 0:
+		words = "صفر " + names.ar.singular
+	default:
+		words = arCount(units, names.ar, true)
+		if sub > 0 {
+			words += " و" + arCount(sub, names.arSub, true)
+		}
+	}
+	if negative {
+		words = "ناقص " + words
+	}
+	return words
+}
+
+// Arabic
+
+var (
+	arOnes = [...]string{"", "واحد", "اثنان", "ثلاثة", "أربعة", "خمسة", "ستة", "سبعة", "ثمانية", "تسعة",
+		"عشرة", "أحد عشر", "اثنا عشر", "ثلاثة عشر", "أربعة عشر", "خمسة عشر", "ستة عشر", "سبعة عشر", "ثمانية عشر", "تسعة عشر"}
+	arTens     = [...]string{"", "", "عشرون", "ثلاثون", "أربعون", "خمسون", "ستون", "سبعون", "ثمانون", "تسعون"}
+	arHundreds = [...]string{"", "مائة", "مئتان", "ثلاثمائة", "أربعمائة", "خمسمائة", "ستمائة", "سبعمائة", "ثمانمائة", "تسعمائة"}
+	arScales   = [...]arNoun{
+		{},
+		{"ألف", "ألفان", "آلاف", "ألفا"},
+		{"مليون", "مليونان", "ملايين", "مليونا"},
+		{"مليار", "ملياران", "مليارات", "مليارا"},
+		{"تريليون", "تريليونان", "تريليونات", "تريليونا"},
+		{"كوادريليون", "كوادريليونان", "كوادريليونات", "كوادريليونا"},
+		{"كوينتليون", "كوينتليونان", "كوينتليونات", "كوينتليونا"},
+	}
+)
+
+// arCount states n of noun with the agreement Arabic requires: one dinar is
+// "دينار واحد" (or just "ألف" for scales), two is the dual, 3–10 take the
+// plural and 11–99 the accusative singular
+func arCount(n int64, noun arNoun, sayOne bool) string {
+	switch {
+	case n == 1:
+		if sayOne {
+			return noun.singular + " واحد"
+		}
+		return noun.singular
+	case n == 2:
+		return noun.dual
+	}
+	switch r := n % 100; {
+	case r >= 3 && r <= 10:
+		return arNumber(n) + " " + noun.plural
+	case r >= 11:
+		return arNumber(n) + " " + noun.accusative
+	case r == 0:
+		return arConstruct(arNumber(n)) + " " + noun.singular
+	}
+	return arNumber(n) + " " + noun.singular
+}
+
+// arConstruct puts the last word of a round number in the construct state it
+// takes before the counted noun: "ألفا دينار", "مئتا دينار", "ثمانون ألف دينار"
+func arConstruct(words string) string {
+	for _, scale := range arScales[1:] {
+		if strings.HasSuffix(words, " "+scale.accusative) {
+			return strings.TrimSuffix(words, scale.accusative) + scale.singular
+		}
+	}
+	if strings.HasSuffix(words, "ان") {
+		return strings.TrimSuffix(words, "ن")
+	}
+	return words
+}
+
+// arNumber spells out n > 0, joining groups with "و" from the largest down
+func arNumber(n int64) string {
+	var parts []string
+	for scale, group := range groupsOfThousand(n) {
+		switch {
+		case group == 0:
+		case scale == 0:
+			parts = append(parts, arBelowThousand(group))
+		default:
+			parts = append(parts, arCount(int64(group), arScales[scale], false))
+		}
+	}
+	reverseStrings(parts)
+	return strings.Join(parts, " و")
+}
+
+func arBelowThousand(n int) string {
+	var parts []string
+	if h := n / 100; h > 0 {
+		parts = append(parts, arHundreds[h])
+	}
+	switch r := n % 100; {
+	case r == 0:
+	case r < 20:
+		parts = append(parts, arOnes[r])
+	default:
+		// Units come first: واحد وعشرون
+		if r%10 > 0 {
+			parts = append(parts, arOnes[r%10])
+		}
+		parts = append(parts, arTens[r/10])
+	}
+	return strings.Join(parts, " و")
+}
+
+// French
+
+var (
+	frUnits = [...]string{"zéro", "un", "deux", "trois", "quatre", "cinq", "six", "sept", "huit", "neuf",
+		"dix", "onze", "douze", "treize", "quatorze", "quinze", "seize"}
+	frTens   = [...]string{"", "dix", "vingt", "trente", "quarante", "cinquante", "soixante", "soixante", "quatre-vingt", "quatre-vingt"}
+	frScales = [...]frNoun{{}, {"mille", "mille"}, {"million", "millions"}, {"milliard", "milliards"},
+		{"billion", "billions"}, {"billiard", "billiards"}, {"trillion", "trillions"}}
+)
+
+// frCount states n of noun; "de" links round millions to the noun
+// ("un million de dinars")
+func frCount(n int64, noun frNoun) string {
+	name := noun.plural
+	if n < 2 {
+		name = noun.singular
+	}
+	words := frNumber(n)
+	if n >= 1000000 && n%1000000 == 0 {
+		if strings.ContainsRune("aeiouéh", []rune(name)[0]) {
+			return words + " d'" + name
+		}
+		return words + " de " + name
+	}
+	return words + " " + name
+}
+
+func frNumber(n int64) string {
+	if n == 0 {
+		return frUnits[0]
+	}
+	var parts []string
+	for scale, group := range groupsOfThousand(n) {
+		switch {
+		case group == 0:
+		case scale == 0:
+			parts = append(parts, frBelowThousand(group, true))
+		case scale == 1 && group == 1:
+			parts = append(parts, "mille")
+		case scale == 1:
+			// "mille" is an adjective: "deux cent mille", "quatre-vingt mille"
+			parts = append(parts, frBelowThousand(group, false)+" mille")
+		default:
+			noun := frScales[scale].plural
+			if group == 1 {
+				noun = frScales[scale].singular
+			}
+			parts = append(parts, frBelowThousand(group, true)+" "+noun)
+		}
+	}
+	reverseStrings(parts)
+	return strings.Join(parts, " ")
+}
+
+// frBelowThousand spells 1–999. "cents" and "quatre-vingts" only take their
+// s when they end the number, so final is false before "mille".
+func frBelowThousand(n int, final bool) string {
+	var parts []string
+	h, r := n/100, n%100
+	switch {
+	case h == 1:
+		parts = append(parts, "cent")
+	case h > 1 && r == 0 && final:
+		parts = append(parts, frUnits[h]+" cents")
+	case h > 1:
+		parts = append(parts, frUnits[h]+" cent")
+	}
+	if r > 0 {
+		words := frBelowHundred(r)
+		if r == 80 && !final {
+			words = "quatre-vingt"
+		}
+		parts = append(parts, words)
+	}
+	return strings.Join(parts, " ")
+}
+
+func frBelowHundred(n int) string {
+	if n <= 16 {
+		return frUnits[n]
+	}
+	if n < 20 {
+		return "dix-" + frUnits[n-10]
+	}
+	t, u := n/10, n%10
+	if t == 7 || t == 9 {
+		// soixante-dix, quatre-vingt-onze...
+		u += 10
+	}
+	switch {
+	case u == 0 && t == 8:
+		return "quatre-vingts"
+	case u == 0:
+		return frTens[t]
+	case (u == 1 || u == 11) && t != 8 && t != 9:
+		return frTens[t] + " et " + frBelowHundred(u)
+	}
+	return frTens[t] + "-" + frBelowHundred(u)
+}
+
+// English
+
+var (
+	enOnes = [...]string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+		"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen"}
+	enTens   = [...]string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+	enScales = [...]string{"", "thousand", "million", "billion", "trillion", "quadrillion", "quintillion"}
+)
+
+func enCount(n int64, noun enNoun) string {
+	if n == 1 {
+		return "one " + noun.singular
+	}
+	return enNumber(n) + " " + noun.plural
+}
+
+// enNumber spells out n, e.g. "one thousand five hundred"
+func enNumber(n int64) string {
+	if n == 0 {
+		return enOnes[0]
+	}
+	var parts []string
+	for scale, group := range groupsOfThousand(n) {
+		switch {
+		case group == 0:
+		case scale == 0:
+			parts = append(parts, enBelowThousand(group))
+		default:
+			parts = append(parts, enBelowThousand(group)+" "+enScales[scale])
+		}
+	}
+	reverseStrings(parts)
+	return strings.Join(parts, " ")
+}
+
+func enBelowThousand(n int) string {
+	var parts []string
+	if h := n / 100; h > 0 {
+		parts = append(parts, enOnes[h]+" hundred")
+	}
+	switch r := n % 100; {
+	case r == 0:
+	case r < 20:
+		parts = append(parts, enOnes[r])
+	case r%10 == 0:
+		parts = append(parts, enTens[r/10])
+	default:
+		parts = append(parts, enTens[r/10]+"-"+enOnes[r%10])
+	}
+	return strings.Join(parts, " ")
+}
+
+// groupsOfThousand splits n into base-1000 digits, least significant first
+func groupsOfThousand(n int64) []int {
+	var groups []int
+	for n > 0 {
+		groups = append(groups, int(n%1000))
+		n /= 1000
+	}
+	return groups
+}
+
+func reverseStrings(s []string) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
@@ -0,0 +1,61 @@
+package pdf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAmountToWords(t *testing.T) {
+	tests := []struct {
+		name  string
+		cents int64
+		ar    string
+		fr    string
+	}{
+		{"one", 100, "دينار جزائري واحد", "un dinar algérien"},
+		{"dual", 200, "ديناران جزائريان", "deux dinars algériens"},
+		{"plural", 500, "خمسة دنانير جزائرية", "cinq dinars algériens"},
+		{"eleven", 1100, "أحد عشر دينارا جزائريا", "onze dinars algériens"},
+		{"ninety-nine", 9900, "تسعة وتسعون دينارا جزائريا", "quatre-vingt-dix-neuf dinars algériens"},
+		{"eighty", 8000, "ثمانون دينارا جزائريا", "quatre-vingts dinars algériens"},
+		{"two hundred", 20000, "مئتا دينار جزائري", "deux cents dinars algériens"},
+		{"two hundred one", 20100, "مئتان وواحد دينار جزائري", "deux cent un dinars algériens"},
+		{"thousand five hundred", 150000, "ألف وخمسمائة دينار جزائري", "mille cinq cents dinars algériens"},
+		{"eighty thousand", 8000000, "ثمانون ألف دينار جزائري", "quatre-vingt mille dinars algériens"},
+		{"one million", 100000000, "مليون دينار جزائري", "un million de dinars algériens"},
+		{"two million", 200000000, "مليونا دينار جزائري", "deux millions de dinars algériens"},
+		{"centimes", 150050, "ألف وخمسمائة دينار جزائري وخمسون سنتيما", "mille cinq cents dinars algériens et cinquante centimes"},
+		{"centimes only", 5, "خمسة سنتيمات", "cinq centimes"},
+		{"zero", 0, "صفر دينار جزائري", "zéro dinar algérien"},
+		{"negative", -200, "ناقص ديناران جزائريان", "moins deux dinars algériens"},
+		{"min int64", math.MinInt64,
+			"ناقص اثنان وتسعون كوادريليونا ومئتان وثلاثة وثلاثون تريليونا وسبعمائة وعشرون مليارا وثلاثمائة وثمانية وستون مليونا وخمسمائة وسبعة وأربعون ألفا وسبعمائة وثمانية وخمسون دينارا جزائريا وثمانية سنتيمات",
+			"moins quatre-vingt-douze billiards deux cent trente-trois billions sept cent vingt milliards trois cent soixante-huit millions cinq cent quarante-sept mille sept cent cinquante-huit dinars algériens et huit centimes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := amountToWords(tt.cents, "DZD", "ar"); got != tt.ar {
+				t.Errorf("ar: got %q, want %q", got, tt.ar)
+			}
+			if got := amountToWords(tt.cents, "DZD", "fr"); got != tt.fr {
+				t.Errorf("fr: got %q, want %q", got, tt.fr)
+			}
+		})
+	}
+}
+
+func TestAmountToWordsCurrency(t *testing.T) {
+	tests := []struct {
+		currency, lang, want string
+	}{
+		{"", "fr", "deux dinars algériens"},
+		{"EUR", "en", "two euros"},
+		{"USD", "ar", "دولاران أمريكيان"},
+		{"GBP", "fr", "deux GBP"},
+	}
+	for _, tt := range tests {
+		if got := amountToWords(200, tt.currency, tt.lang); got != tt.want {
+			t.Errorf("amountToWords(200, %q, %q) = %q, want %q", tt.currency, tt.lang, got, tt.want)
+		}
+	}
+}
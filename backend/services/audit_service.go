@@ -0,0 +1,31 @@
+package services
+
+import (
+	"context"
+	"barakaERP/backend/db"
+)
+
+// AuditService provides read access to the audit trail
+type AuditService struct {
+	repo     *db.Repository
+	settings *SettingsService
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(repo *db.Repository, settings *SettingsService) *AuditService {
+	return &AuditService{repo: repo, settings: settings}
+}
+
+// List retrieves audit entries with pagination and filters
+func (s *AuditService) List(ctx context.Context, filters db.AuditFilters, limit, offset int) (*db.PaginatedResult[db.AuditEntry], error) {
+	limit = s.settings.PageLimit(limit)
+
+	entries, total, err := s.repo.ListAuditLog(ctx, filters, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return &db.PaginatedResult[db.AuditEntry]{
+		Data:  entries,
+		Total: total,
+	}, nil
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"barakaERP/backend/db"
+	apperr "barakaERP/backend/domain/errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Admin password length bounds; bcrypt ignores bytes past 72
+const (
+	minAdminPasswordLen = 6
+	maxAdminPasswordLen = 72
+)
+
+var (
+	errInvalidAdminPassword = apperr.Forbidden("invalid_admin_password") // Wrong admin password
+	errAdminPasswordNotSet  = apperr.Forbidden("admin_password_not_set") // No admin password has been set yet
+)
+
+// AuthService checks the admin password that grants a session admin rights
+type AuthService struct {
+	repo *db.Repository
+}
+
+// NewAuthService creates a new auth service
+func NewAuthService(repo *db.Repository) *AuthService {
+	return &AuthService{repo: repo}
+}
+
+// HasAdminPassword reports whether an admin password has been set
+func (s *AuthService) HasAdminPassword(ctx context.Context) (bool, error) {
+	hash, err := s.repo.GetSecret(ctx, db.SecretAdminPassword)
+	if err != nil {
+		return false, err
+	}
+	return hash != nil, nil
+}
+
+// CheckAdminPassword verifies password against the stored admin password
+func (s *AuthService) CheckAdminPassword(ctx context.Context, password string) error {
+	hash, err := s.repo.GetSecret(ctx, db.SecretAdminPassword)
+	if err != nil {
+		return err
+	}
+	if hash == nil {
+		return errAdminPasswordNotSet
+	}
+	if err := bcrypt.CompareHashAndPassword(hash, []byte(password)); err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return errInvalidAdminPassword
+		}
+		return err
+	}
+	return nil
+}
+
+// SetAdminPassword sets the admin password. Changing it requires the
+// current one; the first password is set without one, on first run.
+func (s *AuthService) SetAdminPassword(ctx context.Context, current, password string) error {
+	set, err := s.HasAdminPassword(ctx)
+	if err != nil {
+		return err
+	}
+	if set {
+		if err := s.CheckAdminPassword(ctx, current); err != nil {
+			return err
+		}
+	}
+	if len(password) < minAdminPasswordLen {
+		return apperr.Validation("password", "admin_password_too_short").With("min", minAdminPasswordLen) // Admin password too short
+	}
+	if len(password) > maxAdminPasswordLen {
+		return apperr.Validation("password", "admin_password_too_long").With("max", maxAdminPasswordLen) // Admin password too long
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	return s.repo.SetSecret(ctx, db.SecretAdminPassword, hash)
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"barakaERP/backend/db"
+	apperr "barakaERP/backend/domain/errors"
+)
+
+var (
+	errInvalidCategoryID    = apperr.Validation("category_id", "invalid_category_id") // Invalid category ID
+	errCategoryNotFound     = apperr.NotFound("category_not_found")                   // Category not found
+	errCategoryNameRequired = apperr.Validation("name", "category_name_required")     // Category name is required
+)
+
+// CategoryService manages product categories
+type CategoryService struct {
+	repo *db.Repository
+}
+
+// NewCategoryService creates a new category service
+func NewCategoryService(repo *db.Repository) *CategoryService {
+	return &CategoryService{repo: repo}
+}
+
+// List retrieves all categories
+func (s *CategoryService) List(ctx context.Context) ([]db.Category, error) {
+	return s.repo.ListCategories(ctx)
+}
+
+// Create creates a category, under ParentID when set (admins only)
+func (s *CategoryService) Create(ctx context.Context, category db.Category) (*db.Category, error) {
+	if !db.ActorFromContext(ctx).IsAdmin() {
+		return nil, errAdminOnly
+	}
+	category.Name = strings.TrimSpace(category.Name)
+	if category.Name == "" {
+		return nil, errCategoryNameRequired
+	}
+	if err := s.checkParent(ctx, 0, category.ParentID); err != nil {
+		return nil, err
+	}
+	return s.repo.CreateCategory(ctx, category)
+}
+
+// Update renames a category or moves it under another parent (admins only)
+func (s *CategoryService) Update(ctx context.Context, category db.Category) (*db.Category, error) {
+	if !db.ActorFromContext(ctx).IsAdmin() {
+		return nil, errAdminOnly
+	}
+	if category.ID <= 0 {
+		return nil, errInvalidCategoryID
+	}
+	category.Name = strings.TrimSpace(category.Name)
+	if category.Name == "" {
+		return nil, errCategoryNameRequired
+	}
+	if err := s.checkParent(ctx, category.ID, category.ParentID); err != nil {
+		return nil, err
+	}
+	updated, err := s.repo.UpdateCategory(ctx, category)
+	if err != nil {
+		return nil, notFoundAs(err, errCategoryNotFound)
+	}
+	return updated, nil
+}
+
+// Delete removes a category and its discount rules; its subcategories and
+// products move up to its parent (admins only)
+func (s *CategoryService) Delete(ctx context.Context, id int64) error {
+	if !db.ActorFromContext(ctx).IsAdmin() {
+		return errAdminOnly
+	}
+	if id <= 0 {
+		return errInvalidCategoryID
+	}
+	return notFoundAs(s.repo.DeleteCategory(ctx, id), errCategoryNotFound)
+}
+
+// checkParent verifies that the parent of category id, if any, exists and is
+// neither the category itself nor one of its subcategories
+func (s *CategoryService) checkParent(ctx context.Context, id int64, parentID *int64) error {
+	if parentID == nil {
+		return nil
+	}
+	if *parentID <= 0 {
+		return apperr.Validation("parent_id", "invalid_category_id") // Invalid parent category ID
+	}
+	for next := parentID; next != nil; {
+		if *next == id {
+			return apperr.Validation("parent_id", "category_cycle") // A category cannot sit under itself
+		}
+		parent, err := s.repo.GetCategory(ctx, *next)
+		if err != nil {
+			return notFoundAs(err, errCategoryNotFound)
+		}
+		next = parent.ParentID
+	}
+	return nil
+}
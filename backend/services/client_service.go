@@ -2,26 +2,34 @@ package services
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"barakaERP/backend/db"
-	"strings"
+	apperr "barakaERP/backend/domain/errors"
+)
+
+var (
+	errClientNameRequired = apperr.Validation("name", "client_name_required") // Client name is required
+	errInvalidClientID    = apperr.Validation("id", "invalid_client_id")      // Invalid client ID
+	errClientNotFound     = apperr.NotFound("client_not_found")               // Client not found
+	errClientInUse        = apperr.Conflict("client_in_use")                  // Client is referenced by orders
 )
 
 // ClientService handles client-related business logic
 type ClientService struct {
-	repo *db.Repository
+	repo     *db.Repository
+	settings *SettingsService
 }
 
 // NewClientService creates a new client service
-func NewClientService(repo *db.Repository) *ClientService {
-	return &ClientService{repo: repo}
+func NewClientService(repo *db.Repository, settings *SettingsService) *ClientService {
+	return &ClientService{repo: repo, settings: settings}
 }
 
 // Create creates a new client
 func (s *ClientService) Create(ctx context.Context, client db.Client) (*db.Client, error) {
 	// Validate required fields
 	if client.Name == "" {
-		return nil, fmt.Errorf("اسم العميل مطلوب") // Client name is required
+		return nil, errClientNameRequired
 	}
 
 	return s.repo.CreateClient(ctx, client)
@@ -29,25 +37,27 @@ func (s *ClientService) Create(ctx context.Context, client db.Client) (*db.Clien
 
 // List retrieves clients with pagination and search
 func (s *ClientService) List(ctx context.Context, query string, limit, offset int) ([]db.Client, int, error) {
-	if limit <= 0 {
-		limit = 20 // Default page size
-	}
-	if limit > 100 {
-		limit = 100 // Max page size
-	}
+	limit = s.settings.PageLimit(limit)
 
 	return s.repo.ListClients(ctx, query, limit, offset)
 }
 
+// ListAfter retrieves a page of clients after cursor (keyset pagination)
+func (s *ClientService) ListAfter(ctx context.Context, query, cursor string, limit int) (*db.PaginatedResult[db.Client], error) {
+	limit = s.settings.PageLimit(limit)
+
+	return s.repo.ListClientsAfter(ctx, query, cursor, limit)
+}
+
 // Get retrieves a client by ID
 func (s *ClientService) Get(ctx context.Context, id int64) (*db.Client, error) {
 	if id <= 0 {
-		return nil, fmt.Errorf("معرف العميل غير صحيح") // Invalid client ID
+		return nil, errInvalidClientID
 	}
 
 	client, err := s.repo.GetClient(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("العميل غير موجود") // Client not found
+		return nil, notFoundAs(err, errClientNotFound)
 	}
 
 	return client, nil
@@ -57,16 +67,19 @@ func (s *ClientService) Get(ctx context.Context, id int64) (*db.Client, error) {
 func (s *ClientService) Update(ctx context.Context, client db.Client) (*db.Client, error) {
 	// Validate required fields
 	if client.ID <= 0 {
-		return nil, fmt.Errorf("معرف العميل مطلوب") // Client ID is required
+		return nil, errInvalidClientID
 	}
 	if client.Name == "" {
-		return nil, fmt.Errorf("اسم العميل مطلوب") // Client name is required
+		return nil, errClientNameRequired
 	}
 
 	// Check if client exists
-	_, err := s.repo.GetClient(ctx, client.ID)
+	existing, err := s.repo.GetClient(ctx, client.ID)
 	if err != nil {
-		return nil, fmt.Errorf("العميل غير موجود") // Client not found
+		return nil, notFoundAs(err, errClientNotFound)
+	}
+	if existing.DeletedAt != nil {
+		return nil, errClientNotFound
 	}
 
 	return s.repo.UpdateClient(ctx, client)
@@ -75,21 +88,20 @@ func (s *ClientService) Update(ctx context.Context, client db.Client) (*db.Clien
 // AdjustDebt adjusts a client's debt by deltaCents (can be negative) and creates a debt payment record
 func (s *ClientService) AdjustDebt(ctx context.Context, clientID int64, deltaCents int64, notes *string) (*db.Client, *db.DebtPayment, error) {
 	if clientID <= 0 {
-		return nil, nil, fmt.Errorf("معرف العميل غير صحيح")
+		return nil, nil, errInvalidClientID
 	}
 	
 	// Check if client exists
-	_, err := s.repo.GetClient(ctx, clientID)
-	if err != nil {
-		return nil, nil, fmt.Errorf("العميل غير موجود")
-	}
-	
-	// Don't allow negative debt
 	client, err := s.repo.GetClient(ctx, clientID)
 	if err != nil {
-		return nil, nil, fmt.Errorf("فشل في الحصول على معلومات العميل")
+		return nil, nil, notFoundAs(err, errClientNotFound)
+	}
+	if client.DeletedAt != nil {
+		return nil, nil, errClientNotFound
 	}
 	
+	// Don't allow negative debt
+
 	newDebt := client.DebtCents + deltaCents
 	if newDebt < 0 {
 		deltaCents = -client.DebtCents // Adjust delta to bring debt to 0
@@ -100,56 +112,81 @@ func (s *ClientService) AdjustDebt(ctx context.Context, clientID int64, deltaCen
 
 // GetDebtPayments retrieves all debt payment records with pagination
 func (s *ClientService) GetDebtPayments(ctx context.Context, limit, offset int) (*db.PaginatedResult[db.DebtPaymentDetail], error) {
-	if limit <= 0 {
-		limit = 20
-	}
-	if limit > 100 {
-		limit = 100
-	}
+	limit = s.settings.PageLimit(limit)
 	
 	return s.repo.GetDebtPayments(ctx, limit, offset)
 }
 
+// GetDebtPaymentsAfter retrieves a page of debt payment records after cursor (keyset pagination)
+func (s *ClientService) GetDebtPaymentsAfter(ctx context.Context, cursor string, limit int) (*db.PaginatedResult[db.DebtPaymentDetail], error) {
+	limit = s.settings.PageLimit(limit)
+
+	return s.repo.GetDebtPaymentsAfter(ctx, cursor, limit)
+}
+
 // GetClientDebtPayments retrieves debt payment records for a specific client
 func (s *ClientService) GetClientDebtPayments(ctx context.Context, clientID int64, limit, offset int) (*db.PaginatedResult[db.DebtPayment], error) {
 	if clientID <= 0 {
-		return nil, fmt.Errorf("معرف العميل غير صحيح")
+		return nil, errInvalidClientID
 	}
 	
-	if limit <= 0 {
-		limit = 20
-	}
-	if limit > 100 {
-		limit = 100
-	}
+	limit = s.settings.PageLimit(limit)
 	
 	return s.repo.GetClientDebtPayments(ctx, clientID, limit, offset)
 }
 
-// Delete removes a client if no restricting relations block it
+// Delete moves a client to the recycle bin if it has no active orders
 func (s *ClientService) Delete(ctx context.Context, id int64) error {
-	if id <= 0 { return fmt.Errorf("معرف العميل غير صحيح") }
+	if id <= 0 { return errInvalidClientID }
 	// Check existence
-	if _, err := s.repo.GetClient(ctx, id); err != nil { return fmt.Errorf("العميل غير موجود") }
-	// First attempt delete
+	client, err := s.repo.GetClient(ctx, id)
+	if err != nil { return notFoundAs(err, errClientNotFound) }
+	if client.DeletedAt != nil { return errClientNotFound }
 	// Ensure no active (non-canceled) orders remain
 	if hasActive, errAct := s.repo.HasActiveOrdersForClient(ctx, id); errAct != nil {
-		return fmt.Errorf("تعذر التحقق من الطلبات النشطة: %v", errAct)
+		return errAct
 	} else if hasActive {
-		return fmt.Errorf("لا يمكن حذف العميل لوجود طلبات غير ملغاة")
-	}
-	if err := s.repo.DeleteClient(ctx, id); err != nil {
-		// If FK constraint blocks, attempt to remove canceled orders then retry
-		errStr := err.Error()
-		if strings.Contains(errStr, "FOREIGN KEY") || strings.Contains(errStr, "foreign key") {
-			// Remove canceled orders
-			_, delErr := s.repo.DeleteCanceledOrdersForClient(ctx, id)
-			if delErr != nil { return fmt.Errorf("تعذر حذف الطلبات الملغاة للعميل: %v", delErr) }
-			// Retry delete
-			if err2 := s.repo.DeleteClient(ctx, id); err2 != nil {
-				return fmt.Errorf("لا يمكن حذف العميل لوجود طلبات أو فواتير نشطة")
-			}
-			return nil
+		return apperr.Conflict("client_has_active_orders")
+	}
+	return s.repo.DeleteClient(ctx, id)
+}
+
+// ListDeleted retrieves clients in the recycle bin
+func (s *ClientService) ListDeleted(ctx context.Context, query string, limit, offset int) ([]db.Client, int, error) {
+	limit = s.settings.PageLimit(limit)
+
+	return s.repo.ListDeletedClients(ctx, query, limit, offset)
+}
+
+// Restore brings a client back from the recycle bin
+func (s *ClientService) Restore(ctx context.Context, id int64) (*db.Client, error) {
+	if id <= 0 { return nil, errInvalidClientID }
+	if _, err := s.repo.GetClient(ctx, id); err != nil { return nil, notFoundAs(err, errClientNotFound) }
+	if err := s.repo.RestoreClient(ctx, id); err != nil { return nil, err }
+	return s.repo.GetClient(ctx, id)
+}
+
+// Purge permanently removes a client from the recycle bin (admins only)
+func (s *ClientService) Purge(ctx context.Context, id int64) error {
+	if !db.ActorFromContext(ctx).IsAdmin() {
+		return errAdminOnly
+	}
+	if id <= 0 { return errInvalidClientID }
+	client, err := s.repo.GetClient(ctx, id)
+	if err != nil { return notFoundAs(err, errClientNotFound) }
+	if client.DeletedAt == nil {
+		return apperr.Conflict("client_not_deleted") // Must be in recycle bin first
+	}
+	// Orders keep their history; a client referenced by any order stays in the recycle bin
+	count, err := s.repo.CountOrdersForClient(ctx, id)
+	if err != nil { return err }
+	if count > 0 {
+		return errClientInUse
+	}
+	// Invoices reference clients too; the foreign key catches those
+	if err := s.repo.PurgeClient(ctx, id); err != nil {
+		if errors.Is(err, apperr.ErrConflict) {
+			return errClientInUse.Wrap(err)
 		}
 		return err
 	}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"barakaERP/backend/db"
+	apperr "barakaERP/backend/domain/errors"
+)
+
+func TestAdjustDebt(t *testing.T) {
+	repo := newTestRepository(t)
+	clients := NewClientService(repo, NewSettingsService(repo))
+	ctx := db.WithActor(context.Background(), db.Actor{User: "admin", Role: db.RoleAdmin})
+	client, err := clients.Create(ctx, db.Client{Name: "سعيد", DebtCents: 5000})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a payment larger than the debt clears it
+	updated, _, err := clients.AdjustDebt(ctx, client.ID, -8000, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.DebtCents != 0 {
+		t.Errorf("got debt %d, want 0", updated.DebtCents)
+	}
+
+	// clients in the recycle bin keep their debt
+	if err := clients.Delete(ctx, client.ID); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = clients.AdjustDebt(ctx, client.ID, 1000, nil)
+	checkCode(t, err, apperr.CodeNotFound)
+}
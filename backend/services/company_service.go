@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"barakaERP/backend/db"
+	apperr "barakaERP/backend/domain/errors"
+)
+
+// maxLogoBytes caps the logo stored in company settings
+const maxLogoBytes = 1 << 20
+
+// CompanyService manages the company profile used for document branding
+type CompanyService struct {
+	repo *db.Repository
+}
+
+// NewCompanyService creates a new company service
+func NewCompanyService(repo *db.Repository) *CompanyService {
+	return &CompanyService{repo: repo}
+}
+
+// Get retrieves the company profile
+func (s *CompanyService) Get(ctx context.Context) (*db.CompanySettings, error) {
+	return s.repo.GetCompanySettings(ctx)
+}
+
+// Update validates and saves the company profile. The logo type is detected
+// from the image bytes; an empty logo removes it.
+func (s *CompanyService) Update(ctx context.Context, settings db.CompanySettings) (*db.CompanySettings, error) {
+	settings.NameAR = strings.TrimSpace(settings.NameAR)
+	if settings.NameAR == "" {
+		return nil, apperr.Validation("name_ar", "company_name_required") // Arabic company name is required
+	}
+
+	phones := make([]string, 0, len(settings.Phones))
+	for _, phone := range settings.Phones {
+		if phone = strings.TrimSpace(phone); phone != "" {
+			phones = append(phones, phone)
+		}
+	}
+	settings.Phones = phones
+
+	settings.LogoType = nil
+	if len(settings.Logo) == 0 {
+		settings.Logo = nil
+	} else {
+		if len(settings.Logo) > maxLogoBytes {
+			return nil, apperr.Validation("logo", "logo_too_large").With("max_mb", maxLogoBytes>>20) // Logo must not exceed 1 MB
+		}
+		var logoType string
+		switch http.DetectContentType(settings.Logo) {
+		case "image/png":
+			logoType = "PNG"
+		case "image/jpeg":
+			logoType = "JPG"
+		default:
+			return nil, apperr.Validation("logo", "unsupported_logo_format") // Unsupported logo format
+		}
+		settings.LogoType = &logoType
+	}
+
+	return s.repo.UpdateCompanySettings(ctx, settings)
+}
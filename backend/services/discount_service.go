@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+	"barakaERP/backend/db"
+	apperr "barakaERP/backend/domain/errors"
+)
+
+var (
+	errInvalidDiscountRuleID    = apperr.Validation("id", "invalid_discount_rule_id")      // Invalid discount rule ID
+	errDiscountRuleNotFound     = apperr.NotFound("discount_rule_not_found")               // Discount rule not found
+	errDiscountRuleNameRequired = apperr.Validation("name", "discount_rule_name_required") // Discount rule name is required
+	errDiscountRuleTarget       = apperr.Validation("product_id", "discount_rule_target")  // A rule targets a product or a category, not both
+	errInvalidMinQty            = apperr.Validation("min_qty", "invalid_min_qty")          // Minimum quantity must be greater than zero
+	errInvalidRuleValidity      = apperr.Validation("valid_to", "invalid_rule_validity")   // Validity ends before it starts
+)
+
+// DiscountService manages discount rules and applies them to order lines:
+// volume tiers (a percentage from a minimum quantity) and promotions (a
+// percentage between two dates) on a product, a category or every product.
+// It also holds manual discounts to the cap of the user's role.
+type DiscountService struct {
+	repo     *db.Repository
+	settings *SettingsService
+}
+
+// NewDiscountService creates a new discount service
+func NewDiscountService(repo *db.Repository, settings *SettingsService) *DiscountService {
+	return &DiscountService{repo: repo, settings: settings}
+}
+
+// ListRules retrieves all discount rules
+func (s *DiscountService) ListRules(ctx context.Context) ([]db.DiscountRule, error) {
+	return s.repo.ListDiscountRules(ctx)
+}
+
+// SaveRule creates a discount rule, or updates it when rule.ID is set
+// (admins only). New rules are active; empty validity dates leave that side
+// open.
+func (s *DiscountService) SaveRule(ctx context.Context, rule db.DiscountRule) (*db.DiscountRule, error) {
+	if !db.ActorFromContext(ctx).IsAdmin() {
+		return nil, errAdminOnly
+	}
+	if rule.ID < 0 {
+		return nil, errInvalidDiscountRuleID
+	}
+	rule.Name = strings.TrimSpace(rule.Name)
+	if rule.Name == "" {
+		return nil, errDiscountRuleNameRequired
+	}
+	if rule.ProductID != nil && rule.CategoryID != nil {
+		return nil, errDiscountRuleTarget
+	}
+	if rule.ProductID != nil {
+		if *rule.ProductID <= 0 {
+			return nil, errInvalidProductID
+		}
+		if _, err := s.repo.GetProduct(ctx, *rule.ProductID); err != nil {
+			return nil, notFoundAs(err, errProductNotFound)
+		}
+	}
+	if rule.CategoryID != nil {
+		if *rule.CategoryID <= 0 {
+			return nil, errInvalidCategoryID
+		}
+		if _, err := s.repo.GetCategory(ctx, *rule.CategoryID); err != nil {
+			return nil, notFoundAs(err, errCategoryNotFound)
+		}
+	}
+	if rule.MinQty == 0 {
+		rule.MinQty = 1
+	}
+	if rule.MinQty < 0 {
+		return nil, errInvalidMinQty
+	}
+	if rule.Percent <= 0 || rule.Percent > 100 {
+		return nil, apperr.Validation("percent", "invalid_discount") // Discount percentage must be between 0 and 100
+	}
+
+	dates := []struct {
+		field string
+		value **string
+	}{{"valid_from", &rule.ValidFrom}, {"valid_to", &rule.ValidTo}}
+	for _, d := range dates {
+		if *d.value == nil {
+			continue
+		}
+		v := strings.TrimSpace(**d.value)
+		if v == "" {
+			*d.value = nil
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", v); err != nil {
+			return nil, apperr.Validation(d.field, "invalid_date").With("value", v) // Invalid date format
+		}
+		*d.value = &v
+	}
+	if rule.ValidFrom != nil && rule.ValidTo != nil && *rule.ValidTo < *rule.ValidFrom {
+		return nil, errInvalidRuleValidity
+	}
+
+	if rule.ID == 0 {
+		rule.Active = true
+	}
+	saved, err := s.repo.SaveDiscountRule(ctx, rule)
+	if err != nil {
+		return nil, notFoundAs(err, errDiscountRuleNotFound)
+	}
+	return saved, nil
+}
+
+// DeleteRule removes a discount rule (admins only)
+func (s *DiscountService) DeleteRule(ctx context.Context, id int64) error {
+	if !db.ActorFromContext(ctx).IsAdmin() {
+		return errAdminOnly
+	}
+	if id <= 0 {
+		return errInvalidDiscountRuleID
+	}
+	return notFoundAs(s.repo.DeleteDiscountRule(ctx, id), errDiscountRuleNotFound)
+}
+
+// discountCap returns the largest manual discount the user may give, and
+// false when their role is not capped
+func (s *DiscountService) discountCap(ctx context.Context) (int, bool) {
+	max, ok := s.settings.Get().DiscountCaps[db.ActorFromContext(ctx).Role]
+	return max, ok
+}
+
+// checkOrderDiscount holds an order-level discount to the user's cap
+func (s *DiscountService) checkOrderDiscount(ctx context.Context, pct int) error {
+	if max, capped := s.discountCap(ctx); capped && pct > max {
+		return apperr.Validation("discount_percent", "discount_above_cap").With("max", max) // Discount exceeds the role's cap
+	}
+	return nil
+}
+
+// applyDiscounts gives the lines of an order dated date the best rule
+// discount for their product and quantity in base units, unless the line already has a
+// discount, and records the rule on the line. Other discounts are manual and
+// may not exceed the user's cap, or the rule's percentage when larger.
+func (s *DiscountService) applyDiscounts(ctx context.Context, items []db.OrderItemDraft, date time.Time) error {
+	max, capped := s.discountCap(ctx)
+	for i := range items {
+		item := &items[i]
+		item.DiscountRuleID = nil
+		if item.DiscountPercent < 0 || item.DiscountPercent > 100 {
+			return errInvalidDiscount.With("item", i+1)
+		}
+
+		limit := max
+		if item.ProductID != nil && item.Qty > 0 {
+			product, err := s.repo.GetProduct(ctx, *item.ProductID)
+			if err != nil {
+				return apperr.From(notFoundAs(err, errProductNotFound)).With("item", i+1)
+			}
+			factor, err := s.repo.UnitFactor(ctx, product, item.Unit)
+			if err != nil {
+				return apperr.From(err).With("item", i+1)
+			}
+			rule, err := s.repo.FindDiscountRule(ctx, product, item.Qty.Times(factor), date.Format("2006-01-02"))
+			if err != nil {
+				return err
+			}
+			if rule != nil {
+				if item.DiscountPercent == 0 {
+					item.DiscountPercent = rule.Percent
+				}
+				if item.DiscountPercent == rule.Percent {
+					item.DiscountRuleID = &rule.ID
+				}
+				if rule.Percent > limit {
+					limit = rule.Percent
+				}
+			}
+		}
+
+		if item.DiscountRuleID == nil && capped && item.DiscountPercent > limit {
+			return apperr.Validation("discount_percent", "discount_above_cap").
+				With("item", i+1).
+				With("max", limit) // Discount exceeds the role's cap
+		}
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package services
+
+import (
+	"errors"
+	apperr "barakaERP/backend/domain/errors"
+)
+
+// Errors shared by several services
+var (
+	errAdminOnly = apperr.Forbidden("admin_only") // Admin only
+)
+
+// notFoundAs replaces a repository not-found error with the service's own,
+// keeping the original as its cause; any other error (a real database
+// failure) is returned unchanged
+func notFoundAs(err error, notFound *apperr.Error) error {
+	if errors.Is(err, apperr.ErrNotFound) {
+		return notFound.Wrap(err)
+	}
+	return err
+}
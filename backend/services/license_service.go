@@ -1,124 +1,358 @@
 package services
 
 import (
-	"bufio"
-	"fmt"
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
+	apperr "barakaERP/backend/domain/errors"
+	"barakaERP/backend/i18n"
+	"barakaERP/backend/logging"
 )
 
-// LicenseService handles license validation
-type LicenseService struct {
-	licenseURL    string
-	client        *http.Client
-	checkEnabled  bool
+var licenseLog = logging.For(logging.License)
+
+// licensePublicKey verifies license files (Ed25519, base64). Release builds
+// may set their own with -ldflags "-X barakaERP/backend/services.licensePublicKey=...".
+var licensePublicKey = "hpisdbYQ9RtSU1WvAT/WJgZbe41hciJMHn5AX09Q9Ng="
+
+const (
+	licenseFileName      = "license.json"
+	licenseCacheFileName = "license-state.json"
+	// licenseGracePeriod keeps the app usable this long after the last
+	// successful validation when the license file expires or goes missing
+	licenseGracePeriod = 7 * 24 * time.Hour
+	// clockTolerance is how far the clock may go back before it is treated as
+	// set back to extend the license
+	clockTolerance = 24 * time.Hour
+)
+
+// License states reported in LicenseStatus.State
+const (
+	LicenseStateValid         = "valid"
+	LicenseStateGrace         = "grace"         // usable on the last good validation, see Message
+	LicenseStateDisabled      = "disabled"      // license checking is off (development)
+	LicenseStateMissing       = "missing"       // no license file installed
+	LicenseStateInvalid       = "invalid"       // malformed file or bad signature
+	LicenseStateWrongMachine  = "wrong_machine" // issued for another computer
+	LicenseStateExpired       = "expired"
+	LicenseStateClockRollback = "clock_rollback" // system clock earlier than a previous check
+)
+
+// Licensed features. Core modules (clients, products, orders and their PDF)
+// are always available; these are enabled per customer tier. Each one must
+// be checked with requireFeature by the App methods of its module.
+const (
+	FeatureInvoices    = "invoices"
+	FeatureReceipts    = "receipts"     // thermal receipts and ESC/POS printing
+	FeatureBatchExport = "batch_export" // exporting many orders at once
+	FeatureAuditLog    = "audit_log"
+)
+
+// Customer tiers
+const (
+	TierBasic      = "basic"
+	TierPro        = "pro"
+	TierEnterprise = "enterprise"
+)
+
+// tierEntitlements are the features and seats each tier includes; a
+// MaxUsers of 0 means unlimited
+var tierEntitlements = map[string]struct {
+	features []string
+	maxUsers int
+}{
+	TierBasic:      {nil, 1},
+	TierPro:        {[]string{FeatureInvoices, FeatureReceipts, FeatureBatchExport, FeatureAuditLog}, 3},
+	TierEnterprise: {[]string{FeatureInvoices, FeatureReceipts, FeatureBatchExport, FeatureAuditLog}, 0},
 }
 
-// loadEnvFile loads environment variables from .env file
-func loadEnvFile() {
-	envFile := ".env"
-	if _, err := os.Stat(envFile); os.IsNotExist(err) {
-		return // .env file doesn't exist, skip
-	}
+// License is the signed content of a license file
+type License struct {
+	ID        string    `json:"id"`
+	Customer  string    `json:"customer"`
+	Machine   string    `json:"machine"` // MachineFingerprint it is bound to; empty for any computer
+	IssuedAt  time.Time `json:"issued_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Tier      string    `json:"tier"`      // basic when empty
+	Features  []string  `json:"features"`  // granted on top of the tier's
+	MaxUsers  int       `json:"max_users"` // overrides the tier's seat count when set
+}
 
-	file, err := os.Open(envFile)
-	if err != nil {
-		return // Can't open file, skip
-	}
-	defer file.Close()
+// Entitlements is what a license grants
+type Entitlements struct {
+	Tier     string   `json:"tier"`
+	Features []string `json:"features"`
+	MaxUsers int      `json:"max_users"` // 0 for unlimited
+}
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue // Skip empty lines and comments
+// Entitlements combines the license's tier with its own features and seats
+func (l *License) Entitlements() Entitlements {
+	tier := l.Tier
+	if _, ok := tierEntitlements[tier]; !ok {
+		tier = TierBasic
+	}
+	base := tierEntitlements[tier]
+	e := Entitlements{Tier: tier, MaxUsers: base.maxUsers}
+	seen := map[string]bool{}
+	for _, f := range append(append([]string{}, base.features...), l.Features...) {
+		if !seen[f] {
+			seen[f] = true
+			e.Features = append(e.Features, f)
 		}
+	}
+	if l.MaxUsers > 0 {
+		e.MaxUsers = l.MaxUsers
+	}
+	return e
+}
 
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) != 2 {
-			continue // Invalid format, skip
+// Has reports whether feature is granted
+func (e Entitlements) Has(feature string) bool {
+	for _, f := range e.Features {
+		if f == feature {
+			return true
 		}
+	}
+	return false
+}
 
-		key := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
-		
-		// Only set if not already set in environment
-		if os.Getenv(key) == "" {
-			os.Setenv(key, value)
-		}
+// licenseFile is the on-disk form of a license: the license JSON exactly as
+// signed and its Ed25519 signature
+type licenseFile struct {
+	License   json.RawMessage `json:"license"`
+	Signature string          `json:"signature"`
+}
+
+// licenseCache records the last successful validation for the grace period
+// and the latest clock reading seen
+type licenseCache struct {
+	LicenseID    string       `json:"license_id"`
+	Customer     string       `json:"customer"`
+	Entitlements Entitlements `json:"entitlements"`
+	ExpiresAt    time.Time    `json:"expires_at"`
+	ValidatedAt  time.Time    `json:"validated_at"`
+	LastSeen     time.Time    `json:"last_seen"`
+	MAC          string       `json:"mac"`
+}
+
+// licenseError is a failed check with the state it maps to and its message,
+// a key under "license" in the locale files
+type licenseError struct {
+	state  string
+	key    string
+	params map[string]any
+}
+
+func (e *licenseError) Error() string { return i18n.T(i18n.Default, "license."+e.key, e.params) }
+
+// appError is the error returned when a license file is rejected; its key is
+// the message key prefixed with "license_" under "errors"
+func (e *licenseError) appError() *apperr.Error {
+	err := apperr.Validation("license", "license_"+e.key)
+	for name, value := range e.params {
+		err = err.With(name, value)
 	}
+	return err
 }
 
-// NewLicenseService creates a new license service
-func NewLicenseService() *LicenseService {
-	// Load .env file
-	loadEnvFile()
-	
+// licenseAppError returns the apperr form of a failed license check
+func licenseAppError(err error) error {
+	var lerr *licenseError
+	if errors.As(err, &lerr) {
+		return lerr.appError()
+	}
+	return err
+}
+
+// LicenseService validates the offline license file of this installation.
+// Validation never needs the network; a license server, when configured, is
+// only used to fetch renewed license files.
+type LicenseService struct {
+	dir          string // holds the license and cache files
+	publicKey    ed25519.PublicKey
+	serverURL    string
+	client       *http.Client
+	checkEnabled bool
+	now          func() time.Time
+	mu           sync.Mutex
+	// last is the latest status, reused by HasFeature until statusTTL passes
+	last      *LicenseStatus
+	checkedAt time.Time
+}
+
+// statusTTL is how long HasFeature trusts the last validation
+const statusTTL = time.Hour
+
+// NewLicenseService creates a license service keeping its files in dir
+// (the application data directory). Checking can only be turned off for
+// development, with ENABLE_LICENSE_CHECK=false in the environment.
+func NewLicenseService(dir string) *LicenseService {
 	// Check if license checking is enabled
 	checkEnabled := true
 	if env := os.Getenv("ENABLE_LICENSE_CHECK"); env != "" {
 		checkEnabled = strings.ToLower(env) == "true"
 	}
 
+	key, _ := base64.StdEncoding.DecodeString(licensePublicKey)
 	return &LicenseService{
-		licenseURL:   "https://api-sadine.sorino.io/test",
+		dir:          dir,
+		publicKey:    ed25519.PublicKey(key),
 		checkEnabled: checkEnabled,
 		client: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		now: time.Now,
 	}
 }
 
 // LicenseStatus represents the license validation result
 type LicenseStatus struct {
-	IsValid   bool   `json:"is_valid"`
-	Message   string `json:"message"`
-	ExpiresAt string `json:"expires_at,omitempty"`
+	IsValid bool   `json:"is_valid"`
+	State   string `json:"state"`
+	// Message is the text of MessageKey, a key under "license" in the locale
+	// files, in the default locale until Localize is called
+	Message       string         `json:"message"`
+	MessageKey    string         `json:"message_key"`
+	MessageParams map[string]any `json:"message_params,omitempty"`
+	Customer      string         `json:"customer,omitempty"`
+	ExpiresAt     string         `json:"expires_at,omitempty"`
+	GraceUntil    string         `json:"grace_until,omitempty"`
+	// Entitlements are empty unless IsValid
+	Entitlements Entitlements `json:"entitlements"`
+	// MachineID is this computer's fingerprint, to be sent when requesting a license
+	MachineID string `json:"machine_id"`
 }
 
-// ValidateLicense checks if the license is valid by making a GET request to the license server
+// Localize returns a copy of the status with Message in locale. In the grace
+// state the message explains the failure, then until when the app works.
+func (st *LicenseStatus) Localize(locale string) *LicenseStatus {
+	c := *st
+	c.Message = i18n.T(locale, "license."+st.MessageKey, st.MessageParams)
+	if st.State == LicenseStateGrace {
+		c.Message += " " + i18n.T(locale, "license.grace", map[string]any{"until": st.GraceUntil})
+	}
+	return &c
+}
+
+// ValidateLicense checks the installed license file. When it is missing,
+// expired or unreadable, the last successful validation keeps the app usable
+// for the grace period. Failures are reported in the status, not as errors.
 func (s *LicenseService) ValidateLicense() (*LicenseStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := s.validate().Localize(i18n.Default)
+	s.last, s.checkedAt = status, s.now()
+	if status.State != LicenseStateValid && status.State != LicenseStateDisabled {
+		licenseLog.Warn("license not valid", "state", status.State, "message", status.Message)
+	}
+	return status, nil
+}
+
+func (s *LicenseService) validate() *LicenseStatus {
+	machine := MachineFingerprint()
 	// If license checking is disabled, always return valid
 	if !s.checkEnabled {
 		return &LicenseStatus{
-			IsValid: true,
-			Message: "License checking is disabled in development mode",
-		}, nil
+			IsValid:      true,
+			State:        LicenseStateDisabled,
+			MessageKey:   "disabled",
+			MachineID:    machine,
+			Entitlements: (&License{Tier: TierEnterprise}).Entitlements(),
+		}
 	}
 
-	resp, err := s.client.Get(s.licenseURL)
-	if err != nil {
-		// Network error - assume trial expired for security
+	now := s.now().UTC()
+
+	cache := s.loadCache(machine)
+	if cache != nil && now.Before(cache.LastSeen.Add(-clockTolerance)) {
 		return &LicenseStatus{
-			IsValid: false,
-			Message: "Unable to validate license. Please check your internet connection.",
-		}, nil
+			State:      LicenseStateClockRollback,
+			MessageKey: "clock_rollback",
+			MachineID:  machine,
+		}
 	}
-	defer resp.Body.Close()
 
-	// If we get a 404, the trial has ended
-	if resp.StatusCode == http.StatusNotFound {
+	lic, err := s.readLicense(machine, now)
+	if err == nil {
+		s.saveCache(machine, &licenseCache{
+			LicenseID:    lic.ID,
+			Customer:     lic.Customer,
+			Entitlements: lic.Entitlements(),
+			ExpiresAt:    lic.ExpiresAt,
+			ValidatedAt:  now,
+			LastSeen:     now,
+		})
 		return &LicenseStatus{
-			IsValid: false,
-			Message: "Your free trial has ended. Please contact support to continue using barakaERP.",
-		}, nil
+			IsValid:      true,
+			State:        LicenseStateValid,
+			MessageKey:   "valid",
+			Customer:     lic.Customer,
+			ExpiresAt:    formatLicenseTime(lic.ExpiresAt),
+			MachineID:    machine,
+			Entitlements: lic.Entitlements(),
+		}
 	}
 
-	// If we get a 200, the trial is still active
-	if resp.StatusCode == http.StatusOK {
-		return &LicenseStatus{
-			IsValid: true,
-			Message: "Trial license is active",
-		}, nil
+	var lerr *licenseError
+	if !errors.As(err, &lerr) {
+		lerr = &licenseError{LicenseStateInvalid, "unreadable", map[string]any{"error": err.Error()}}
+	}
+	status := &LicenseStatus{State: lerr.state, MessageKey: lerr.key, MessageParams: lerr.params, MachineID: machine}
+	if cache != nil {
+		if now.After(cache.LastSeen) {
+			cache.LastSeen = now
+			s.saveCache(machine, cache)
+		}
+		// A license for another computer never gets a grace period
+		graceUntil := cache.ValidatedAt.Add(licenseGracePeriod)
+		if lerr.state != LicenseStateWrongMachine && now.Before(graceUntil) {
+			status.IsValid = true
+			status.State = LicenseStateGrace
+			status.Customer = cache.Customer
+			status.Entitlements = cache.Entitlements
+			status.ExpiresAt = formatLicenseTime(cache.ExpiresAt)
+			status.GraceUntil = formatLicenseTime(graceUntil)
+		}
 	}
+	return status
+}
+
+// current returns the last status, validating again when it is older than statusTTL
+func (s *LicenseService) current() *LicenseStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.last == nil || s.now().Sub(s.checkedAt) > statusTTL {
+		s.last, s.checkedAt = s.validate().Localize(i18n.Default), s.now()
+	}
+	return s.last
+}
 
-	// Any other status code - assume trial expired for security
-	return &LicenseStatus{
-		IsValid: false,
-		Message: fmt.Sprintf("License validation failed with status: %d", resp.StatusCode),
-	}, nil
+// HasFeature reports whether the license grants feature (one of the Feature* names)
+func (s *LicenseService) HasFeature(name string) bool {
+	status := s.current()
+	return status.IsValid && status.Entitlements.Has(name)
+}
+
+// MaxUsers returns the licensed number of users; 0 means unlimited. An
+// invalid license allows a single user.
+func (s *LicenseService) MaxUsers() int {
+	status := s.current()
+	if !status.IsValid {
+		return 1
+	}
+	return status.Entitlements.MaxUsers
 }
 
 // CheckLicenseQuiet performs a license check without detailed error messages
@@ -133,4 +367,177 @@ func (s *LicenseService) CheckLicenseQuiet() bool {
 		return false
 	}
 	return status.IsValid
-}
\ No newline at end of file
+}
+
+// SetServerURL sets the license server RefreshLicense uses; empty disables it
+func (s *LicenseService) SetServerURL(serverURL string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.serverURL = serverURL
+}
+
+// InstallLicense verifies a license file issued for this computer and
+// installs it in place of the current one
+func (s *LicenseService) InstallLicense(data []byte) (*LicenseStatus, error) {
+	machine := MachineFingerprint()
+	lic, err := s.verify(data)
+	if err == nil {
+		err = checkLicense(lic, machine, s.now().UTC())
+	}
+	if err != nil {
+		return nil, licenseAppError(err)
+	}
+	if err := s.writeFile(licenseFileName, data); err != nil {
+		return nil, &apperr.Error{Code: apperr.CodeInternal, Key: "license_install_failed", Err: err} // Failed to install license
+	}
+	return s.ValidateLicense()
+}
+
+// RefreshLicense asks the license server for the current license of this
+// computer (e.g. after a renewal) and installs it. Network failures are
+// returned as errors and leave the installed license untouched.
+func (s *LicenseService) RefreshLicense(ctx context.Context) (*LicenseStatus, error) {
+	s.mu.Lock()
+	serverURL := s.serverURL
+	s.mu.Unlock()
+	if serverURL == "" {
+		return nil, apperr.Conflict("license_server_not_configured") // No license server configured
+	}
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return nil, apperr.Validation("license_server_url", "invalid_license_server_url").Wrap(err) // Invalid license server URL
+	}
+	// keep any query parameters already in the configured URL
+	query := u.Query()
+	query.Set("machine", MachineFingerprint())
+	if lic, err := s.verifyFile(); err == nil {
+		query.Set("license", lic.ID)
+	}
+	u.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, apperr.Validation("license_server_url", "invalid_license_server_url").Wrap(err) // Invalid license server URL
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, &apperr.Error{Code: apperr.CodeInternal, Key: "license_server_unreachable", Err: err} // License server unreachable
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, apperr.NotFound("license_not_registered") // No license is registered for this computer
+	default:
+		return nil, (&apperr.Error{Code: apperr.CodeInternal, Key: "license_server_error"}).With("status", resp.StatusCode) // License server returned an error status
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return nil, &apperr.Error{Code: apperr.CodeInternal, Key: "license_server_unreachable", Err: err} // Failed to download license
+	}
+	return s.InstallLicense(data)
+}
+
+// readLicense loads, verifies and checks the installed license
+func (s *LicenseService) readLicense(machine string, now time.Time) (*License, error) {
+	lic, err := s.verifyFile()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkLicense(lic, machine, now); err != nil {
+		return nil, err
+	}
+	return lic, nil
+}
+
+func (s *LicenseService) verifyFile() (*License, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, licenseFileName))
+	if os.IsNotExist(err) {
+		return nil, &licenseError{LicenseStateMissing, "missing", nil}
+	}
+	if err != nil {
+		return nil, &licenseError{LicenseStateInvalid, "unreadable", map[string]any{"error": err.Error()}}
+	}
+	return s.verify(data)
+}
+
+// verify checks the signature of a license file and decodes it
+func (s *LicenseService) verify(data []byte) (*License, error) {
+	invalid := &licenseError{LicenseStateInvalid, "invalid", nil}
+	var file licenseFile
+	if err := json.Unmarshal(data, &file); err != nil || len(file.License) == 0 {
+		return nil, invalid
+	}
+	sig, err := base64.StdEncoding.DecodeString(file.Signature)
+	if err != nil || len(s.publicKey) != ed25519.PublicKeySize || !ed25519.Verify(s.publicKey, file.License, sig) {
+		return nil, invalid
+	}
+	var lic License
+	if err := json.Unmarshal(file.License, &lic); err != nil {
+		return nil, invalid
+	}
+	return &lic, nil
+}
+
+// checkLicense checks a verified license against this computer and the date
+func checkLicense(lic *License, machine string, now time.Time) error {
+	if lic.Machine != "" && !strings.EqualFold(lic.Machine, machine) {
+		return &licenseError{LicenseStateWrongMachine, "wrong_machine", map[string]any{"machine": machine}}
+	}
+	if !lic.ExpiresAt.IsZero() && !now.Before(lic.ExpiresAt) {
+		return &licenseError{LicenseStateExpired, "expired", map[string]any{"date": formatLicenseTime(lic.ExpiresAt)}}
+	}
+	return nil
+}
+
+// loadCache returns the last validation record, or nil when there is none or
+// it was not written on this computer
+func (s *LicenseService) loadCache(machine string) *licenseCache {
+	data, err := os.ReadFile(filepath.Join(s.dir, licenseCacheFileName))
+	if err != nil {
+		return nil
+	}
+	var cache licenseCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil
+	}
+	if !hmac.Equal([]byte(cache.MAC), []byte(cacheMAC(&cache, machine))) {
+		return nil
+	}
+	return &cache
+}
+
+func (s *LicenseService) saveCache(machine string, cache *licenseCache) {
+	cache.MAC = cacheMAC(cache, machine)
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return
+	}
+	// Best effort: without a cache there is only no grace period
+	_ = s.writeFile(licenseCacheFileName, data)
+}
+
+// cacheMAC ties the cache to this computer and deters editing it by hand
+func cacheMAC(cache *licenseCache, machine string) string {
+	c := *cache
+	c.MAC = ""
+	data, _ := json.Marshal(c)
+	mac := hmac.New(sha256.New, []byte("barakaERP license cache:"+machine))
+	mac.Write(data)
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// writeFile replaces a file in the service directory atomically
+func (s *LicenseService) writeFile(name string, data []byte) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+	tmp := filepath.Join(s.dir, name+".tmp")
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(s.dir, name))
+}
+
+func formatLicenseTime(t time.Time) string {
+	return t.Format("2006-01-02")
+}
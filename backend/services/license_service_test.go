@@ -0,0 +1,351 @@
+package services
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	apperr "barakaERP/backend/domain/errors"
+)
+
+// testLicenses signs license files with a key generated for the test and
+// runs a LicenseService trusting it, on a clock the test moves
+type testLicenses struct {
+	t   *testing.T
+	key ed25519.PrivateKey
+	now time.Time
+	svc *LicenseService
+}
+
+func newTestLicenses(t *testing.T) *testLicenses {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tl := &testLicenses{t: t, key: priv, now: time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)}
+	tl.svc = &LicenseService{
+		dir:          t.TempDir(),
+		publicKey:    pub,
+		client:       http.DefaultClient,
+		checkEnabled: true,
+		now:          func() time.Time { return tl.now },
+	}
+	return tl
+}
+
+// sign returns a license file for lic signed with key
+func (tl *testLicenses) sign(key ed25519.PrivateKey, lic License) []byte {
+	tl.t.Helper()
+	payload, err := json.Marshal(lic)
+	if err != nil {
+		tl.t.Fatal(err)
+	}
+	data, err := json.Marshal(licenseFile{
+		License:   payload,
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(key, payload)),
+	})
+	if err != nil {
+		tl.t.Fatal(err)
+	}
+	return data
+}
+
+// install writes a license file as if copied by hand
+func (tl *testLicenses) install(data []byte) {
+	tl.t.Helper()
+	if err := os.WriteFile(filepath.Join(tl.svc.dir, licenseFileName), data, 0644); err != nil {
+		tl.t.Fatal(err)
+	}
+}
+
+func (tl *testLicenses) validate() *LicenseStatus {
+	tl.t.Helper()
+	status, err := tl.svc.ValidateLicense()
+	if err != nil {
+		tl.t.Fatal(err)
+	}
+	return status
+}
+
+// license is a pro license for this computer, valid for 30 days
+func (tl *testLicenses) license() License {
+	return License{
+		ID:        "LIC-1",
+		Customer:  "Baraka",
+		Machine:   MachineFingerprint(),
+		IssuedAt:  tl.now,
+		ExpiresAt: tl.now.Add(30 * 24 * time.Hour),
+		Tier:      TierPro,
+	}
+}
+
+func checkState(t *testing.T, status *LicenseStatus, state string, valid bool) {
+	t.Helper()
+	if status.State != state || status.IsValid != valid {
+		t.Fatalf("got state %q (valid %t), want %q (valid %t): %s", status.State, status.IsValid, state, valid, status.Message)
+	}
+}
+
+func TestValidateLicenseValid(t *testing.T) {
+	tl := newTestLicenses(t)
+	tl.install(tl.sign(tl.key, tl.license()))
+
+	status := tl.validate()
+	checkState(t, status, LicenseStateValid, true)
+	if status.Customer != "Baraka" || status.ExpiresAt != "2025-04-13" {
+		t.Errorf("got customer %q expiring %q", status.Customer, status.ExpiresAt)
+	}
+	if !status.Entitlements.Has(FeatureReceipts) || status.Entitlements.MaxUsers != 3 {
+		t.Errorf("got entitlements %+v, want the pro tier", status.Entitlements)
+	}
+}
+
+func TestValidateLicenseMissing(t *testing.T) {
+	tl := newTestLicenses(t)
+	checkState(t, tl.validate(), LicenseStateMissing, false)
+}
+
+func TestValidateLicenseExpired(t *testing.T) {
+	tl := newTestLicenses(t)
+	lic := tl.license()
+	lic.ExpiresAt = tl.now.Add(-time.Hour)
+	tl.install(tl.sign(tl.key, lic))
+	checkState(t, tl.validate(), LicenseStateExpired, false)
+}
+
+func TestValidateLicenseWrongMachine(t *testing.T) {
+	tl := newTestLicenses(t)
+	tl.install(tl.sign(tl.key, tl.license()))
+	checkState(t, tl.validate(), LicenseStateValid, true)
+
+	// a license for another computer gets no grace period
+	lic := tl.license()
+	lic.Machine = "0000-0000-0000-0000-0000"
+	tl.install(tl.sign(tl.key, lic))
+	checkState(t, tl.validate(), LicenseStateWrongMachine, false)
+}
+
+func TestValidateLicenseBadSignature(t *testing.T) {
+	tl := newTestLicenses(t)
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tl.install(tl.sign(otherKey, tl.license()))
+	checkState(t, tl.validate(), LicenseStateInvalid, false)
+
+	// a signed license edited afterwards
+	var file licenseFile
+	if err := json.Unmarshal(tl.sign(tl.key, tl.license()), &file); err != nil {
+		t.Fatal(err)
+	}
+	lic := tl.license()
+	lic.Tier = TierEnterprise
+	file.License, _ = json.Marshal(lic)
+	data, _ := json.Marshal(file)
+	tl.install(data)
+	checkState(t, tl.validate(), LicenseStateInvalid, false)
+}
+
+func TestValidateLicenseGracePeriod(t *testing.T) {
+	tl := newTestLicenses(t)
+	tl.install(tl.sign(tl.key, tl.license()))
+	checkState(t, tl.validate(), LicenseStateValid, true)
+
+	if err := os.Remove(filepath.Join(tl.svc.dir, licenseFileName)); err != nil {
+		t.Fatal(err)
+	}
+	tl.now = tl.now.Add(3 * 24 * time.Hour)
+	status := tl.validate()
+	checkState(t, status, LicenseStateGrace, true)
+	if status.GraceUntil != "2025-03-21" || !status.Entitlements.Has(FeatureReceipts) {
+		t.Errorf("got grace until %q with %+v", status.GraceUntil, status.Entitlements)
+	}
+
+	// the grace period counts from the last successful validation
+	tl.now = tl.now.Add(5 * 24 * time.Hour)
+	checkState(t, tl.validate(), LicenseStateMissing, false)
+}
+
+func TestValidateLicenseClockRollback(t *testing.T) {
+	tl := newTestLicenses(t)
+	tl.install(tl.sign(tl.key, tl.license()))
+	checkState(t, tl.validate(), LicenseStateValid, true)
+
+	// small corrections are tolerated
+	tl.now = tl.now.Add(-time.Hour)
+	checkState(t, tl.validate(), LicenseStateValid, true)
+
+	tl.now = tl.now.Add(-2 * 24 * time.Hour)
+	checkState(t, tl.validate(), LicenseStateClockRollback, false)
+}
+
+func TestLicenseStatusLocalize(t *testing.T) {
+	tl := newTestLicenses(t)
+	lic := tl.license()
+	lic.ExpiresAt = tl.now.Add(-time.Hour)
+	tl.install(tl.sign(tl.key, lic))
+
+	status := tl.validate()
+	if status.MessageKey != "expired" || status.Message != "انتهت صلاحية الترخيص في 2025-03-14." {
+		t.Errorf("got %q (%s) in the default locale", status.Message, status.MessageKey)
+	}
+	if got := status.Localize("fr").Message; got != "La licence a expiré le 2025-03-14." {
+		t.Errorf("got %q in French", got)
+	}
+	if status.Message != "انتهت صلاحية الترخيص في 2025-03-14." {
+		t.Error("Localize changed the status it was called on")
+	}
+}
+
+func TestInstallLicenseErrors(t *testing.T) {
+	tl := newTestLicenses(t)
+	lic := tl.license()
+	lic.Machine = "0000-0000-0000-0000-0000"
+	_, err := tl.svc.InstallLicense(tl.sign(tl.key, lic))
+	checkCode(t, err, apperr.CodeValidation)
+	var aerr *apperr.Error
+	if !errors.As(err, &aerr) || aerr.Key != "license_wrong_machine" || aerr.Params["machine"] != MachineFingerprint() {
+		t.Errorf("got %v, want license_wrong_machine with this computer's ID", err)
+	}
+
+	_, err = tl.svc.InstallLicense([]byte("not a license"))
+	if !errors.As(err, &aerr) || aerr.Key != "license_invalid" {
+		t.Errorf("got %v, want license_invalid", err)
+	}
+}
+
+func TestRefreshLicense(t *testing.T) {
+	tl := newTestLicenses(t)
+	tl.install(tl.sign(tl.key, tl.license()))
+
+	renewed := tl.license()
+	renewed.ID = "LIC-2"
+	renewed.ExpiresAt = tl.now.Add(365 * 24 * time.Hour)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("machine") != MachineFingerprint() || query.Get("license") != "LIC-1" || query.Get("customer") != "baraka" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(tl.sign(tl.key, renewed))
+	}))
+	defer server.Close()
+	// the configured URL may carry its own query parameters
+	tl.svc.SetServerURL(server.URL + "/license?customer=baraka")
+
+	status, err := tl.svc.RefreshLicense(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkState(t, status, LicenseStateValid, true)
+	if status.ExpiresAt != "2026-03-14" {
+		t.Errorf("got expiry %q, want the renewed license's", status.ExpiresAt)
+	}
+}
+
+func TestRefreshLicenseKeepsInstalledLicense(t *testing.T) {
+	tl := newTestLicenses(t)
+	tl.install(tl.sign(tl.key, tl.license()))
+
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	responses := []func(w http.ResponseWriter, r *http.Request){
+		func(w http.ResponseWriter, r *http.Request) { http.NotFound(w, r) },
+		func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusInternalServerError) },
+		func(w http.ResponseWriter, r *http.Request) { w.Write(tl.sign(otherKey, tl.license())) },
+	}
+	for _, respond := range responses {
+		server := httptest.NewServer(http.HandlerFunc(respond))
+		tl.svc.SetServerURL(server.URL)
+		if _, err := tl.svc.RefreshLicense(context.Background()); err == nil {
+			t.Error("want an error")
+		}
+		server.Close()
+		checkState(t, tl.validate(), LicenseStateValid, true)
+	}
+
+	tl.svc.SetServerURL("")
+	_, err = tl.svc.RefreshLicense(context.Background())
+	checkCode(t, err, apperr.CodeConflict)
+}
+
+func TestLicenseEntitlements(t *testing.T) {
+	tests := []struct {
+		name     string
+		tier     string
+		features []string
+		maxUsers int
+		want     []string
+		seats    int
+	}{
+		{"basic", TierBasic, nil, 0, nil, 1},
+		{"unknown tier", "gold", nil, 0, nil, 1},
+		{"pro", TierPro, nil, 0, []string{FeatureInvoices, FeatureReceipts, FeatureBatchExport, FeatureAuditLog}, 3},
+		{"enterprise", TierEnterprise, nil, 0, []string{FeatureInvoices, FeatureReceipts, FeatureBatchExport, FeatureAuditLog}, 0},
+		{"basic with extras", TierBasic, []string{FeatureAuditLog}, 5, []string{FeatureAuditLog}, 5},
+	}
+	all := []string{FeatureInvoices, FeatureReceipts, FeatureBatchExport, FeatureAuditLog}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := newTestLicenses(t)
+			lic := tl.license()
+			lic.Tier, lic.Features, lic.MaxUsers = tt.tier, tt.features, tt.maxUsers
+			tl.install(tl.sign(tl.key, lic))
+
+			for _, feature := range all {
+				want := false
+				for _, f := range tt.want {
+					want = want || f == feature
+				}
+				if got := tl.svc.HasFeature(feature); got != want {
+					t.Errorf("HasFeature(%q) = %t, want %t", feature, got, want)
+				}
+			}
+			if got := tl.svc.MaxUsers(); got != tt.seats {
+				t.Errorf("MaxUsers() = %d, want %d", got, tt.seats)
+			}
+		})
+	}
+}
+
+func TestLicenseEntitlementsWithoutLicense(t *testing.T) {
+	tl := newTestLicenses(t)
+	if tl.svc.HasFeature(FeatureInvoices) || tl.svc.MaxUsers() != 1 {
+		t.Errorf("got invoices %t and %d users without a license, want none and 1", tl.svc.HasFeature(FeatureInvoices), tl.svc.MaxUsers())
+	}
+
+	tl.svc.checkEnabled = false
+	tl.svc.last = nil
+	if !tl.svc.HasFeature(FeatureAuditLog) || tl.svc.MaxUsers() != 0 {
+		t.Error("want every feature and unlimited users with license checking disabled")
+	}
+}
+
+func TestLicenseEntitlementsCached(t *testing.T) {
+	tl := newTestLicenses(t)
+	if tl.svc.HasFeature(FeatureReceipts) {
+		t.Fatal("want no receipts before a license is installed")
+	}
+
+	// a license copied by hand is picked up once the last status is stale
+	tl.install(tl.sign(tl.key, tl.license()))
+	if tl.svc.HasFeature(FeatureReceipts) {
+		t.Error("want the cached status until statusTTL passes")
+	}
+	tl.now = tl.now.Add(statusTTL + time.Minute)
+	if !tl.svc.HasFeature(FeatureReceipts) {
+		t.Error("want receipts after revalidation")
+	}
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"strings"
+)
+
+// MachineFingerprint identifies this computer for license binding. It is a
+// hash of the OS machine ID (the host name when there is none), so the raw ID
+// is never shown or sent, formatted in groups to be read over the phone:
+// XXXX-XXXX-XXXX-XXXX-XXXX
+func MachineFingerprint() string {
+	id := machineID()
+	if id == "" {
+		id, _ = os.Hostname()
+	}
+	sum := sha256.Sum256([]byte("barakaERP:" + strings.ToLower(id)))
+	hexID := strings.ToUpper(hex.EncodeToString(sum[:10]))
+	groups := make([]string, 0, 5)
+	for i := 0; i < len(hexID); i += 4 {
+		groups = append(groups, hexID[i:i+4])
+	}
+	return strings.Join(groups, "-")
+}
@@ -0,0 +1,19 @@
+package services
+
+import (
+	"golang.org/x/sys/windows/registry"
+)
+
+// machineID returns the MachineGuid Windows assigns at installation
+func machineID() string {
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, `SOFTWARE\Microsoft\Cryptography`, registry.QUERY_VALUE|registry.WOW64_64KEY)
+	if err != nil {
+		return ""
+	}
+	defer key.Close()
+	guid, _, err := key.GetStringValue("MachineGuid")
+	if err != nil {
+		return ""
+	}
+	return guid
+}
@@ -2,51 +2,77 @@ package services
 
 import (
 	"context"
-	"fmt"
-	"log"
-	"os"
-	"strings"
+	"time"
 	"barakaERP/backend/db"
+	apperr "barakaERP/backend/domain/errors"
+)
+
+var (
+	errInvalidOrderID  = apperr.Validation("id", "invalid_order_id")               // Invalid order ID
+	errOrderNotFound   = apperr.NotFound("order_not_found")                        // Order not found
+	errInvoiceNotFound = apperr.NotFound("invoice_not_found")                      // Invoice not found
+	errInvalidDiscount = apperr.Validation("discount_percent", "invalid_discount") // Discount percentage must be between 0 and 100
 )
 
 // OrderService handles order-related business logic
 type OrderService struct {
-	repo *db.Repository
+	repo     *db.Repository
+	settings  *SettingsService
+	pricing   *PricingService
+	discounts *DiscountService
 }
 
 // NewOrderService creates a new order service
-func NewOrderService(repo *db.Repository) *OrderService {
-	return &OrderService{repo: repo}
+func NewOrderService(repo *db.Repository, settings *SettingsService, pricing *PricingService, discounts *DiscountService) *OrderService {
+	return &OrderService{repo: repo, settings: settings, pricing: pricing, discounts: discounts}
 }
 
-// Create creates a new order
+// Create creates a new order. Lines naming a product without a unit price
+// get the client's quote (see PricingService), and lines without a discount
+// the best matching discount rule (see DiscountService). Products with
+// variants cannot be ordered themselves, only one of their variants.
 func (s *OrderService) Create(ctx context.Context, draft db.OrderDraft) (*db.Order, error) {
-	// Lightweight debug hook
-	if v := os.Getenv("DEBUG_ORDERS"); v != "" && v != "0" && strings.ToLower(v) != "false" {
-		log.Printf("[orders] Create draft client=%d items=%d discount=%d", draft.ClientID, len(draft.Items), draft.DiscountPercent)
-	}
 	// Validate required fields
 	if draft.ClientID <= 0 {
-		return nil, fmt.Errorf("معرف العميل مطلوب") // Client ID is required
+		return nil, apperr.Validation("client_id", "client_required") // Client ID is required
 	}
 
 	if len(draft.Items) == 0 {
-		return nil, fmt.Errorf("يجب إضافة عنصر واحد على الأقل للطلب") // At least one item is required
+		return nil, apperr.Validation("items", "order_items_required") // At least one item is required
+	}
+
+	// Verify client exists and is not in the recycle bin
+	client, err := s.repo.GetClient(ctx, draft.ClientID)
+	if err != nil {
+		return nil, notFoundAs(err, errClientNotFound)
+	}
+	if client.DeletedAt != nil {
+		return nil, errClientNotFound
+	}
+
+	// Fill and check line prices from the client's price list
+	issueDate := time.Now()
+	if draft.IssueDate != nil {
+		issueDate = *draft.IssueDate
+	}
+	if err := s.snapshotOrderItems(ctx, draft.Items); err != nil {
+		return nil, err
+	}
+	if err := s.pricing.priceOrderItems(ctx, client, draft.Items, issueDate); err != nil {
+		return nil, err
+	}
+	if err := s.discounts.applyDiscounts(ctx, draft.Items, issueDate); err != nil {
+		return nil, err
 	}
 
 	// Validate items
-	for i, item := range draft.Items {
-		if item.Qty <= 0 {
-			return nil, fmt.Errorf("الكمية يجب أن تكون أكبر من صفر للعنصر %d", i+1) // Quantity must be greater than zero
-		}
-		if item.UnitPriceCents <= 0 {
-			return nil, fmt.Errorf("سعر الوحدة يجب أن يكون أكبر من صفر للعنصر %d", i+1) // Unit price must be greater than zero
-		}
-		if item.NameSnapshot == "" {
-			return nil, fmt.Errorf("اسم المنتج مطلوب للعنصر %d", i+1) // Product name is required
+	for i := range draft.Items {
+		item := &draft.Items[i]
+		if err := validateOrderItem(i, *item); err != nil {
+			return nil, err
 		}
 		if item.Currency == "" {
-			item.Currency = "USD" // Default currency
+			item.Currency = s.settings.DefaultCurrency()
 		}
 	}
 
@@ -54,11 +80,8 @@ func (s *OrderService) Create(ctx context.Context, draft db.OrderDraft) (*db.Ord
 	if draft.DiscountPercent < 0 || draft.DiscountPercent > 100 {
 		draft.DiscountPercent = 0
 	}
-
-	// Verify client exists
-	_, err := s.repo.GetClient(ctx, draft.ClientID)
-	if err != nil {
-		return nil, fmt.Errorf("العميل غير موجود") // Client not found
+	if err := s.discounts.checkOrderDiscount(ctx, draft.DiscountPercent); err != nil {
+		return nil, err
 	}
 
 	return s.repo.CreateOrder(ctx, draft)
@@ -66,63 +89,198 @@ func (s *OrderService) Create(ctx context.Context, draft db.OrderDraft) (*db.Ord
 
 // List retrieves orders with pagination and filters
 func (s *OrderService) List(ctx context.Context, filters db.OrderFilters, limit, offset int) ([]db.OrderDetail, int, error) {
-	if limit <= 0 {
-		limit = 20 // Default page size
-	}
-	if limit > 100 {
-		limit = 100 // Max page size
+	limit = s.settings.PageLimit(limit)
+
+	if err := validateOrderFilters(filters); err != nil {
+		return nil, 0, err
 	}
 
 	return s.repo.ListOrders(ctx, filters, limit, offset)
 }
 
+// ListAfter retrieves a page of orders after cursor, newest first (keyset pagination)
+func (s *OrderService) ListAfter(ctx context.Context, filters db.OrderFilters, cursor string, limit int) (*db.PaginatedResult[db.OrderDetail], error) {
+	limit = s.settings.PageLimit(limit)
+
+	if filters.Sort != nil && *filters.Sort != "" {
+		return nil, apperr.Validation("sort", "sort_with_cursor") // Sorting is not supported with cursor pagination
+	}
+	if err := validateOrderFilters(filters); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListOrdersAfter(ctx, filters, cursor, limit)
+}
+
+// validateOrderFilters checks sort keys, date formats and the total range
+func validateOrderFilters(filters db.OrderFilters) error {
+	if filters.Sort != nil && !db.ValidOrderSort(*filters.Sort) {
+		return apperr.Validation("sort", "invalid_sort") // Invalid sort field
+	}
+	dates := map[string]*string{
+		"issue_date_from": filters.IssueDateFrom,
+		"issue_date_to":   filters.IssueDateTo,
+		"due_date_from":   filters.DueDateFrom,
+		"due_date_to":     filters.DueDateTo,
+	}
+	for field, d := range dates {
+		if d == nil || *d == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", *d); err != nil {
+			return apperr.Validation(field, "invalid_date").With("value", *d) // Invalid date format
+		}
+	}
+	if filters.MinTotalCents != nil && filters.MaxTotalCents != nil && *filters.MinTotalCents > *filters.MaxTotalCents {
+		return apperr.Validation("min_total_cents", "invalid_total_range") // Min total exceeds max total
+	}
+	return nil
+}
+
+// snapshotOrderItems fills the name and SKU snapshots of lines naming a
+// product from that product, which is the chosen variant for products sold
+// in variants; lines naming the parent of live variants are rejected
+func (s *OrderService) snapshotOrderItems(ctx context.Context, items []db.OrderItemDraft) error {
+	for i := range items {
+		item := &items[i]
+		if item.ProductID == nil {
+			continue
+		}
+		product, err := s.repo.GetProduct(ctx, *item.ProductID)
+		if err != nil {
+			return apperr.From(notFoundAs(err, errProductNotFound)).With("item", i+1)
+		}
+		if product.ParentID == nil {
+			variants, err := s.repo.CountProductVariants(ctx, product.ID, false)
+			if err != nil {
+				return err
+			}
+			if variants > 0 {
+				return apperr.Validation("product_id", "product_variant_required").With("item", i+1) // Choose one of the product's variants
+			}
+		}
+		if item.NameSnapshot == "" {
+			item.NameSnapshot = product.Name
+		}
+		if item.SKUSnapshot == nil {
+			item.SKUSnapshot = product.SKU
+		}
+	}
+	return nil
+}
+
+// validateOrderItem checks line i of an order; errors name the line (1-based)
+// in their "item" parameter
+func validateOrderItem(i int, item db.OrderItemDraft) error {
+	if item.Qty <= 0 {
+		return apperr.Validation("qty", "invalid_item_quantity").With("item", i+1) // Quantity must be greater than zero
+	}
+	if item.UnitPriceCents <= 0 {
+		return apperr.Validation("unit_price_cents", "invalid_item_price").With("item", i+1) // Unit price must be greater than zero
+	}
+	if item.NameSnapshot == "" {
+		return apperr.Validation("name_snapshot", "item_name_required").With("item", i+1) // Product name is required
+	}
+	return nil
+}
+
 // Get retrieves an order by ID with details
 func (s *OrderService) Get(ctx context.Context, id int64) (*db.OrderDetail, error) {
 	if id <= 0 {
-		return nil, fmt.Errorf("معرف الطلب غير صحيح") // Invalid order ID
+		return nil, errInvalidOrderID
 	}
 
 	order, err := s.repo.GetOrderDetail(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("الطلب غير موجود") // Order not found
+		return nil, notFoundAs(err, errOrderNotFound)
 	}
 
 	return order, nil
 }
 
+// ResolveDocument finds the order behind a scanned document reference (an
+// order, or the order an invoice was issued for) and checks the printed issue
+// date and total against the stored document
+func (s *OrderService) ResolveDocument(ctx context.Context, kind, number, issueDate string, totalCents int64) (*db.ScannedDocument, error) {
+	doc := &db.ScannedDocument{Kind: kind, Number: number}
+	switch kind {
+	case "order":
+		id, err := s.repo.GetOrderIDByNumber(ctx, number)
+		if err != nil {
+			return nil, notFoundAs(err, errOrderNotFound)
+		}
+		if doc.Order, err = s.Get(ctx, id); err != nil {
+			return nil, err
+		}
+		doc.Current = doc.Order.Order.IssueDate.Format("2006-01-02") == issueDate && doc.Order.TotalCents == totalCents
+	case "invoice":
+		id, err := s.repo.GetInvoiceIDByNumber(ctx, number)
+		if err != nil {
+			return nil, notFoundAs(err, errInvoiceNotFound)
+		}
+		invoice, err := s.repo.GetInvoiceDetail(ctx, id)
+		if err != nil {
+			return nil, notFoundAs(err, errInvoiceNotFound)
+		}
+		if invoice.Invoice.OrderID == nil {
+			return nil, apperr.NotFound("invoice_without_order") // Invoice has no order
+		}
+		if doc.Order, err = s.Get(ctx, *invoice.Invoice.OrderID); err != nil {
+			return nil, err
+		}
+		doc.Current = invoice.Invoice.IssueDate.Format("2006-01-02") == issueDate && invoice.Invoice.TotalCents == totalCents
+	default:
+		return nil, apperr.Validation("kind", "unknown_document_kind") // Unknown document kind
+	}
+	return doc, nil
+}
+
 // Update updates an existing order
 func (s *OrderService) Update(ctx context.Context, update db.OrderUpdate) (*db.Order, error) {
 	if update.ID <= 0 {
-		return nil, fmt.Errorf("معرف الطلب مطلوب") // Order ID is required
+		return nil, errInvalidOrderID
 	}
 
 	// Check if order exists
-	_, err := s.repo.GetOrderDetail(ctx, update.ID)
+	existing, err := s.repo.GetOrderDetail(ctx, update.ID)
 	if err != nil {
-		return nil, fmt.Errorf("الطلب غير موجود") // Order not found
+		return nil, notFoundAs(err, errOrderNotFound)
 	}
 
 	// Validate items if provided
 	if len(update.Items) > 0 {
-		for i, item := range update.Items {
-			if item.Qty <= 0 {
-				return nil, fmt.Errorf("الكمية يجب أن تكون أكبر من صفر للعنصر %d", i+1) // Quantity must be greater than zero
-			}
-			if item.UnitPriceCents <= 0 {
-				return nil, fmt.Errorf("سعر الوحدة يجب أن يكون أكبر من صفر للعنصر %d", i+1) // Unit price must be greater than zero
-			}
-			if item.NameSnapshot == "" {
-				return nil, fmt.Errorf("اسم المنتج مطلوب للعنصر %d", i+1) // Product name is required
+		client, err := s.repo.GetClient(ctx, existing.Order.ClientID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.snapshotOrderItems(ctx, update.Items); err != nil {
+			return nil, err
+		}
+		if err := s.pricing.priceOrderItems(ctx, client, update.Items, existing.Order.IssueDate); err != nil {
+			return nil, err
+		}
+		if err := s.discounts.applyDiscounts(ctx, update.Items, existing.Order.IssueDate); err != nil {
+			return nil, err
+		}
+		for i := range update.Items {
+			item := &update.Items[i]
+			if err := validateOrderItem(i, *item); err != nil {
+				return nil, err
 			}
 			if item.Currency == "" {
-				item.Currency = "USD" // Default currency
+				item.Currency = s.settings.DefaultCurrency()
 			}
 		}
 	}
 
 	// Validate discount and tax percentages
 	if update.DiscountPercent != nil && (*update.DiscountPercent < 0 || *update.DiscountPercent > 100) {
-		return nil, fmt.Errorf("نسبة الخصم يجب أن تكون بين 0 و 100") // Discount percentage must be between 0 and 100
+		return nil, errInvalidDiscount
+	}
+	if update.DiscountPercent != nil {
+		if err := s.discounts.checkOrderDiscount(ctx, *update.DiscountPercent); err != nil {
+			return nil, err
+		}
 	}
 
 	return s.repo.UpdateOrder(ctx, update)
@@ -131,14 +289,14 @@ func (s *OrderService) Update(ctx context.Context, update db.OrderUpdate) (*db.O
 // Delete deletes an order (soft delete by setting status to CANCELED)
 func (s *OrderService) Delete(ctx context.Context, id int64) error {
 	if id <= 0 {
-		return fmt.Errorf("معرف الطلب غير صحيح") // Invalid order ID
+		return errInvalidOrderID
 	}
 
 	// Check if order exists to validate status
 	orderDetail, err := s.repo.GetOrderDetail(ctx, id)
-	if err != nil { return fmt.Errorf("الطلب غير موجود") }
+	if err != nil { return notFoundAs(err, errOrderNotFound) }
 	if orderDetail.Order.Status == db.OrderStatusCompleted {
-		return fmt.Errorf("لا يمكن حذف طلب مكتمل")
+		return apperr.Conflict("order_completed")
 	}
 
 	_, errAdj := s.repo.CancelOrderAndAdjustDebt(ctx, id)
@@ -0,0 +1,292 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+	"barakaERP/backend/db"
+	apperr "barakaERP/backend/domain/errors"
+)
+
+var (
+	errInvalidPriceListID    = apperr.Validation("id", "invalid_price_list_id")        // Invalid price list ID
+	errPriceListNotFound     = apperr.NotFound("price_list_not_found")                 // Price list not found
+	errPriceListNameRequired = apperr.Validation("name", "price_list_name_required")   // Price list name is required
+	errInvalidPriceValidity  = apperr.Validation("valid_to", "invalid_price_validity") // Validity ends before it starts
+	errInvalidQuantity       = apperr.Validation("qty", "invalid_quantity")            // Quantity must be greater than zero
+)
+
+// PricingService manages price lists and quotes the price a client pays for
+// a product: the price in the client's default price list valid today, or
+// the product's own price when the client has no list or the list has no
+// price for it.
+type PricingService struct {
+	repo     *db.Repository
+	settings *SettingsService
+}
+
+// NewPricingService creates a new pricing service
+func NewPricingService(repo *db.Repository, settings *SettingsService) *PricingService {
+	return &PricingService{repo: repo, settings: settings}
+}
+
+// ListPriceLists retrieves all price lists
+func (s *PricingService) ListPriceLists(ctx context.Context) ([]db.PriceList, error) {
+	return s.repo.ListPriceLists(ctx)
+}
+
+// GetPriceList retrieves a price list with its product prices
+func (s *PricingService) GetPriceList(ctx context.Context, id int64) (*db.PriceListDetail, error) {
+	if id <= 0 {
+		return nil, errInvalidPriceListID
+	}
+	list, err := s.repo.GetPriceListDetail(ctx, id)
+	if err != nil {
+		return nil, notFoundAs(err, errPriceListNotFound)
+	}
+	return list, nil
+}
+
+// CreatePriceList creates a price list (admins only)
+func (s *PricingService) CreatePriceList(ctx context.Context, list db.PriceList) (*db.PriceList, error) {
+	if !db.ActorFromContext(ctx).IsAdmin() {
+		return nil, errAdminOnly
+	}
+	if err := s.normalizePriceList(&list); err != nil {
+		return nil, err
+	}
+	return s.repo.CreatePriceList(ctx, list)
+}
+
+// UpdatePriceList renames a price list or changes its currency (admins only)
+func (s *PricingService) UpdatePriceList(ctx context.Context, list db.PriceList) (*db.PriceList, error) {
+	if !db.ActorFromContext(ctx).IsAdmin() {
+		return nil, errAdminOnly
+	}
+	if list.ID <= 0 {
+		return nil, errInvalidPriceListID
+	}
+	if err := s.normalizePriceList(&list); err != nil {
+		return nil, err
+	}
+	updated, err := s.repo.UpdatePriceList(ctx, list)
+	if err != nil {
+		return nil, notFoundAs(err, errPriceListNotFound)
+	}
+	return updated, nil
+}
+
+// DeletePriceList removes a price list and its prices (admins only)
+func (s *PricingService) DeletePriceList(ctx context.Context, id int64) error {
+	if !db.ActorFromContext(ctx).IsAdmin() {
+		return errAdminOnly
+	}
+	if id <= 0 {
+		return errInvalidPriceListID
+	}
+	return notFoundAs(s.repo.DeletePriceList(ctx, id), errPriceListNotFound)
+}
+
+func (s *PricingService) normalizePriceList(list *db.PriceList) error {
+	list.Name = strings.TrimSpace(list.Name)
+	if list.Name == "" {
+		return errPriceListNameRequired
+	}
+	list.Currency = strings.ToUpper(strings.TrimSpace(list.Currency))
+	if list.Currency == "" {
+		list.Currency = s.settings.DefaultCurrency()
+	}
+	if !currencyCode.MatchString(list.Currency) {
+		return apperr.Validation("currency", "invalid_currency") // Currency must be a 3-letter code
+	}
+	return nil
+}
+
+// SetProductPrice adds a product price to a price list, or updates it when
+// price.ID is set (admins only). Empty validity dates leave that side open.
+func (s *PricingService) SetProductPrice(ctx context.Context, price db.ProductPrice) (*db.ProductPrice, error) {
+	if !db.ActorFromContext(ctx).IsAdmin() {
+		return nil, errAdminOnly
+	}
+	if price.PriceListID <= 0 {
+		return nil, errInvalidPriceListID
+	}
+	if _, err := s.repo.GetPriceList(ctx, price.PriceListID); err != nil {
+		return nil, notFoundAs(err, errPriceListNotFound)
+	}
+	if price.ProductID <= 0 {
+		return nil, errInvalidProductID
+	}
+	product, err := s.repo.GetProduct(ctx, price.ProductID)
+	if err != nil {
+		return nil, notFoundAs(err, errProductNotFound)
+	}
+	if product.DeletedAt != nil {
+		return nil, errProductNotFound
+	}
+	if err := db.ValidatePrice(price.UnitPriceCents); err != nil {
+		return nil, errInvalidPrice.Wrap(err)
+	}
+
+	dates := []struct {
+		field string
+		value **string
+	}{{"valid_from", &price.ValidFrom}, {"valid_to", &price.ValidTo}}
+	for _, d := range dates {
+		if *d.value == nil {
+			continue
+		}
+		v := strings.TrimSpace(**d.value)
+		if v == "" {
+			*d.value = nil
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", v); err != nil {
+			return nil, apperr.Validation(d.field, "invalid_date").With("value", v) // Invalid date format
+		}
+		*d.value = &v
+	}
+	if price.ValidFrom != nil && price.ValidTo != nil && *price.ValidTo < *price.ValidFrom {
+		return nil, errInvalidPriceValidity
+	}
+
+	return s.repo.SaveProductPrice(ctx, price)
+}
+
+// DeleteProductPrice removes a product price from its list (admins only)
+func (s *PricingService) DeleteProductPrice(ctx context.Context, id int64) error {
+	if !db.ActorFromContext(ctx).IsAdmin() {
+		return errAdminOnly
+	}
+	if id <= 0 {
+		return apperr.Validation("id", "invalid_product_price_id") // Invalid product price ID
+	}
+	return s.repo.DeleteProductPrice(ctx, id)
+}
+
+// SetClientPriceList sets the default price list of a client; nil goes back
+// to the products' own prices (admins only)
+func (s *PricingService) SetClientPriceList(ctx context.Context, clientID int64, priceListID *int64) (*db.Client, error) {
+	if !db.ActorFromContext(ctx).IsAdmin() {
+		return nil, errAdminOnly
+	}
+	if clientID <= 0 {
+		return nil, errInvalidClientID
+	}
+	if priceListID != nil && *priceListID <= 0 {
+		return nil, errInvalidPriceListID
+	}
+	// Not-found errors name the missing client or price list
+	return s.repo.SetClientPriceList(ctx, clientID, priceListID)
+}
+
+// Quote returns the price the client pays today for qty of the product in
+// unit (its base unit when empty)
+func (s *PricingService) Quote(ctx context.Context, clientID, productID int64, qty db.Quantity, unit string) (*db.PriceQuote, error) {
+	if clientID <= 0 {
+		return nil, errInvalidClientID
+	}
+	if productID <= 0 {
+		return nil, errInvalidProductID
+	}
+	if qty <= 0 {
+		return nil, errInvalidQuantity
+	}
+	client, err := s.repo.GetClient(ctx, clientID)
+	if err != nil {
+		return nil, notFoundAs(err, errClientNotFound)
+	}
+	if client.DeletedAt != nil {
+		return nil, errClientNotFound
+	}
+	return s.quote(ctx, client, productID, qty, unit, time.Now())
+}
+
+// quote prices qty of a product in unit for client on date. Prices are kept
+// per base unit; a packaging unit costs its factor times as much.
+func (s *PricingService) quote(ctx context.Context, client *db.Client, productID int64, qty db.Quantity, unit string, date time.Time) (*db.PriceQuote, error) {
+	product, err := s.repo.GetProduct(ctx, productID)
+	if err != nil {
+		return nil, notFoundAs(err, errProductNotFound)
+	}
+	if product.DeletedAt != nil {
+		return nil, errProductNotFound
+	}
+	factor, err := s.repo.UnitFactor(ctx, product, unit)
+	if err != nil {
+		return nil, err
+	}
+	if unit == "" {
+		unit = product.Unit
+	}
+
+	q := &db.PriceQuote{
+		ClientID:       client.ID,
+		ProductID:      productID,
+		Qty:            qty,
+		Unit:           unit,
+		UnitPriceCents: product.UnitPriceCents,
+		Currency:       product.Currency,
+	}
+	if client.PriceListID != nil {
+		list, err := s.repo.GetPriceList(ctx, *client.PriceListID)
+		if err != nil {
+			return nil, err
+		}
+		price, err := s.repo.FindProductPrice(ctx, list.ID, productID, date.Format("2006-01-02"))
+		if err != nil {
+			return nil, err
+		}
+		if price != nil {
+			q.UnitPriceCents = price.UnitPriceCents
+			q.Currency = list.Currency
+			q.PriceListID = &list.ID
+			q.PriceListName = &list.Name
+		}
+	}
+	q.UnitPriceCents = factor.Amount(q.UnitPriceCents)
+	q.TotalCents = qty.Amount(q.UnitPriceCents)
+	return q, nil
+}
+
+// priceOrderItems fills the lines of an order for client dated date that
+// name a product but no price with the client's quote, and lines without a
+// unit with the product's unit. With EnforceListPrices set, lines entered by
+// other users than admins must also match the quote. Lines without a product
+// only get the default unit.
+func (s *PricingService) priceOrderItems(ctx context.Context, client *db.Client, items []db.OrderItemDraft, date time.Time) error {
+	enforce := s.settings.Get().EnforceListPrices && !db.ActorFromContext(ctx).IsAdmin()
+	for i := range items {
+		item := &items[i]
+		item.Unit = strings.ToLower(strings.TrimSpace(item.Unit))
+		if item.ProductID == nil {
+			if item.Unit == "" {
+				item.Unit = db.DefaultUnit
+			}
+			continue
+		}
+		qty := item.Qty
+		if qty <= 0 {
+			qty = db.Units(1) // reported by the item validation
+		}
+		q, err := s.quote(ctx, client, *item.ProductID, qty, item.Unit, date)
+		if err != nil {
+			return apperr.From(err).With("item", i+1)
+		}
+		item.Unit = q.Unit
+		if item.UnitPriceCents > 0 && !enforce {
+			continue
+		}
+		if item.UnitPriceCents == 0 {
+			item.UnitPriceCents = q.UnitPriceCents
+			item.Currency = q.Currency
+			continue
+		}
+		if item.UnitPriceCents != q.UnitPriceCents || (item.Currency != "" && item.Currency != q.Currency) {
+			return apperr.Validation("unit_price_cents", "item_price_mismatch").
+				With("item", i+1).
+				With("expected", db.FormatCents(q.UnitPriceCents)+" "+q.Currency) // Price differs from the client's price list
+		}
+	}
+	return nil
+}
@@ -2,35 +2,61 @@ package services
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
 	"barakaERP/backend/db"
+	apperr "barakaERP/backend/domain/errors"
 )
 
+var (
+	errProductNameRequired = apperr.Validation("name", "product_name_required")       // Product name is required
+	errInvalidPrice        = apperr.Validation("unit_price_cents", "invalid_price")   // Invalid price
+	errInvalidProductID    = apperr.Validation("id", "invalid_product_id")            // Invalid product ID
+	errProductNotFound     = apperr.NotFound("product_not_found")                     // Product not found
+	errProductInUse        = apperr.Conflict("product_in_use")                        // Product used by active orders
+	errInvalidUnit         = apperr.Validation("unit", "invalid_unit")                // Invalid unit of measure
+	errInvalidStock        = apperr.Validation("stock", "invalid_stock")              // Stock cannot be negative
+	errInvalidParent       = apperr.Validation("parent_id", "invalid_variant_parent") // Variants belong to a live product that is not a variant
+	errProductHasVariants  = apperr.Conflict("product_has_variants")                  // Remove the product's variants first
+)
+
+// unitCode matches units of measure: lowercase codes such as piece, kg, m or carton
+var unitCode = regexp.MustCompile(`^[a-z][a-z0-9_]{0,19}$`)
+
 // ProductService handles product-related business logic
 type ProductService struct {
-	repo *db.Repository
+	repo     *db.Repository
+	settings *SettingsService
 }
 
 // NewProductService creates a new product service
-func NewProductService(repo *db.Repository) *ProductService {
-	return &ProductService{repo: repo}
+func NewProductService(repo *db.Repository, settings *SettingsService) *ProductService {
+	return &ProductService{repo: repo, settings: settings}
 }
 
-// Create creates a new product
+// Create creates a new product, or a variant of product.ParentID
 func (s *ProductService) Create(ctx context.Context, product db.Product) (*db.Product, error) {
+	if product.ParentID != nil {
+		if err := s.inheritParent(ctx, &product); err != nil {
+			return nil, err
+		}
+	}
+
 	// Validate required fields
 	if product.Name == "" {
-		return nil, fmt.Errorf("اسم المنتج مطلوب") // Product name is required
+		return nil, errProductNameRequired
 	}
 
 	// Validate price
 	if err := db.ValidatePrice(product.UnitPriceCents); err != nil {
-		return nil, fmt.Errorf("السعر غير صحيح: %v", err) // Invalid price
+		return nil, errInvalidPrice.Wrap(err)
 	}
 
 	// Set default currency if not provided
 	if product.Currency == "" {
-		product.Currency = "DZD"
+		product.Currency = s.settings.DefaultCurrency()
 	}
 
 	// Set default active status
@@ -38,30 +64,137 @@ func (s *ProductService) Create(ctx context.Context, product db.Product) (*db.Pr
 		product.Active = true
 	}
 
+	if err := s.checkCategory(ctx, product.CategoryID); err != nil {
+		return nil, err
+	}
+	if err := normalizeUnit(&product.Unit); err != nil {
+		return nil, err
+	}
+	if product.Stock < 0 {
+		return nil, errInvalidStock
+	}
+	product.Attributes = normalizeAttributes(product.Attributes)
+
 	return s.repo.CreateProduct(ctx, product)
 }
 
-// List retrieves products with pagination and search
-func (s *ProductService) List(ctx context.Context, query string, active *bool, limit, offset int) ([]db.Product, int, error) {
-	if limit <= 0 {
-		limit = 20 // Default page size
+// inheritParent prepares a new variant: its parent must be a live product
+// that is not itself a variant. The variant takes the parent's category, and
+// its unit, price and description unless it has its own; without a name it is
+// named after the parent and its attribute values, e.g. "Pot (30 cm, red)".
+func (s *ProductService) inheritParent(ctx context.Context, variant *db.Product) error {
+	if *variant.ParentID <= 0 {
+		return errInvalidParent
+	}
+	parent, err := s.repo.GetProduct(ctx, *variant.ParentID)
+	if err != nil {
+		return notFoundAs(err, errInvalidParent)
+	}
+	if parent.DeletedAt != nil || parent.ParentID != nil {
+		return errInvalidParent
+	}
+
+	variant.CategoryID = parent.CategoryID
+	if variant.Unit == "" {
+		variant.Unit = parent.Unit
+	}
+	if variant.UnitPriceCents == 0 {
+		variant.UnitPriceCents = parent.UnitPriceCents
+		variant.Currency = parent.Currency
+	}
+	if variant.Description == nil {
+		variant.Description = parent.Description
+	}
+	variant.Attributes = normalizeAttributes(variant.Attributes)
+	if strings.TrimSpace(variant.Name) == "" {
+		variant.Name = variantName(parent.Name, variant.Attributes)
+	}
+	return nil
+}
+
+// normalizeAttributes trims attribute names and values, dropping empty ones
+func normalizeAttributes(attributes db.Attributes) db.Attributes {
+	normalized := db.Attributes{}
+	for name, value := range attributes {
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if name != "" && value != "" {
+			normalized[name] = value
+		}
+	}
+	return normalized
+}
+
+// variantName names a variant after its parent and its attribute values,
+// ordered by attribute name
+func variantName(parentName string, attributes db.Attributes) string {
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return parentName
+	}
+	sort.Strings(names)
+	values := make([]string, len(names))
+	for i, name := range names {
+		values[i] = attributes[name]
+	}
+	return parentName + " (" + strings.Join(values, ", ") + ")"
+}
+
+// List retrieves products with pagination and search, limited to a category
+// and its subcategories when categoryID is set
+func (s *ProductService) List(ctx context.Context, query string, active *bool, categoryID *int64, limit, offset int) ([]db.Product, int, error) {
+	limit = s.settings.PageLimit(limit)
+	if categoryID != nil && *categoryID <= 0 {
+		return nil, 0, errInvalidCategoryID
+	}
+
+	return s.repo.ListProducts(ctx, query, active, categoryID, limit, offset)
+}
+
+// ListAfter retrieves a page of products after cursor (keyset pagination),
+// filtered as in List
+func (s *ProductService) ListAfter(ctx context.Context, query string, active *bool, categoryID *int64, cursor string, limit int) (*db.PaginatedResult[db.Product], error) {
+	limit = s.settings.PageLimit(limit)
+	if categoryID != nil && *categoryID <= 0 {
+		return nil, errInvalidCategoryID
 	}
-	if limit > 100 {
-		limit = 100 // Max page size
+
+	return s.repo.ListProductsAfter(ctx, query, active, categoryID, cursor, limit)
+}
+
+// ListVariants retrieves the variants of a product
+func (s *ProductService) ListVariants(ctx context.Context, parentID int64) ([]db.Product, error) {
+	if parentID <= 0 {
+		return nil, errInvalidProductID
 	}
+	return s.repo.ListProductVariants(ctx, parentID)
+}
 
-	return s.repo.ListProducts(ctx, query, active, limit, offset)
+// SetCategory moves a product and its variants to a category, or out of any
+// category when categoryID is nil
+func (s *ProductService) SetCategory(ctx context.Context, id int64, categoryID *int64) (*db.Product, error) {
+	product, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if product.ParentID != nil {
+		return nil, apperr.Validation("category_id", "variant_category") // Variants take their parent's category
+	}
+	product.CategoryID = categoryID
+	return s.Update(ctx, *product)
 }
 
 // Get retrieves a product by ID
 func (s *ProductService) Get(ctx context.Context, id int64) (*db.Product, error) {
 	if id <= 0 {
-		return nil, fmt.Errorf("معرف المنتج غير صحيح") // Invalid product ID
+		return nil, errInvalidProductID
 	}
 
 	product, err := s.repo.GetProduct(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("المنتج غير موجود") // Product not found
+		return nil, notFoundAs(err, errProductNotFound)
 	}
 
 	return product, nil
@@ -71,46 +204,182 @@ func (s *ProductService) Get(ctx context.Context, id int64) (*db.Product, error)
 func (s *ProductService) Update(ctx context.Context, product db.Product) (*db.Product, error) {
 	// Validate required fields
 	if product.ID <= 0 {
-		return nil, fmt.Errorf("معرف المنتج مطلوب") // Product ID is required
+		return nil, errInvalidProductID
 	}
 	if product.Name == "" {
-		return nil, fmt.Errorf("اسم المنتج مطلوب") // Product name is required
+		return nil, errProductNameRequired
 	}
 
 	// Validate price
 	if err := db.ValidatePrice(product.UnitPriceCents); err != nil {
-		return nil, fmt.Errorf("السعر غير صحيح: %v", err) // Invalid price
+		return nil, errInvalidPrice.Wrap(err)
 	}
 
 	// Set default currency if not provided
 	if product.Currency == "" {
-		product.Currency = "DZD"
+		product.Currency = s.settings.DefaultCurrency()
 	}
 
 	// Check if product exists
-	_, err := s.repo.GetProduct(ctx, product.ID)
+	existing, err := s.repo.GetProduct(ctx, product.ID)
 	if err != nil {
-		return nil, fmt.Errorf("المنتج غير موجود") // Product not found
+		return nil, notFoundAs(err, errProductNotFound)
+	}
+	if existing.DeletedAt != nil {
+		return nil, errProductNotFound
+	}
+	product.ParentID = existing.ParentID // set once, when a variant is created
+	if product.ParentID != nil {
+		product.CategoryID = existing.CategoryID
 	}
 
+	if err := s.checkCategory(ctx, product.CategoryID); err != nil {
+		return nil, err
+	}
+	if product.Unit == "" {
+		product.Unit = existing.Unit // callers unaware of units keep it
+	}
+	if err := normalizeUnit(&product.Unit); err != nil {
+		return nil, err
+	}
+	if product.Stock < 0 {
+		return nil, errInvalidStock
+	}
+	product.Attributes = normalizeAttributes(product.Attributes)
+
 	return s.repo.UpdateProduct(ctx, product)
 }
 
-// Delete removes a product
+// normalizeUnit lowercases a unit of measure, defaulting to a piece
+func normalizeUnit(unit *string) error {
+	*unit = strings.ToLower(strings.TrimSpace(*unit))
+	if *unit == "" {
+		*unit = db.DefaultUnit
+	}
+	if !unitCode.MatchString(*unit) {
+		return errInvalidUnit.With("unit", *unit)
+	}
+	return nil
+}
+
+// ListUnits retrieves the packaging units a product is also sold in
+func (s *ProductService) ListUnits(ctx context.Context, productID int64) ([]db.ProductUnit, error) {
+	if productID <= 0 {
+		return nil, errInvalidProductID
+	}
+	return s.repo.ListProductUnits(ctx, productID)
+}
+
+// SaveUnit adds a packaging unit to a product, e.g. a carton holding 12 of
+// its base unit, or updates it when unit.ID is set
+func (s *ProductService) SaveUnit(ctx context.Context, unit db.ProductUnit) (*db.ProductUnit, error) {
+	if unit.ProductID <= 0 {
+		return nil, errInvalidProductID
+	}
+	product, err := s.repo.GetProduct(ctx, unit.ProductID)
+	if err != nil {
+		return nil, notFoundAs(err, errProductNotFound)
+	}
+	if product.DeletedAt != nil {
+		return nil, errProductNotFound
+	}
+	if err := normalizeUnit(&unit.Unit); err != nil {
+		return nil, err
+	}
+	if unit.Unit == product.Unit {
+		return nil, apperr.Conflict("product_unit_taken") // The product's base unit
+	}
+	if unit.Factor <= 0 {
+		return nil, apperr.Validation("factor", "invalid_unit_factor") // Factor must be greater than zero
+	}
+	return s.repo.SaveProductUnit(ctx, unit)
+}
+
+// DeleteUnit removes a packaging unit from a product
+func (s *ProductService) DeleteUnit(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return apperr.Validation("id", "invalid_product_unit_id") // Invalid product unit ID
+	}
+	return s.repo.DeleteProductUnit(ctx, id)
+}
+
+// checkCategory verifies that a product's category, if any, exists
+func (s *ProductService) checkCategory(ctx context.Context, categoryID *int64) error {
+	if categoryID == nil {
+		return nil
+	}
+	if *categoryID <= 0 {
+		return errInvalidCategoryID
+	}
+	if _, err := s.repo.GetCategory(ctx, *categoryID); err != nil {
+		return notFoundAs(err, errCategoryNotFound)
+	}
+	return nil
+}
+
+// Delete moves a product to the recycle bin
 func (s *ProductService) Delete(ctx context.Context, id int64) error {
-	if id <= 0 { return fmt.Errorf("معرف المنتج غير صحيح") }
-	if _, err := s.repo.GetProduct(ctx, id); err != nil { return fmt.Errorf("المنتج غير موجود") }
+	if id <= 0 { return errInvalidProductID }
+	product, err := s.repo.GetProduct(ctx, id)
+	if err != nil { return notFoundAs(err, errProductNotFound) }
+	if product.DeletedAt != nil { return errProductNotFound }
+	variants, err := s.repo.CountProductVariants(ctx, id, false)
+	if err != nil { return err }
+	if variants > 0 { return errProductHasVariants }
+	// Order lines keep name/sku snapshots, so soft deletion never affects existing orders
+	return s.repo.DeleteProduct(ctx, id)
+}
+
+// ListDeleted retrieves products in the recycle bin
+func (s *ProductService) ListDeleted(ctx context.Context, query string, limit, offset int) ([]db.Product, int, error) {
+	limit = s.settings.PageLimit(limit)
+
+	return s.repo.ListDeletedProducts(ctx, query, limit, offset)
+}
+
+// Restore brings a product back from the recycle bin
+func (s *ProductService) Restore(ctx context.Context, id int64) (*db.Product, error) {
+	if id <= 0 { return nil, errInvalidProductID }
+	product, err := s.repo.GetProduct(ctx, id)
+	if err != nil { return nil, notFoundAs(err, errProductNotFound) }
+	if product.ParentID != nil {
+		parent, err := s.repo.GetProduct(ctx, *product.ParentID)
+		if err != nil { return nil, err }
+		if parent.DeletedAt != nil {
+			return nil, apperr.Conflict("product_parent_deleted") // Restore the parent product first
+		}
+	}
+	if err := s.repo.RestoreProduct(ctx, id); err != nil { return nil, err }
+	return s.repo.GetProduct(ctx, id)
+}
+
+// Purge permanently removes a product from the recycle bin (admins only)
+func (s *ProductService) Purge(ctx context.Context, id int64) error {
+	if !db.ActorFromContext(ctx).IsAdmin() {
+		return errAdminOnly
+	}
+	if id <= 0 { return errInvalidProductID }
+	product, err := s.repo.GetProduct(ctx, id)
+	if err != nil { return notFoundAs(err, errProductNotFound) }
+	if product.DeletedAt == nil {
+		return apperr.Conflict("product_not_deleted") // Must be in recycle bin first
+	}
+	variants, err := s.repo.CountProductVariants(ctx, id, true)
+	if err != nil { return err }
+	if variants > 0 { return errProductHasVariants }
 	// Check usage
-	total, active, err := s.repo.ProductOrderUsageStats(ctx, id)
-	if err != nil { return fmt.Errorf("تعذر التحقق من استخدام المنتج: %v", err) }
+	_, active, err := s.repo.ProductOrderUsageStats(ctx, id)
+	if err != nil { return err }
 	if active > 0 {
-		return fmt.Errorf("لا يمكن حذف المنتج لوجود طلبات غير ملغاة تستخدمه")
+		return errProductInUse
+	}
+	// Order items keep their name/sku snapshots; product_id is set NULL by the FK.
+	if err := s.repo.PurgeProduct(ctx, id); err != nil {
+		if errors.Is(err, apperr.ErrConflict) {
+			return errProductInUse.Wrap(err)
+		}
+		return err
 	}
-	// If only canceled orders reference it, we can safely delete the product without touching historical canceled rows.
-	// (Canceled orders/items will retain name/sku snapshots; product_id FK may prevent delete if still enforced.)
-	// If FK constraints block, user must purge canceled orders first; we could add automatic purge later.
-	if err := s.repo.DeleteProduct(ctx, id); err != nil { return err }
-	_ = total // (Could be logged if needed)
 	return nil
 }
 
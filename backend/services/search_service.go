@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"barakaERP/backend/db"
+)
+
+// SearchService handles global search across clients, products and orders
+type SearchService struct {
+	repo     *db.Repository
+	settings *SettingsService
+}
+
+// NewSearchService creates a new search service
+func NewSearchService(repo *db.Repository, settings *SettingsService) *SearchService {
+	return &SearchService{repo: repo, settings: settings}
+}
+
+// GlobalSearch returns the best matching clients, products and orders for q
+func (s *SearchService) GlobalSearch(ctx context.Context, q string, limit int) ([]db.SearchResult, error) {
+	if strings.TrimSpace(q) == "" {
+		return []db.SearchResult{}, nil
+	}
+	limit = s.settings.PageLimit(limit)
+
+	return s.repo.GlobalSearch(ctx, q, limit)
+}
+
+// Rebuild repopulates the search index from scratch
+func (s *SearchService) Rebuild(ctx context.Context) error {
+	return s.repo.RebuildSearchIndex(ctx)
+}
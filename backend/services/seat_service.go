@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"barakaERP/backend/db"
+	apperr "barakaERP/backend/domain/errors"
+)
+
+var errUserNameRequired = apperr.Validation("name", "user_name_required") // User name is required
+
+// SeatService hands out the licensed seats. A named user takes a seat the
+// first time they sign in and keeps it until an admin releases it.
+type SeatService struct {
+	repo    *db.Repository
+	license *LicenseService
+}
+
+// NewSeatService creates a new seat service
+func NewSeatService(repo *db.Repository, license *LicenseService) *SeatService {
+	return &SeatService{repo: repo, license: license}
+}
+
+// Claim gives user a seat unless all licensed seats are taken; a user who
+// already holds one always succeeds
+func (s *SeatService) Claim(ctx context.Context, user string) error {
+	user = strings.TrimSpace(user)
+	if user == "" {
+		return errUserNameRequired
+	}
+	limit := s.license.MaxUsers()
+	ok, err := s.repo.ClaimSeat(ctx, user, limit)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return apperr.Forbidden("user_limit_reached").With("max", limit) // Licensed user limit reached
+	}
+	return nil
+}
+
+// List retrieves the users holding a seat
+func (s *SeatService) List(ctx context.Context) ([]db.LicensedUser, error) {
+	return s.repo.ListLicensedUsers(ctx)
+}
+
+// Release frees the seat of a user who no longer uses the application (admins only)
+func (s *SeatService) Release(ctx context.Context, user string) error {
+	if !db.ActorFromContext(ctx).IsAdmin() {
+		return errAdminOnly
+	}
+	return s.repo.ReleaseSeat(ctx, strings.TrimSpace(user))
+}
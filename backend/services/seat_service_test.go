@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"barakaERP/backend/db"
+	apperr "barakaERP/backend/domain/errors"
+)
+
+// newTestRepository returns a repository over an empty database
+func newTestRepository(t *testing.T) *db.Repository {
+	t.Helper()
+	conn, err := db.Connect(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	if err := conn.ApplySchemaFile(filepath.Join("..", "db", "schema.sql")); err != nil {
+		t.Fatal(err)
+	}
+	return db.NewRepository(conn)
+}
+
+// newTestSeats returns a seat service over an empty database, licensed for
+// maxUsers users (0 for unlimited)
+func newTestSeats(t *testing.T, maxUsers int) *SeatService {
+	t.Helper()
+	tl := newTestLicenses(t)
+	lic := tl.license()
+	lic.Tier, lic.MaxUsers = TierEnterprise, maxUsers
+	tl.install(tl.sign(tl.key, lic))
+	return NewSeatService(newTestRepository(t), tl.svc)
+}
+
+func checkCode(t *testing.T, err error, code apperr.Code) {
+	t.Helper()
+	var appErr *apperr.Error
+	if !errors.As(err, &appErr) || appErr.Code != code {
+		t.Fatalf("got error %v, want code %q", err, code)
+	}
+}
+
+func TestSeatClaim(t *testing.T) {
+	seats := newTestSeats(t, 2)
+	ctx := context.Background()
+
+	for _, user := range []string{"amina", "karim", "amina", " karim "} {
+		if err := seats.Claim(ctx, user); err != nil {
+			t.Fatalf("Claim(%q): %v", user, err)
+		}
+	}
+	checkCode(t, seats.Claim(ctx, "yacine"), apperr.CodeForbidden)
+	checkCode(t, seats.Claim(ctx, " "), apperr.CodeValidation)
+
+	users, err := seats.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 || users[0].Name != "amina" || users[1].Name != "karim" {
+		t.Errorf("got users %+v, want amina and karim", users)
+	}
+}
+
+func TestSeatClaimUnlimited(t *testing.T) {
+	seats := newTestSeats(t, 0)
+	for i := 0; i < 20; i++ {
+		if err := seats.Claim(context.Background(), string(rune('a'+i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSeatRelease(t *testing.T) {
+	seats := newTestSeats(t, 1)
+	ctx := context.Background()
+	admin := db.WithActor(ctx, db.Actor{User: "admin", Role: db.RoleAdmin})
+	cashier := db.WithActor(ctx, db.Actor{User: "amina", Role: db.RoleCashier})
+
+	if err := seats.Claim(ctx, "amina"); err != nil {
+		t.Fatal(err)
+	}
+	checkCode(t, seats.Claim(ctx, "karim"), apperr.CodeForbidden)
+
+	checkCode(t, seats.Release(cashier, "amina"), apperr.CodeForbidden)
+	if err := seats.Release(admin, "amina"); err != nil {
+		t.Fatal(err)
+	}
+	checkCode(t, seats.Release(admin, "amina"), apperr.CodeNotFound)
+
+	// the freed seat goes to the next user
+	if err := seats.Claim(ctx, "karim"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"barakaERP/backend/db"
+	apperr "barakaERP/backend/domain/errors"
+	"barakaERP/backend/i18n"
+	"barakaERP/backend/logging"
+)
+
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// SettingsService manages the application settings. It keeps the current
+// settings in memory for the other services and notifies listeners on change.
+type SettingsService struct {
+	repo      *db.Repository
+	mu        sync.RWMutex
+	current   db.AppSettings
+	listeners []func(db.AppSettings)
+}
+
+// NewSettingsService creates a settings service holding the defaults until Load
+func NewSettingsService(repo *db.Repository) *SettingsService {
+	return &SettingsService{repo: repo, current: db.DefaultAppSettings()}
+}
+
+// Load reads the stored settings
+func (s *SettingsService) Load(ctx context.Context) (*db.AppSettings, error) {
+	settings, err := s.repo.GetAppSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.current = *settings
+	s.mu.Unlock()
+	return settings, nil
+}
+
+// Get returns the current settings
+func (s *SettingsService) Get() db.AppSettings {
+	if s == nil {
+		return db.DefaultAppSettings()
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current
+}
+
+// Update validates and saves settings (admins only), then notifies listeners
+func (s *SettingsService) Update(ctx context.Context, settings db.AppSettings) (*db.AppSettings, error) {
+	if !db.ActorFromContext(ctx).IsAdmin() {
+		return nil, errAdminOnly
+	}
+	settings.DefaultCurrency = strings.ToUpper(strings.TrimSpace(settings.DefaultCurrency))
+	settings.LicenseServerURL = strings.TrimSpace(settings.LicenseServerURL)
+	settings.LogLevel = strings.ToLower(strings.TrimSpace(settings.LogLevel))
+	settings.Locale = strings.ToLower(strings.TrimSpace(settings.Locale))
+	settings.DocumentLocale = strings.ToLower(strings.TrimSpace(settings.DocumentLocale))
+	if settings.LogLevels == nil {
+		settings.LogLevels = map[string]string{}
+	}
+	if err := validateAppSettings(settings); err != nil {
+		return nil, err
+	}
+
+	saved, err := s.repo.UpdateAppSettings(ctx, settings)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.current = *saved
+	listeners := append([]func(db.AppSettings){}, s.listeners...)
+	s.mu.Unlock()
+	for _, fn := range listeners {
+		fn(*saved)
+	}
+	return saved, nil
+}
+
+// OnChange registers fn to be called with the new settings after each update
+func (s *SettingsService) OnChange(fn func(db.AppSettings)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, fn)
+}
+
+// Locale is the default locale of messages
+func (s *SettingsService) Locale() string {
+	return s.Get().Locale
+}
+
+// DocumentLocale is the default locale of printed documents
+func (s *SettingsService) DocumentLocale() string {
+	settings := s.Get()
+	if settings.DocumentLocale != "" {
+		return settings.DocumentLocale
+	}
+	return settings.Locale
+}
+
+// PageLimit applies the configured default and maximum page sizes to limit
+func (s *SettingsService) PageLimit(limit int) int {
+	settings := s.Get()
+	if limit <= 0 {
+		limit = settings.DefaultPageSize
+	}
+	if limit > settings.MaxPageSize {
+		limit = settings.MaxPageSize
+	}
+	return limit
+}
+
+// DefaultCurrency is the currency given to products and order items without one
+func (s *SettingsService) DefaultCurrency() string {
+	return s.Get().DefaultCurrency
+}
+
+func validateAppSettings(settings db.AppSettings) error {
+	if !currencyCode.MatchString(settings.DefaultCurrency) {
+		return apperr.Validation("default_currency", "invalid_currency") // Currency must be a 3-letter code
+	}
+	if settings.MaxPageSize < 1 || settings.MaxPageSize > 1000 {
+		return apperr.Validation("max_page_size", "invalid_max_page_size").With("max", 1000) // Max page size must be 1-1000
+	}
+	if settings.DefaultPageSize < 1 || settings.DefaultPageSize > settings.MaxPageSize {
+		return apperr.Validation("default_page_size", "invalid_default_page_size").With("max", settings.MaxPageSize) // Default page size must be 1-max
+	}
+	if settings.WindowWidth < 800 || settings.WindowHeight < 600 {
+		return apperr.Validation("window_width", "window_too_small") // Window must be at least 800x600
+	}
+	if _, err := logging.ParseLevel(settings.LogLevel); err != nil {
+		return apperr.Validation("log_level", "invalid_log_level").With("level", settings.LogLevel) // Invalid log level
+	}
+	for subsystem, level := range settings.LogLevels {
+		if !slices.Contains(logging.Subsystems, subsystem) {
+			return apperr.Validation("log_levels", "unknown_log_subsystem").With("subsystem", subsystem) // Unknown log subsystem
+		}
+		if _, err := logging.ParseLevel(level); err != nil {
+			return apperr.Validation("log_levels", "invalid_log_level").With("level", level) // Invalid log level
+		}
+	}
+	if i18n.Normalize(settings.Locale) != settings.Locale {
+		return apperr.Validation("locale", "invalid_locale").With("locale", settings.Locale)
+	}
+	if settings.DocumentLocale != "" && i18n.Normalize(settings.DocumentLocale) != settings.DocumentLocale {
+		return apperr.Validation("document_locale", "invalid_locale").With("locale", settings.DocumentLocale)
+	}
+	for role, max := range settings.DiscountCaps {
+		if role != db.RoleAdmin && role != db.RoleCashier {
+			return apperr.Validation("discount_caps", "unknown_role").With("role", role) // Unknown role
+		}
+		if max < 0 || max > 100 {
+			return apperr.Validation("discount_caps", "invalid_discount") // Discount percentage must be between 0 and 100
+		}
+	}
+	if settings.LicenseServerURL != "" {
+		u, err := url.Parse(settings.LicenseServerURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return apperr.Validation("license_server_url", "invalid_license_server_url") // Invalid license server URL
+		}
+	}
+	return nil
+}
+
+// LogLevels returns the configured default log level and per-subsystem levels
+func (s *SettingsService) LogLevels() (slog.Level, map[string]slog.Level) {
+	settings := s.Get()
+	defaultLevel, err := logging.ParseLevel(settings.LogLevel)
+	if err != nil {
+		defaultLevel = slog.LevelInfo
+	}
+	levels := map[string]slog.Level{}
+	for subsystem, name := range settings.LogLevels {
+		if level, err := logging.ParseLevel(name); err == nil {
+			levels[subsystem] = level
+		}
+	}
+	return defaultLevel, levels
+}
@@ -0,0 +1,35 @@
+import i18n from "./i18n";
+
+// AppError is what bound backend methods reject with (see formatError in app.go)
+export interface AppError {
+  code: "not_found" | "validation" | "conflict" | "forbidden" | "internal";
+  key: string;
+  field?: string;
+  params?: Record<string, unknown>;
+  message: string;
+}
+
+export function isAppError(err: unknown): err is AppError {
+  return (
+    typeof err === "object" && err !== null && "code" in err && "key" in err
+  );
+}
+
+// errorMessage localizes a backend error from the "errors" section of the
+// locale files, falling back to the backend's own message and then to fallback
+export function errorMessage(err: unknown, fallback: string): string {
+  if (isAppError(err)) {
+    const key = `errors.${err.key}`;
+    if (err.key && i18n.global.te(key)) {
+      return i18n.global.t(key, err.params ?? {});
+    }
+    return err.message || fallback;
+  }
+  if (err instanceof Error) {
+    return err.message;
+  }
+  if (typeof err === "string" && err) {
+    return err;
+  }
+  return fallback;
+}
@@ -1,5 +1,6 @@
 import { defineStore } from "pinia";
 import { ref, computed } from "vue";
+import { errorMessage } from "../errors";
 import {
   CreateClient,
   GetClients,
@@ -44,7 +45,7 @@ export const useClientStore = defineStore("clients", () => {
       };
     } catch (err) {
       console.error("Error fetching clients:", err);
-      error.value = err instanceof Error ? err.message : "حدث خطأ غير متوقع";
+      error.value = errorMessage(err, "حدث خطأ غير متوقع");
       throw err;
     } finally {
       loading.value = false;
@@ -67,7 +68,7 @@ export const useClientStore = defineStore("clients", () => {
       return result;
     } catch (err) {
       console.error("Error creating client:", err);
-      error.value = err instanceof Error ? err.message : "فشل في إنشاء العميل";
+      error.value = errorMessage(err, "فشل في إنشاء العميل");
       throw err;
     } finally {
       loading.value = false;
@@ -93,7 +94,7 @@ export const useClientStore = defineStore("clients", () => {
       return result;
     } catch (err) {
       console.error("Error updating client:", err);
-      error.value = err instanceof Error ? err.message : "فشل في تحديث العميل";
+      error.value = errorMessage(err, "فشل في تحديث العميل");
       throw err;
     } finally {
       loading.value = false;
@@ -109,7 +110,7 @@ export const useClientStore = defineStore("clients", () => {
       return result;
     } catch (err) {
       console.error("Error getting client:", err);
-      error.value = err instanceof Error ? err.message : "العميل غير موجود";
+      error.value = errorMessage(err, "العميل غير موجود");
       throw err;
     } finally {
       loading.value = false;
@@ -124,7 +125,7 @@ export const useClientStore = defineStore("clients", () => {
       clients.value = clients.value.filter((c) => c.id !== id);
     } catch (err) {
       console.error("Error deleting client:", err);
-      error.value = err instanceof Error ? err.message : "فشل في حذف العميل";
+      error.value = errorMessage(err, "فشل في حذف العميل");
       throw err;
     } finally {
       loading.value = false;
@@ -152,7 +153,7 @@ export const useClientStore = defineStore("clients", () => {
     } catch (err) {
       console.error("Error adjusting client debt:", err);
       error.value =
-        err instanceof Error ? err.message : "فشل في تعديل دين العميل";
+        errorMessage(err, "فشل في تعديل دين العميل");
       throw err;
     } finally {
       loading.value = false;
@@ -168,7 +169,7 @@ export const useClientStore = defineStore("clients", () => {
     } catch (err) {
       console.error("Error fetching debt payments:", err);
       error.value =
-        err instanceof Error ? err.message : "فشل في جلب سجلات المدفوعات";
+        errorMessage(err, "فشل في جلب سجلات المدفوعات");
       throw err;
     } finally {
       loading.value = false;
@@ -188,7 +189,7 @@ export const useClientStore = defineStore("clients", () => {
     } catch (err) {
       console.error("Error fetching client debt payments:", err);
       error.value =
-        err instanceof Error ? err.message : "فشل في جلب سجلات مدفوعات العميل";
+        errorMessage(err, "فشل في جلب سجلات مدفوعات العميل");
       throw err;
     } finally {
       loading.value = false;
@@ -1,4 +1,5 @@
 import { defineStore } from "pinia";
+import { errorMessage } from "../errors";
 import {
   CreateProduct,
   GetProducts,
@@ -44,7 +45,7 @@ export const useProductStore = defineStore("products", {
         this.loading = true;
         this.error = null;
 
-        const result = await GetProducts(search, limit, offset);
+        const result = await GetProducts(search, 0, limit, offset);
 
         // Update local state with all products for getters
         if (offset === 0) {
@@ -57,7 +58,7 @@ export const useProductStore = defineStore("products", {
         };
       } catch (error) {
         this.error =
-          error instanceof Error ? error.message : "Failed to fetch products";
+          errorMessage(error, "Failed to fetch products");
         throw error;
       } finally {
         this.loading = false;
@@ -85,7 +86,7 @@ export const useProductStore = defineStore("products", {
         return newProduct;
       } catch (error) {
         this.error =
-          error instanceof Error ? error.message : "Failed to create product";
+          errorMessage(error, "Failed to create product");
         throw error;
       } finally {
         this.loading = false;
@@ -119,7 +120,7 @@ export const useProductStore = defineStore("products", {
         return updatedProduct;
       } catch (error) {
         this.error =
-          error instanceof Error ? error.message : "Failed to update product";
+          errorMessage(error, "Failed to update product");
         throw error;
       } finally {
         this.loading = false;
@@ -134,7 +135,7 @@ export const useProductStore = defineStore("products", {
         this.products = this.products.filter((p) => p.id !== id);
       } catch (error) {
         this.error =
-          error instanceof Error ? error.message : "Failed to delete product";
+          errorMessage(error, "Failed to delete product");
         throw error;
       } finally {
         this.loading = false;
@@ -12,7 +12,7 @@ declare global {
           
           // Product operations
           CreateProduct(name: string, description: string, price: number, sku: string): Promise<any>
-          GetProducts(query: string, limit: number, offset: number): Promise<any>
+          GetProducts(query: string, categoryId: number, limit: number, offset: number): Promise<any>
           GetProduct(id: number): Promise<any>
           UpdateProduct(id: number, name: string, description: string, price: number, sku: string): Promise<any>
           
@@ -1,54 +1,167 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {db} from '../models';
+import {logging} from '../models';
 import {services} from '../models';
 
 export function AdjustClientDebt(arg1:number,arg2:number,arg3:string):Promise<db.Client>;
 
 export function CheckLicense():Promise<boolean>;
 
+export function CreateCategory(arg1:db.Category):Promise<db.Category>;
+
 export function CreateClient(arg1:string,arg2:string,arg3:string):Promise<db.Client>;
 
 export function CreateOrder(arg1:number,arg2:string,arg3:number,arg4:Array<Record<string, any>>):Promise<db.Order>;
 
+export function CreatePriceList(arg1:db.PriceList):Promise<db.PriceList>;
+
 export function CreateProduct(arg1:string,arg2:string,arg3:number,arg4:string):Promise<db.Product>;
 
 export function DebugSchema():Promise<Record<string, Array<string>>>;
 
+export function DeleteCategory(arg1:number):Promise<void>;
+
 export function DeleteClient(arg1:number):Promise<void>;
 
+export function DeleteDiscountRule(arg1:number):Promise<void>;
+
 export function DeleteOrder(arg1:number):Promise<void>;
 
+export function DeletePriceList(arg1:number):Promise<void>;
+
 export function DeleteProduct(arg1:number):Promise<void>;
 
+export function DeleteProductPrice(arg1:number):Promise<void>;
+
+export function DeleteProductUnit(arg1:number):Promise<void>;
+
 export function ExportOrderPDF(arg1:number):Promise<Array<number>>;
 
+export function ExportOrderPDFIn(arg1:number,arg2:string):Promise<Array<number>>;
+
+export function ExportOrderReceipt(arg1:number,arg2:string):Promise<Array<number>>;
+
+export function ExportOrdersPDF(arg1:db.OrderFilters,arg2:string):Promise<Array<number>>;
+
+export function GetAuditLog(arg1:string,arg2:number,arg3:string,arg4:string,arg5:number,arg6:number):Promise<db.PaginatedResult_barakaERP_backend_db_AuditEntry_>;
+
+export function GetCategories():Promise<Array<db.Category>>;
+
 export function GetClient(arg1:number):Promise<db.Client>;
 
 export function GetClientDebtPayments(arg1:number,arg2:number,arg3:number):Promise<db.PaginatedResult_barakaERP_backend_db_DebtPayment_>;
 
 export function GetClients(arg1:string,arg2:number,arg3:number):Promise<db.PaginatedResult_barakaERP_backend_db_Client_>;
 
+export function GetClientsAfter(arg1:string,arg2:string,arg3:number):Promise<db.PaginatedResult_barakaERP_backend_db_Client_>;
+
+export function GetCompanySettings():Promise<db.CompanySettings>;
+
+export function GetCurrentUser():Promise<db.Actor>;
+
 export function GetDashboardMetrics(arg1:string):Promise<db.DashboardData>;
 
 export function GetDebtPayments(arg1:number,arg2:number):Promise<db.PaginatedResult_barakaERP_backend_db_DebtPaymentDetail_>;
 
+export function GetDebtPaymentsAfter(arg1:string,arg2:number):Promise<db.PaginatedResult_barakaERP_backend_db_DebtPaymentDetail_>;
+
+export function GetDeletedClients(arg1:string,arg2:number,arg3:number):Promise<db.PaginatedResult_barakaERP_backend_db_Client_>;
+
+export function GetDeletedProducts(arg1:string,arg2:number,arg3:number):Promise<db.PaginatedResult_barakaERP_backend_db_Product_>;
+
+export function GetDiscountRules():Promise<Array<db.DiscountRule>>;
+
+export function GetLocale():Promise<string>;
+
 export function GetOrder(arg1:number):Promise<db.OrderDetail>;
 
 export function GetOrderStatuses():Promise<Array<string>>;
 
-export function GetOrders(arg1:string,arg2:number,arg3:string,arg4:number,arg5:number,arg6:string):Promise<db.PaginatedResult_barakaERP_backend_db_OrderDetail_>;
+export function GetOrders(arg1:db.OrderFilters,arg2:number,arg3:number):Promise<db.PaginatedResult_barakaERP_backend_db_OrderDetail_>;
+
+export function GetOrdersAfter(arg1:db.OrderFilters,arg2:string,arg3:number):Promise<db.PaginatedResult_barakaERP_backend_db_OrderDetail_>;
+
+export function GetPriceList(arg1:number):Promise<db.PriceListDetail>;
+
+export function GetPriceLists():Promise<Array<db.PriceList>>;
 
 export function GetProduct(arg1:number):Promise<db.Product>;
 
-export function GetProducts(arg1:string,arg2:number,arg3:number):Promise<db.PaginatedResult_barakaERP_backend_db_Product_>;
+export function GetProductUnits(arg1:number):Promise<Array<db.ProductUnit>>;
+
+export function GetProductVariants(arg1:number):Promise<Array<db.Product>>;
+
+export function GetProducts(arg1:string,arg2:number,arg3:number,arg4:number):Promise<db.PaginatedResult_barakaERP_backend_db_Product_>;
+
+export function GetProductsAfter(arg1:string,arg2:number,arg3:string,arg4:number):Promise<db.PaginatedResult_barakaERP_backend_db_Product_>;
+
+export function GetRecentLogs(arg1:string,arg2:string,arg3:number):Promise<Array<logging.Entry>>;
+
+export function GetSettings():Promise<db.AppSettings>;
+
+export function GlobalSearch(arg1:string):Promise<Array<db.SearchResult>>;
 
 export function Greet(arg1:string):Promise<string>;
 
+export function HasAdminPassword():Promise<boolean>;
+
+export function HasFeature(arg1:string):Promise<boolean>;
+
+export function InstallLicense(arg1:string):Promise<services.LicenseStatus>;
+
+export function ListLicensedUsers():Promise<Array<db.LicensedUser>>;
+
+export function LookupScannedDocument(arg1:string):Promise<db.ScannedDocument>;
+
+export function PurgeClient(arg1:number):Promise<void>;
+
+export function PurgeProduct(arg1:number):Promise<void>;
+
+export function QuotePrice(arg1:number,arg2:number,arg3:number,arg4:string):Promise<db.PriceQuote>;
+
+export function RebuildSearchIndex():Promise<void>;
+
+export function RefreshLicense():Promise<services.LicenseStatus>;
+
+export function ReleaseLicensedUser(arg1:string):Promise<void>;
+
+export function RestoreClient(arg1:number):Promise<db.Client>;
+
+export function RestoreProduct(arg1:number):Promise<db.Product>;
+
+export function SaveDiscountRule(arg1:db.DiscountRule):Promise<db.DiscountRule>;
+
+export function SaveProductUnit(arg1:db.ProductUnit):Promise<db.ProductUnit>;
+
+export function SaveProductVariant(arg1:db.Product):Promise<db.Product>;
+
+export function SetAdminPassword(arg1:string,arg2:string):Promise<void>;
+
+export function SetClientPriceList(arg1:number,arg2:number):Promise<db.Client>;
+
+export function SetCurrentUser(arg1:string,arg2:string,arg3:string):Promise<void>;
+
+export function SetLocale(arg1:string):Promise<void>;
+
+export function SetProductCategory(arg1:number,arg2:number):Promise<db.Product>;
+
+export function SetProductPrice(arg1:db.ProductPrice):Promise<db.ProductPrice>;
+
+export function SetProductStock(arg1:number,arg2:string):Promise<db.Product>;
+
+export function UpdateCategory(arg1:db.Category):Promise<db.Category>;
+
 export function UpdateClient(arg1:number,arg2:string,arg3:string,arg4:string,arg5:number):Promise<db.Client>;
 
+export function UpdateCompanySettings(arg1:db.CompanySettings):Promise<db.CompanySettings>;
+
 export function UpdateOrder(arg1:number,arg2:string,arg3:string,arg4:any,arg5:Array<Record<string, any>>):Promise<db.Order>;
 
+export function UpdatePriceList(arg1:db.PriceList):Promise<db.PriceList>;
+
 export function UpdateProduct(arg1:number,arg2:string,arg3:string,arg4:number,arg5:string):Promise<db.Product>;
 
+export function UpdateSettings(arg1:db.AppSettings):Promise<db.AppSettings>;
+
 export function ValidateLicense():Promise<services.LicenseStatus>;
@@ -10,6 +10,10 @@ export function CheckLicense() {
   return window['go']['main']['App']['CheckLicense']();
 }
 
+export function CreateCategory(arg1) {
+  return window['go']['main']['App']['CreateCategory'](arg1);
+}
+
 export function CreateClient(arg1, arg2, arg3) {
   return window['go']['main']['App']['CreateClient'](arg1, arg2, arg3);
 }
@@ -18,6 +22,10 @@ export function CreateOrder(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['CreateOrder'](arg1, arg2, arg3, arg4);
 }
 
+export function CreatePriceList(arg1) {
+  return window['go']['main']['App']['CreatePriceList'](arg1);
+}
+
 export function CreateProduct(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['CreateProduct'](arg1, arg2, arg3, arg4);
 }
@@ -26,22 +34,62 @@ export function DebugSchema() {
   return window['go']['main']['App']['DebugSchema']();
 }
 
+export function DeleteCategory(arg1) {
+  return window['go']['main']['App']['DeleteCategory'](arg1);
+}
+
 export function DeleteClient(arg1) {
   return window['go']['main']['App']['DeleteClient'](arg1);
 }
 
+export function DeleteDiscountRule(arg1) {
+  return window['go']['main']['App']['DeleteDiscountRule'](arg1);
+}
+
 export function DeleteOrder(arg1) {
   return window['go']['main']['App']['DeleteOrder'](arg1);
 }
 
+export function DeletePriceList(arg1) {
+  return window['go']['main']['App']['DeletePriceList'](arg1);
+}
+
 export function DeleteProduct(arg1) {
   return window['go']['main']['App']['DeleteProduct'](arg1);
 }
 
+export function DeleteProductPrice(arg1) {
+  return window['go']['main']['App']['DeleteProductPrice'](arg1);
+}
+
+export function DeleteProductUnit(arg1) {
+  return window['go']['main']['App']['DeleteProductUnit'](arg1);
+}
+
 export function ExportOrderPDF(arg1) {
   return window['go']['main']['App']['ExportOrderPDF'](arg1);
 }
 
+export function ExportOrderPDFIn(arg1, arg2) {
+  return window['go']['main']['App']['ExportOrderPDFIn'](arg1, arg2);
+}
+
+export function ExportOrderReceipt(arg1, arg2) {
+  return window['go']['main']['App']['ExportOrderReceipt'](arg1, arg2);
+}
+
+export function ExportOrdersPDF(arg1, arg2) {
+  return window['go']['main']['App']['ExportOrdersPDF'](arg1, arg2);
+}
+
+export function GetAuditLog(arg1, arg2, arg3, arg4, arg5, arg6) {
+  return window['go']['main']['App']['GetAuditLog'](arg1, arg2, arg3, arg4, arg5, arg6);
+}
+
+export function GetCategories() {
+  return window['go']['main']['App']['GetCategories']();
+}
+
 export function GetClient(arg1) {
   return window['go']['main']['App']['GetClient'](arg1);
 }
@@ -54,6 +102,18 @@ export function GetClients(arg1, arg2, arg3) {
   return window['go']['main']['App']['GetClients'](arg1, arg2, arg3);
 }
 
+export function GetClientsAfter(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetClientsAfter'](arg1, arg2, arg3);
+}
+
+export function GetCompanySettings() {
+  return window['go']['main']['App']['GetCompanySettings']();
+}
+
+export function GetCurrentUser() {
+  return window['go']['main']['App']['GetCurrentUser']();
+}
+
 export function GetDashboardMetrics(arg1) {
   return window['go']['main']['App']['GetDashboardMetrics'](arg1);
 }
@@ -62,6 +122,26 @@ export function GetDebtPayments(arg1, arg2) {
   return window['go']['main']['App']['GetDebtPayments'](arg1, arg2);
 }
 
+export function GetDebtPaymentsAfter(arg1, arg2) {
+  return window['go']['main']['App']['GetDebtPaymentsAfter'](arg1, arg2);
+}
+
+export function GetDeletedClients(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetDeletedClients'](arg1, arg2, arg3);
+}
+
+export function GetDeletedProducts(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetDeletedProducts'](arg1, arg2, arg3);
+}
+
+export function GetDiscountRules() {
+  return window['go']['main']['App']['GetDiscountRules']();
+}
+
+export function GetLocale() {
+  return window['go']['main']['App']['GetLocale']();
+}
+
 export function GetOrder(arg1) {
   return window['go']['main']['App']['GetOrder'](arg1);
 }
@@ -70,34 +150,178 @@ export function GetOrderStatuses() {
   return window['go']['main']['App']['GetOrderStatuses']();
 }
 
-export function GetOrders(arg1, arg2, arg3, arg4, arg5, arg6) {
-  return window['go']['main']['App']['GetOrders'](arg1, arg2, arg3, arg4, arg5, arg6);
+export function GetOrders(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetOrders'](arg1, arg2, arg3);
+}
+
+export function GetOrdersAfter(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetOrdersAfter'](arg1, arg2, arg3);
+}
+
+export function GetPriceList(arg1) {
+  return window['go']['main']['App']['GetPriceList'](arg1);
+}
+
+export function GetPriceLists() {
+  return window['go']['main']['App']['GetPriceLists']();
 }
 
 export function GetProduct(arg1) {
   return window['go']['main']['App']['GetProduct'](arg1);
 }
 
-export function GetProducts(arg1, arg2, arg3) {
-  return window['go']['main']['App']['GetProducts'](arg1, arg2, arg3);
+export function GetProductUnits(arg1) {
+  return window['go']['main']['App']['GetProductUnits'](arg1);
+}
+
+export function GetProductVariants(arg1) {
+  return window['go']['main']['App']['GetProductVariants'](arg1);
+}
+
+export function GetProducts(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['GetProducts'](arg1, arg2, arg3, arg4);
+}
+
+export function GetProductsAfter(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['GetProductsAfter'](arg1, arg2, arg3, arg4);
+}
+
+export function GetRecentLogs(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetRecentLogs'](arg1, arg2, arg3);
+}
+
+export function GetSettings() {
+  return window['go']['main']['App']['GetSettings']();
+}
+
+export function GlobalSearch(arg1) {
+  return window['go']['main']['App']['GlobalSearch'](arg1);
 }
 
 export function Greet(arg1) {
   return window['go']['main']['App']['Greet'](arg1);
 }
 
+export function HasAdminPassword() {
+  return window['go']['main']['App']['HasAdminPassword']();
+}
+
+export function HasFeature(arg1) {
+  return window['go']['main']['App']['HasFeature'](arg1);
+}
+
+export function InstallLicense(arg1) {
+  return window['go']['main']['App']['InstallLicense'](arg1);
+}
+
+export function ListLicensedUsers() {
+  return window['go']['main']['App']['ListLicensedUsers']();
+}
+
+export function LookupScannedDocument(arg1) {
+  return window['go']['main']['App']['LookupScannedDocument'](arg1);
+}
+
+export function PurgeClient(arg1) {
+  return window['go']['main']['App']['PurgeClient'](arg1);
+}
+
+export function PurgeProduct(arg1) {
+  return window['go']['main']['App']['PurgeProduct'](arg1);
+}
+
+export function QuotePrice(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['QuotePrice'](arg1, arg2, arg3, arg4);
+}
+
+export function RebuildSearchIndex() {
+  return window['go']['main']['App']['RebuildSearchIndex']();
+}
+
+export function RefreshLicense() {
+  return window['go']['main']['App']['RefreshLicense']();
+}
+
+export function ReleaseLicensedUser(arg1) {
+  return window['go']['main']['App']['ReleaseLicensedUser'](arg1);
+}
+
+export function RestoreClient(arg1) {
+  return window['go']['main']['App']['RestoreClient'](arg1);
+}
+
+export function RestoreProduct(arg1) {
+  return window['go']['main']['App']['RestoreProduct'](arg1);
+}
+
+export function SaveDiscountRule(arg1) {
+  return window['go']['main']['App']['SaveDiscountRule'](arg1);
+}
+
+export function SaveProductUnit(arg1) {
+  return window['go']['main']['App']['SaveProductUnit'](arg1);
+}
+
+export function SaveProductVariant(arg1) {
+  return window['go']['main']['App']['SaveProductVariant'](arg1);
+}
+
+export function SetAdminPassword(arg1, arg2) {
+  return window['go']['main']['App']['SetAdminPassword'](arg1, arg2);
+}
+
+export function SetClientPriceList(arg1, arg2) {
+  return window['go']['main']['App']['SetClientPriceList'](arg1, arg2);
+}
+
+export function SetCurrentUser(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetCurrentUser'](arg1, arg2, arg3);
+}
+
+export function SetLocale(arg1) {
+  return window['go']['main']['App']['SetLocale'](arg1);
+}
+
+export function SetProductCategory(arg1, arg2) {
+  return window['go']['main']['App']['SetProductCategory'](arg1, arg2);
+}
+
+export function SetProductPrice(arg1) {
+  return window['go']['main']['App']['SetProductPrice'](arg1);
+}
+
+export function SetProductStock(arg1, arg2) {
+  return window['go']['main']['App']['SetProductStock'](arg1, arg2);
+}
+
+export function UpdateCategory(arg1) {
+  return window['go']['main']['App']['UpdateCategory'](arg1);
+}
+
 export function UpdateClient(arg1, arg2, arg3, arg4, arg5) {
   return window['go']['main']['App']['UpdateClient'](arg1, arg2, arg3, arg4, arg5);
 }
 
+export function UpdateCompanySettings(arg1) {
+  return window['go']['main']['App']['UpdateCompanySettings'](arg1);
+}
+
 export function UpdateOrder(arg1, arg2, arg3, arg4, arg5) {
   return window['go']['main']['App']['UpdateOrder'](arg1, arg2, arg3, arg4, arg5);
 }
 
+export function UpdatePriceList(arg1) {
+  return window['go']['main']['App']['UpdatePriceList'](arg1);
+}
+
 export function UpdateProduct(arg1, arg2, arg3, arg4, arg5) {
   return window['go']['main']['App']['UpdateProduct'](arg1, arg2, arg3, arg4, arg5);
 }
 
+export function UpdateSettings(arg1) {
+  return window['go']['main']['App']['UpdateSettings'](arg1);
+}
+
 export function ValidateLicense() {
   return window['go']['main']['App']['ValidateLicense']();
 }
@@ -1,5 +1,138 @@
 export namespace db {
 	
+	export class Actor {
+	    user: string;
+	    role: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Actor(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.user = source["user"];
+	        this.role = source["role"];
+	    }
+	}
+	export class AppSettings {
+	    default_currency: string;
+	    default_page_size: number;
+	    max_page_size: number;
+	    license_server_url: string;
+	    window_width: number;
+	    window_height: number;
+	    log_level: string;
+	    log_levels: Record<string, string>;
+	    locale: string;
+	    document_locale?: string;
+	    enforce_list_prices: boolean;
+	    discount_caps: Record<string, number>;
+	
+	    static createFrom(source: any = {}) {
+	        return new AppSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.default_currency = source["default_currency"];
+	        this.default_page_size = source["default_page_size"];
+	        this.max_page_size = source["max_page_size"];
+	        this.license_server_url = source["license_server_url"];
+	        this.window_width = source["window_width"];
+	        this.window_height = source["window_height"];
+	        this.log_level = source["log_level"];
+	        this.log_levels = source["log_levels"];
+	        this.locale = source["locale"];
+	        this.document_locale = source["document_locale"];
+	        this.enforce_list_prices = source["enforce_list_prices"];
+	        this.discount_caps = source["discount_caps"];
+	    }
+	}
+	export class AuditEntry {
+	    id: number;
+	    entity: string;
+	    entity_id: number;
+	    action: string;
+	    before?: string;
+	    after?: string;
+	    user: string;
+	    // Go type: time
+	    created_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new AuditEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.entity = source["entity"];
+	        this.entity_id = source["entity_id"];
+	        this.action = source["action"];
+	        this.before = source["before"];
+	        this.after = source["after"];
+	        this.user = source["user"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Category {
+	    id: number;
+	    name: string;
+	    parent_id?: number;
+	    // Go type: time
+	    created_at: any;
+	    // Go type: time
+	    updated_at?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Category(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.parent_id = source["parent_id"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.updated_at = this.convertValues(source["updated_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class Client {
 	    id: number;
 	    name: string;
@@ -10,6 +143,9 @@ export namespace db {
 	    created_at: any;
 	    // Go type: time
 	    updated_at?: any;
+	    // Go type: time
+	    deleted_at?: any;
+	    price_list_id?: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new Client(source);
@@ -24,6 +160,61 @@ export namespace db {
 	        this.address = source["address"];
 	        this.created_at = this.convertValues(source["created_at"], null);
 	        this.updated_at = this.convertValues(source["updated_at"], null);
+	        this.deleted_at = this.convertValues(source["deleted_at"], null);
+	        this.price_list_id = source["price_list_id"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CompanySettings {
+	    name_ar: string;
+	    name_fr?: string;
+	    address?: string;
+	    phones: string[];
+	    rc?: string;
+	    nif?: string;
+	    nis?: string;
+	    ai?: string;
+	    logo: number[];
+	    logo_type?: string;
+	    footer_text?: string;
+	    // Go type: time
+	    updated_at?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new CompanySettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name_ar = source["name_ar"];
+	        this.name_fr = source["name_fr"];
+	        this.address = source["address"];
+	        this.phones = source["phones"];
+	        this.rc = source["rc"];
+	        this.nif = source["nif"];
+	        this.nis = source["nis"];
+	        this.ai = source["ai"];
+	        this.logo = source["logo"];
+	        this.logo_type = source["logo_type"];
+	        this.footer_text = source["footer_text"];
+	        this.updated_at = this.convertValues(source["updated_at"], null);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -193,6 +384,91 @@ export namespace db {
 		    return a;
 		}
 	}
+	export class DiscountRule {
+	    id: number;
+	    name: string;
+	    product_id?: number;
+	    category_id?: number;
+	    min_qty: number;
+	    percent: number;
+	    valid_from?: string;
+	    valid_to?: string;
+	    active: boolean;
+	    // Go type: time
+	    created_at: any;
+	    // Go type: time
+	    updated_at?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new DiscountRule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.product_id = source["product_id"];
+	        this.category_id = source["category_id"];
+	        this.min_qty = source["min_qty"];
+	        this.percent = source["percent"];
+	        this.valid_from = source["valid_from"];
+	        this.valid_to = source["valid_to"];
+	        this.active = source["active"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.updated_at = this.convertValues(source["updated_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LicensedUser {
+	    name: string;
+	    // Go type: time
+	    created_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new LicensedUser(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class Order {
 	    id: number;
 	    order_number: string;
@@ -254,8 +530,10 @@ export namespace db {
 	    name_snapshot: string;
 	    sku_snapshot?: string;
 	    qty: number;
+	    unit: string;
 	    unit_price_cents: number;
 	    discount_percent: number;
+	    discount_rule_id?: number;
 	    currency: string;
 	    total_cents: number;
 	
@@ -271,8 +549,10 @@ export namespace db {
 	        this.name_snapshot = source["name_snapshot"];
 	        this.sku_snapshot = source["sku_snapshot"];
 	        this.qty = source["qty"];
+	        this.unit = source["unit"];
 	        this.unit_price_cents = source["unit_price_cents"];
 	        this.discount_percent = source["discount_percent"];
+	        this.discount_rule_id = source["discount_rule_id"];
 	        this.currency = source["currency"];
 	        this.total_cents = source["total_cents"];
 	    }
@@ -319,10 +599,81 @@ export namespace db {
 		    return a;
 		}
 	}
+	export class OrderFilters {
+	    client_id?: number;
+	    status?: string;
+	    query?: string;
+	    sort?: string;
+	    issue_date_from?: string;
+	    issue_date_to?: string;
+	    due_date_from?: string;
+	    due_date_to?: string;
+	    min_total_cents?: number;
+	    max_total_cents?: number;
+	    product_id?: number;
+	    has_unpaid_invoice?: boolean;
+	    skip_items: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new OrderFilters(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.client_id = source["client_id"];
+	        this.status = source["status"];
+	        this.query = source["query"];
+	        this.sort = source["sort"];
+	        this.issue_date_from = source["issue_date_from"];
+	        this.issue_date_to = source["issue_date_to"];
+	        this.due_date_from = source["due_date_from"];
+	        this.due_date_to = source["due_date_to"];
+	        this.min_total_cents = source["min_total_cents"];
+	        this.max_total_cents = source["max_total_cents"];
+	        this.product_id = source["product_id"];
+	        this.has_unpaid_invoice = source["has_unpaid_invoice"];
+	        this.skip_items = source["skip_items"];
+	    }
+	}
+	
+	export class PaginatedResult_barakaERP_backend_db_AuditEntry_ {
+	    data: AuditEntry[];
+	    total: number;
+	    next_cursor?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PaginatedResult_barakaERP_backend_db_AuditEntry_(source);
+	    }
 	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.data = this.convertValues(source["data"], AuditEntry);
+	        this.total = source["total"];
+	        this.next_cursor = source["next_cursor"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class PaginatedResult_barakaERP_backend_db_Client_ {
 	    data: Client[];
 	    total: number;
+	    next_cursor?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new PaginatedResult_barakaERP_backend_db_Client_(source);
@@ -332,6 +683,7 @@ export namespace db {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.data = this.convertValues(source["data"], Client);
 	        this.total = source["total"];
+	        this.next_cursor = source["next_cursor"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -355,6 +707,7 @@ export namespace db {
 	export class PaginatedResult_barakaERP_backend_db_DebtPaymentDetail_ {
 	    data: DebtPaymentDetail[];
 	    total: number;
+	    next_cursor?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new PaginatedResult_barakaERP_backend_db_DebtPaymentDetail_(source);
@@ -364,6 +717,7 @@ export namespace db {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.data = this.convertValues(source["data"], DebtPaymentDetail);
 	        this.total = source["total"];
+	        this.next_cursor = source["next_cursor"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -387,6 +741,7 @@ export namespace db {
 	export class PaginatedResult_barakaERP_backend_db_DebtPayment_ {
 	    data: DebtPayment[];
 	    total: number;
+	    next_cursor?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new PaginatedResult_barakaERP_backend_db_DebtPayment_(source);
@@ -396,6 +751,7 @@ export namespace db {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.data = this.convertValues(source["data"], DebtPayment);
 	        this.total = source["total"];
+	        this.next_cursor = source["next_cursor"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -419,6 +775,7 @@ export namespace db {
 	export class PaginatedResult_barakaERP_backend_db_OrderDetail_ {
 	    data: OrderDetail[];
 	    total: number;
+	    next_cursor?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new PaginatedResult_barakaERP_backend_db_OrderDetail_(source);
@@ -428,6 +785,7 @@ export namespace db {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.data = this.convertValues(source["data"], OrderDetail);
 	        this.total = source["total"];
+	        this.next_cursor = source["next_cursor"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -456,10 +814,17 @@ export namespace db {
 	    unit_price_cents: number;
 	    currency: string;
 	    active: boolean;
+	    category_id?: number;
+	    unit: string;
+	    parent_id?: number;
+	    attributes: Record<string, string>;
+	    stock: number;
 	    // Go type: time
 	    created_at: any;
 	    // Go type: time
 	    updated_at?: any;
+	    // Go type: time
+	    deleted_at?: any;
 	
 	    static createFrom(source: any = {}) {
 	        return new Product(source);
@@ -474,8 +839,14 @@ export namespace db {
 	        this.unit_price_cents = source["unit_price_cents"];
 	        this.currency = source["currency"];
 	        this.active = source["active"];
+	        this.category_id = source["category_id"];
+	        this.unit = source["unit"];
+	        this.parent_id = source["parent_id"];
+	        this.attributes = source["attributes"];
+	        this.stock = source["stock"];
 	        this.created_at = this.convertValues(source["created_at"], null);
 	        this.updated_at = this.convertValues(source["updated_at"], null);
+	        this.deleted_at = this.convertValues(source["deleted_at"], null);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -499,6 +870,7 @@ export namespace db {
 	export class PaginatedResult_barakaERP_backend_db_Product_ {
 	    data: Product[];
 	    total: number;
+	    next_cursor?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new PaginatedResult_barakaERP_backend_db_Product_(source);
@@ -508,6 +880,234 @@ export namespace db {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.data = this.convertValues(source["data"], Product);
 	        this.total = source["total"];
+	        this.next_cursor = source["next_cursor"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PriceList {
+	    id: number;
+	    name: string;
+	    currency: string;
+	    // Go type: time
+	    created_at: any;
+	    // Go type: time
+	    updated_at?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new PriceList(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.currency = source["currency"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.updated_at = this.convertValues(source["updated_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ProductPrice {
+	    id: number;
+	    price_list_id: number;
+	    product_id: number;
+	    unit_price_cents: number;
+	    valid_from?: string;
+	    valid_to?: string;
+	    // Go type: time
+	    created_at: any;
+	    // Go type: time
+	    updated_at?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProductPrice(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.price_list_id = source["price_list_id"];
+	        this.product_id = source["product_id"];
+	        this.unit_price_cents = source["unit_price_cents"];
+	        this.valid_from = source["valid_from"];
+	        this.valid_to = source["valid_to"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.updated_at = this.convertValues(source["updated_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PriceListDetail {
+	    price_list: PriceList;
+	    prices: ProductPrice[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PriceListDetail(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.price_list = this.convertValues(source["price_list"], PriceList);
+	        this.prices = this.convertValues(source["prices"], ProductPrice);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PriceQuote {
+	    client_id: number;
+	    product_id: number;
+	    qty: number;
+	    unit: string;
+	    unit_price_cents: number;
+	    currency: string;
+	    total_cents: number;
+	    price_list_id?: number;
+	    price_list_name?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PriceQuote(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.client_id = source["client_id"];
+	        this.product_id = source["product_id"];
+	        this.qty = source["qty"];
+	        this.unit = source["unit"];
+	        this.unit_price_cents = source["unit_price_cents"];
+	        this.currency = source["currency"];
+	        this.total_cents = source["total_cents"];
+	        this.price_list_id = source["price_list_id"];
+	        this.price_list_name = source["price_list_name"];
+	    }
+	}
+	
+	
+	export class ProductUnit {
+	    id: number;
+	    product_id: number;
+	    unit: string;
+	    factor: number;
+	    // Go type: time
+	    created_at: any;
+	    // Go type: time
+	    updated_at?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProductUnit(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.product_id = source["product_id"];
+	        this.unit = source["unit"];
+	        this.factor = source["factor"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.updated_at = this.convertValues(source["updated_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ScannedDocument {
+	    kind: string;
+	    number: string;
+	    order?: OrderDetail;
+	    current: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScannedDocument(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.number = source["number"];
+	        this.order = this.convertValues(source["order"], OrderDetail);
+	        this.current = source["current"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -528,17 +1128,102 @@ export namespace db {
 		    return a;
 		}
 	}
+	export class SearchResult {
+	    type: string;
+	    id: number;
+	    title: string;
+	    subtitle: string;
+	    rank: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.id = source["id"];
+	        this.title = source["title"];
+	        this.subtitle = source["subtitle"];
+	        this.rank = source["rank"];
+	    }
+	}
+
+}
+
+export namespace logging {
 	
+	export class Entry {
+	    // Go type: time
+	    time: any;
+	    level: string;
+	    subsystem: string;
+	    message: string;
+	    attrs?: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new Entry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = this.convertValues(source["time"], null);
+	        this.level = source["level"];
+	        this.subsystem = source["subsystem"];
+	        this.message = source["message"];
+	        this.attrs = source["attrs"];
+	    }
 	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 
 }
 
 export namespace services {
 	
+	export class Entitlements {
+	    tier: string;
+	    features: string[];
+	    max_users: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Entitlements(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tier = source["tier"];
+	        this.features = source["features"];
+	        this.max_users = source["max_users"];
+	    }
+	}
 	export class LicenseStatus {
 	    is_valid: boolean;
+	    state: string;
 	    message: string;
+	    message_key: string;
+	    message_params?: Record<string, any>;
+	    customer?: string;
 	    expires_at?: string;
+	    grace_until?: string;
+	    entitlements: Entitlements;
+	    machine_id: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new LicenseStatus(source);
@@ -547,9 +1232,34 @@ export namespace services {
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.is_valid = source["is_valid"];
+	        this.state = source["state"];
 	        this.message = source["message"];
+	        this.message_key = source["message_key"];
+	        this.message_params = source["message_params"];
+	        this.customer = source["customer"];
 	        this.expires_at = source["expires_at"];
+	        this.grace_until = source["grace_until"];
+	        this.entitlements = this.convertValues(source["entitlements"], Entitlements);
+	        this.machine_id = source["machine_id"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 
 }
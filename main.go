@@ -25,6 +25,7 @@ func main() {
 		},
 		BackgroundColour: &options.RGBA{R: 255, G: 255, B: 255, A: 1},
 		OnStartup:        app.startup,
+		ErrorFormatter:   app.formatError,
 		Bind: []interface{}{
 			app,
 		},